@@ -16,6 +16,7 @@ package datanode
 
 import (
 	"fmt"
+	"io/ioutil"
 	"path"
 	"regexp"
 	"strconv"
@@ -44,6 +45,7 @@ type Disk struct {
 	Path        string
 	ReadErrCnt  uint64 // number of read errors
 	WriteErrCnt uint64 // number of write errors
+	WrittenSize uint64 // cumulative bytes written to the disk, persisted in DiskWrittenSizeFile
 
 	Total       uint64
 	Used        uint64
@@ -56,6 +58,7 @@ type Disk struct {
 	ReservedSpace uint64
 
 	RejectWrite                               bool
+	persistedWrittenSize                      uint64
 	partitionMap                              map[uint64]*DataPartition
 	syncTinyDeleteRecordFromLeaderOnEveryDisk chan bool
 	space                                     *SpaceManager
@@ -78,6 +81,9 @@ func NewDisk(path string, reservedSpace uint64, maxErrCnt int, space *SpaceManag
 	d.dataNode = space.dataNode
 	d.partitionMap = make(map[uint64]*DataPartition)
 	d.syncTinyDeleteRecordFromLeaderOnEveryDisk = make(chan bool, SyncTinyDeleteRecordFromLeaderOnEveryDisk)
+	if err := d.loadWrittenSize(); err != nil {
+		log.LogErrorf("action[NewDisk] disk(%v) load written size err(%v)", d.Path, err)
+	}
 	d.computeUsage()
 	d.updateSpaceInfo()
 	d.startScheduleToUpdateSpaceInfo()
@@ -153,13 +159,19 @@ func (d *Disk) incWriteErrCnt() {
 	atomic.AddUint64(&d.WriteErrCnt, 1)
 }
 
+func (d *Disk) addWrittenSize(size uint64) {
+	atomic.AddUint64(&d.WrittenSize, size)
+}
+
 func (d *Disk) startScheduleToUpdateSpaceInfo() {
 	go func() {
 		updateSpaceInfoTicker := time.NewTicker(5 * time.Second)
 		checkStatusTickser := time.NewTicker(time.Minute * 2)
+		persistWrittenSizeTicker := time.NewTicker(time.Minute)
 		defer func() {
 			updateSpaceInfoTicker.Stop()
 			checkStatusTickser.Stop()
+			persistWrittenSizeTicker.Stop()
 		}()
 		for {
 			select {
@@ -168,11 +180,61 @@ func (d *Disk) startScheduleToUpdateSpaceInfo() {
 				d.updateSpaceInfo()
 			case <-checkStatusTickser.C:
 				d.checkDiskStatus()
+			case <-persistWrittenSizeTicker.C:
+				if err := d.persistWrittenSize(); err != nil {
+					log.LogErrorf("action[persistWrittenSize] disk(%v) err(%v)", d.Path, err)
+				}
 			}
 		}
 	}()
 }
 
+// persistWrittenSize stores the bytes written to the disk, so that the wear of the disk survives a restart.
+func (d *Disk) persistWrittenSize() (err error) {
+	writtenSize := atomic.LoadUint64(&d.WrittenSize)
+	if writtenSize == atomic.LoadUint64(&d.persistedWrittenSize) {
+		return
+	}
+	filename := path.Join(d.Path, TempDiskWrittenSizeFile)
+	fp, err := os.OpenFile(filename, os.O_RDWR|os.O_APPEND|os.O_TRUNC|os.O_CREATE, 0755)
+	if err != nil {
+		return
+	}
+	defer func() {
+		fp.Close()
+		os.Remove(filename)
+	}()
+	if _, err = fp.WriteString(fmt.Sprintf("%d", writtenSize)); err != nil {
+		return
+	}
+	fp.Sync()
+	if err = os.Rename(filename, path.Join(d.Path, DiskWrittenSizeFile)); err != nil {
+		return
+	}
+	atomic.StoreUint64(&d.persistedWrittenSize, writtenSize)
+	return
+}
+
+// loadWrittenSize loads the bytes written to the disk before the data node restarted.
+func (d *Disk) loadWrittenSize() (err error) {
+	filename := path.Join(d.Path, DiskWrittenSizeFile)
+	if _, err = os.Stat(filename); err != nil {
+		err = nil
+		return
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	var writtenSize uint64
+	if _, err = fmt.Sscanf(string(data), "%d", &writtenSize); err != nil {
+		return
+	}
+	atomic.StoreUint64(&d.WrittenSize, writtenSize)
+	atomic.StoreUint64(&d.persistedWrittenSize, writtenSize)
+	return
+}
+
 func (d *Disk) doBackendTask() {
 	for {
 		partitions := make([]*DataPartition, 0)
@@ -189,7 +251,9 @@ func (d *Disk) doBackendTask() {
 }
 
 const (
-	DiskStatusFile = ".diskStatus"
+	DiskStatusFile          = ".diskStatus"
+	DiskWrittenSizeFile     = "WRITTEN"
+	TempDiskWrittenSizeFile = ".written"
 )
 
 func (d *Disk) checkDiskStatus() {
@@ -240,6 +240,7 @@ func (dp *DataPartition) ApplyRandomWrite(command []byte, raftApplyID uint64) (r
 	for i := 0; i < 20; i++ {
 		err = dp.ExtentStore().Write(opItem.extentID, opItem.offset, opItem.size, opItem.data, opItem.crc, storage.RandomWriteType, opItem.opcode == proto.OpSyncRandomWrite)
 		if err == nil {
+			dp.disk.addWrittenSize(uint64(opItem.size))
 			break
 		}
 		if IsDiskErr(err.Error()) {
@@ -68,7 +68,7 @@ func (s *DataNode) getDiskAPI(w http.ResponseWriter, r *http.Request) {
 
 func (s *DataNode) getStatAPI(w http.ResponseWriter, r *http.Request) {
 	response := &proto.DataNodeHeartbeatResponse{}
-	s.buildHeartBeatResponse(response)
+	s.buildHeartBeatResponse(response, true)
 
 	s.buildSuccessResp(w, response)
 }
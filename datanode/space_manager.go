@@ -17,6 +17,7 @@ package datanode
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"math"
@@ -42,6 +43,7 @@ type SpaceManager struct {
 	diskList             []string
 	dataNode             *DataNode
 	createPartitionMutex sync.RWMutex
+	decommissionedDisks  map[string]bool // the disks decommissioned by the master, no partition is created on them
 }
 
 // NewSpaceManager creates a new space manager.
@@ -93,6 +95,11 @@ func (manager *SpaceManager) Stop() {
 		}(partitionC)
 	}
 	wg.Wait()
+	for _, d := range manager.GetDisks() {
+		if err := d.persistWrittenSize(); err != nil {
+			log.LogErrorf("action[Stop] disk(%v) persist written size err(%v)", d.Path, err)
+		}
+	}
 }
 
 func (manager *SpaceManager) SetNodeID(nodeID uint64) {
@@ -228,7 +235,7 @@ func (manager *SpaceManager) minPartitionCnt() (d *Disk) {
 	)
 	minWeight = math.MaxFloat64
 	for _, disk := range manager.disks {
-		if disk.Status != proto.ReadWrite {
+		if disk.Status != proto.ReadWrite || manager.decommissionedDisks[disk.Path] {
 			continue
 		}
 		diskWeight := disk.getSelectWeight()
@@ -246,6 +253,18 @@ func (manager *SpaceManager) minPartitionCnt() (d *Disk) {
 	d = minWeightDisk
 	return d
 }
+
+// The decommissioned disks are sent by the master with each heartbeat.
+func (manager *SpaceManager) setDecommissionedDisks(paths []string) {
+	disks := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		disks[path] = true
+	}
+	manager.diskMutex.Lock()
+	manager.decommissionedDisks = disks
+	manager.diskMutex.Unlock()
+}
+
 func (manager *SpaceManager) statUpdateScheduler() {
 	go func() {
 		ticker := time.NewTicker(10 * time.Second)
@@ -333,7 +352,8 @@ func (manager *SpaceManager) DeletePartition(dpID uint64) {
 	os.RemoveAll(dp.Path())
 }
 
-func (s *DataNode) buildHeartBeatResponse(response *proto.DataNodeHeartbeatResponse) {
+// The per-disk stats are reported only if verbose is set.
+func (s *DataNode) buildHeartBeatResponse(response *proto.DataNodeHeartbeatResponse, verbose bool) {
 	response.Status = proto.TaskSucceeds
 	stat := s.space.Stats()
 	stat.Lock()
@@ -362,6 +382,7 @@ func (s *DataNode) buildHeartBeatResponse(response *proto.DataNodeHeartbeatRespo
 			IsLeader:        isLeader,
 			ExtentCount:     partition.GetExtentCount(),
 			NeedCompare:     true,
+			ApplyID:         partition.GetAppliedID(),
 		}
 		log.LogDebugf("action[Heartbeats] dpid(%v), status(%v) total(%v) used(%v) leader(%v) isLeader(%v).", vr.PartitionID, vr.PartitionStatus, vr.Total, vr.Used, leaderAddr, vr.IsLeader)
 		response.PartitionReports = append(response.PartitionReports, vr)
@@ -369,9 +390,25 @@ func (s *DataNode) buildHeartBeatResponse(response *proto.DataNodeHeartbeatRespo
 	})
 
 	disks := space.GetDisks()
+	response.DiskWrittenSizes = make(map[string]uint64, len(disks))
 	for _, d := range disks {
 		if d.Status == proto.Unavailable {
 			response.BadDisks = append(response.BadDisks, d.Path)
 		}
+		response.DiskWrittenSizes[d.Path] = atomic.LoadUint64(&d.WrittenSize)
+		if !verbose {
+			continue
+		}
+		response.DiskReports = append(response.DiskReports, &proto.DiskReport{
+			Path:           d.Path,
+			Status:         d.Status,
+			Total:          d.Total,
+			Used:           d.Used,
+			Available:      d.Available,
+			ReadErrCnt:     atomic.LoadUint64(&d.ReadErrCnt),
+			WriteErrCnt:    atomic.LoadUint64(&d.WriteErrCnt),
+			WrittenSize:    atomic.LoadUint64(&d.WrittenSize),
+			PartitionCount: d.PartitionCount(),
+		})
 	}
 }
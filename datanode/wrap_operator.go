@@ -236,11 +236,12 @@ func (s *DataNode) handleHeartbeatPacket(p *repl.Packet) {
 	go func() {
 		request := &proto.HeartBeatRequest{}
 		response := &proto.DataNodeHeartbeatResponse{}
-		s.buildHeartBeatResponse(response)
 
 		if task.OpCode == proto.OpDataNodeHeartbeat {
 			marshaled, _ := json.Marshal(task.Request)
 			_ = json.Unmarshal(marshaled, request)
+			s.space.setDecommissionedDisks(request.DecommissionedDisks)
+			s.buildHeartBeatResponse(response, request.Verbose)
 			response.Status = proto.TaskSucceeds
 		} else {
 			response.Status = proto.TaskFailed
@@ -442,6 +443,9 @@ func (s *DataNode) handleWritePacket(p *repl.Packet) {
 			s.metrics.MetricIOBytes.AddWithLabels(int64(p.Size), metricPartitionIOLabels)
 			partitionIOMetric.SetWithLabels(err, metricPartitionIOLabels)
 		}
+		if err == nil {
+			partition.disk.addWrittenSize(uint64(p.Size))
+		}
 		s.incDiskErrCnt(p.PartitionID, err, WriteFlag)
 		return
 	}
@@ -482,6 +486,9 @@ func (s *DataNode) handleWritePacket(p *repl.Packet) {
 			offset += currSize
 		}
 	}
+	if err == nil {
+		partition.disk.addWrittenSize(uint64(p.Size))
+	}
 	s.incDiskErrCnt(p.PartitionID, err, WriteFlag)
 	return
 }
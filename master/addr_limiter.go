@@ -0,0 +1,80 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// The bucket of an address idle this long is dropped. It has been full again for long,
+// so the address gets the same bucket back if it comes again.
+const addrLimiterIdleTimeout = time.Minute
+
+type addrLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// addrLimiter keeps a token bucket for each source address, so that a misbehaving node
+// can not flood the master while the other nodes are not throttled.
+type addrLimiter struct {
+	sync.Mutex
+	qps       int
+	limiters  map[string]*addrLimiterEntry
+	lastEvict time.Time
+}
+
+func newAddrLimiter(qps int) *addrLimiter {
+	return &addrLimiter{qps: qps, limiters: make(map[string]*addrLimiterEntry), lastEvict: time.Now()}
+}
+
+// The port is stripped from the remote address, the connections of a node come from random ports.
+func (l *addrLimiter) allow(remoteAddr string) bool {
+	return l.allowAt(remoteAddr, time.Now())
+}
+
+func (l *addrLimiter) allowAt(remoteAddr string, now time.Time) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	l.Lock()
+	if now.Sub(l.lastEvict) >= addrLimiterIdleTimeout {
+		l.evictIdle(now)
+	}
+	entry, ok := l.limiters[host]
+	if !ok {
+		entry = &addrLimiterEntry{limiter: rate.NewLimiter(rate.Limit(l.qps), l.qps)}
+		l.limiters[host] = entry
+	}
+	entry.lastSeen = now
+	l.Unlock()
+	return entry.limiter.AllowN(now, 1)
+}
+
+// drop the buckets of the addresses idle for addrLimiterIdleTimeout, so that the addresses
+// which are gone, such as the removed nodes and the one-off clients, are not kept forever
+func (l *addrLimiter) evictIdle(now time.Time) {
+	for host, entry := range l.limiters {
+		if now.Sub(entry.lastSeen) >= addrLimiterIdleTimeout {
+			delete(l.limiters, host)
+		}
+	}
+	l.lastEvict = now
+}
@@ -0,0 +1,200 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// The APIs changing the cluster state, which require the admin token if it is configured.
+var mutatingAPIs = map[string]bool{
+	proto.AdminLoadDataPartition:         true,
+	proto.AdminVerifyDataPartition:       true,
+	proto.AdminCreateDataPartition:       true,
+	proto.AdminDecommissionDataPartition: true,
+	proto.AdminBatchDecommissionDps:      true,
+	proto.AdminMigrateDataPartition:      true,
+	proto.AdminRebalanceDataPartitions:   true,
+	proto.AdminDeleteDataReplica:         true,
+	proto.AdminAddDataReplica:            true,
+	proto.AdminSetLogLevel:               true,
+	proto.AdminDeleteVol:                 true,
+	proto.AdminUpdateVol:                 true,
+	proto.AdminVolShrink:                 true,
+	proto.AdminVolExpand:                 true,
+	proto.AdminImportVol:                 true,
+	proto.AdminSetVolAllocPriority:       true,
+	proto.AdminSetVolIOPriority:          true,
+	proto.AdminSetVolReadOnly:            true,
+	proto.AdminUndeleteVol:               true,
+	proto.AdminRenameVol:                 true,
+	proto.AdminCreateVol:                 true,
+	proto.AdminBatchCreateVol:            true,
+	proto.AdminClusterFreeze:             true,
+	proto.AdminSetMaintenance:            true,
+	proto.AdminSetAllocationStrategy:     true,
+	proto.AdminSetMaxVolumes:             true,
+	proto.AdminSetDefaultZone:            true,
+	proto.AdminCreateNodeSet:             true,
+	proto.AdminMoveNodeToNodeSet:         true,
+	proto.AdminSetAutoRebalancePolicy:    true,
+	proto.AdminSetAutoDecommission:       true,
+	proto.AdminSetHeartbeatVerbosity:     true,
+	proto.AdminSetAutoAllocThreshold:     true,
+	proto.AdminBatchSetNodeTags:          true,
+	proto.AdminReservePartitionIDs:       true,
+	proto.AdminCreateMetaPartition:       true,
+	proto.AdminSetMetaNodeThreshold:      true,
+	proto.AdminSetNodeInfo:               true,
+	proto.AdminUpdateNodeSetCapcity:      true,
+	proto.AdminUpdateNodeSetId:           true,
+	proto.AdminUpdateDomainDataUseRatio:  true,
+	proto.AdminUpdateZoneExcludeRatio:    true,
+	proto.AdminSetNodeRdOnly:             true,
+	proto.AddRaftNode:                    true,
+	proto.RemoveRaftNode:                 true,
+	proto.TransferRaftLeader:             true,
+	proto.TryToRaftLeader:                true,
+	proto.SnapshotRaftLog:                true,
+	proto.StepDownRaftLeader:             true,
+	proto.DecommissionDataNode:           true,
+	proto.MigrateDataNode:                true,
+	proto.CancelDecommissionDataNode:     true,
+	proto.DecommissionDisk:               true,
+	proto.CancelDecommissionDisk:         true,
+	proto.RecommissionDisk:               true,
+	proto.DecommissionMetaNode:           true,
+	proto.MigrateMetaNode:                true,
+	proto.AdminUpdateMetaNode:            true,
+	proto.AdminUpdateDataNode:            true,
+	proto.AdminLoadMetaPartition:         true,
+	proto.AdminDecommissionMetaPartition: true,
+	proto.AdminSplitMetaPartition:        true,
+	proto.AdminAddMetaReplica:            true,
+	proto.AdminDeleteMetaReplica:         true,
+	proto.UpdateZone:                     true,
+	proto.UserCreate:                     true,
+	proto.UserDelete:                     true,
+	proto.UserUpdate:                     true,
+	proto.UserUpdatePolicy:               true,
+	proto.UserRemovePolicy:               true,
+	proto.UserDeleteVolPolicy:            true,
+	proto.UserTransferVol:                true,
+}
+
+// The APIs which never require the admin token, they are called by the data nodes, the meta nodes and the clients,
+// or authenticate the users by themselves like the graphql APIs.
+var tokenExemptAPIs = map[string]bool{
+	proto.AdminGetIP:              true,
+	proto.AdminMetrics:            true,
+	proto.AdminLivez:              true,
+	proto.AdminReadyz:             true,
+	proto.AddDataNode:             true,
+	proto.AddMetaNode:             true,
+	proto.GetDataNodeTaskResponse: true,
+	proto.GetMetaNodeTaskResponse: true,
+	proto.ClientDataPartitions:    true,
+	proto.ClientVol:               true,
+	proto.ClientMetaPartition:     true,
+	proto.ClientVolStat:           true,
+	proto.ClientMetaPartitions:    true,
+	proto.AdminClusterAPI:         true,
+	proto.AdminUserAPI:            true,
+	proto.AdminVolumeAPI:          true,
+}
+
+// Return the path of the route matched by the request.
+func apiPath(r *http.Request) (path string) {
+	path = r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			path = tpl
+		}
+	}
+	return
+}
+
+func isMutatingAPI(r *http.Request) bool {
+	return mutatingAPIs[apiPath(r)]
+}
+
+// The mutating APIs still served in the maintenance mode, so that it can be turned off.
+var maintenanceExemptAPIs = map[string]bool{
+	proto.AdminSetMaintenance: true,
+}
+
+// Reject the mutating APIs with 503 while the cluster is in maintenance. The read APIs, as well as the registration,
+// the heartbeats and the task responses of the nodes, are served as usual.
+func (m *Server) rejectInMaintenance(w http.ResponseWriter, r *http.Request) bool {
+	if !m.cluster.MaintenanceMode || !isMutatingAPI(r) || maintenanceExemptAPIs[apiPath(r)] {
+		return false
+	}
+	log.LogWarnf("action[rejectInMaintenance] reject path[%v] remoteAddr[%v] requestID[%v], cluster in maintenance",
+		r.URL.Path, r.RemoteAddr, requestID(r))
+	sendErrReplyWithStatus(w, r, http.StatusServiceUnavailable, newErrHTTPReply(proto.ErrClusterInMaintenance))
+	return true
+}
+
+func (m *Server) needAdminToken(r *http.Request) bool {
+	if m.config.adminToken == "" {
+		return false
+	}
+	if isMutatingAPI(r) {
+		return true
+	}
+	return m.config.secureRead && !tokenExemptAPIs[apiPath(r)]
+}
+
+// The token is taken from the Authorization header, with or without the Bearer scheme,
+// or from the token query parameter, or from the token of the urlencoded form body.
+// The form body parsed here is kept in r.PostForm for the handlers, the other bodies are not read.
+func extractAdminToken(r *http.Request) (token string) {
+	if token = r.Header.Get(proto.HeadAuthorized); token != "" {
+		return strings.TrimSpace(strings.TrimPrefix(token, "Bearer "))
+	}
+	if token = r.URL.Query().Get(tokenKey); token != "" {
+		return
+	}
+	return r.PostFormValue(tokenKey)
+}
+
+func (m *Server) registerAuthMiddleware(route *mux.Router) {
+	var authenticator mux.MiddlewareFunc = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if !m.needAdminToken(r) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				token := extractAdminToken(r)
+				if subtle.ConstantTimeCompare([]byte(token), []byte(m.config.adminToken)) != 1 {
+					log.LogWarnf("action[authenticator] reject path[%v] remoteAddr[%v] requestID[%v], admin token is missing or wrong",
+						r.URL.Path, r.RemoteAddr, requestID(r))
+					sendErrReplyWithStatus(w, r, http.StatusUnauthorized,
+						&proto.HTTPReply{Code: proto.ErrCodeNoPermission, Msg: "admin token is missing or wrong"})
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+	}
+	route.Use(authenticator)
+}
@@ -17,9 +17,13 @@ package master
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
-	"regexp"
+	"net/url"
+	"path"
+	"sort"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -52,7 +56,9 @@ type InvalidNodeView struct {
 
 // TopologyView provides the view of the topology view of the cluster
 type TopologyView struct {
-	Zones []*ZoneView
+	DataNodeTotal int // the number of the data nodes kept by the filter, the page lists some of them
+	MetaNodeTotal int
+	Zones         []*ZoneView
 }
 
 type NodeSetView struct {
@@ -97,14 +103,14 @@ func (m *Server) setMetaNodeThreshold(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set threshold to %v successfully", threshold)))
 }
 
-// Turn on or off the automatic allocation of the data partitions.
-// If DisableAutoAllocate == off, then we WILL NOT automatically allocate new data partitions for the volume when:
-// 	1. the used space is below the max capacity,
-//	2. and the number of r&w data partition is less than 20.
+// Turn on or off the automatic allocation of the data partitions, enable=true freezes the cluster.
+// If DisableAutoAllocate == off, then we WILL automatically allocate new data partitions for the volume when:
+// 	1. the used space is below the ratio of the capacity, 1 by default,
+//	2. and the number of r&w data partition is less than the threshold, 10 by default.
 //
-// If DisableAutoAllocate == on, then we WILL automatically allocate new data partitions for the volume when:
-// 	1. the used space is below the max capacity,
-//	2. and the number of r&w data partition is less than 20.
+// The ratio and the threshold are set by setAutoAllocThreshold.
+//
+// If DisableAutoAllocate == on, then we WILL NOT automatically allocate new data partitions for the volume.
 func (m *Server) setupAutoAllocation(w http.ResponseWriter, r *http.Request) {
 	var (
 		status bool
@@ -118,38 +124,313 @@ func (m *Server) setupAutoAllocation(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
-	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set DisableAutoAllocate to %v successfully", status)))
+	autoAlloc := "enabled"
+	if status {
+		autoAlloc = "disabled"
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf(
+		"set DisableAutoAllocate to %v successfully, the automatic allocation of data partitions is %v", status, autoAlloc)))
+}
+
+// Set the thresholds of the automatic allocation of the data partitions, the parameters absent keep their values.
+func (m *Server) setAutoAllocThreshold(w http.ResponseWriter, r *http.Request) {
+	var (
+		minRWDataPartitions int
+		usedRatio           float64
+		err                 error
+	)
+	if minRWDataPartitions, usedRatio, err = parseRequestToSetAutoAllocThreshold(r, m.cluster.getMinRWDataPartitions(),
+		m.cluster.getAutoAllocUsedRatio()); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() {
+		m.cluster.addAuditEvent("setAutoAllocThreshold", r.RemoteAddr, fmt.Sprintf("%v:%v", minRWDataPartitions, usedRatio), err)
+	}()
+	if err = m.cluster.setAutoAllocThreshold(minRWDataPartitions, usedRatio); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set MinRWDataPartitions to %v and AutoAllocUsedRatio to %v successfully",
+		minRWDataPartitions, usedRatio)))
+}
+
+// Set the max number of volumes in the cluster, createVol is rejected beyond the limit. 0 means unlimited.
+func (m *Server) setMaxVolumes(w http.ResponseWriter, r *http.Request) {
+	var (
+		maxVolumes uint64
+		err        error
+	)
+	if maxVolumes, err = parseRequestToSetMaxVolumes(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.setMaxVolumes(maxVolumes); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set MaxVolumes to %v successfully, current volume count is %v",
+		maxVolumes, m.cluster.volCount())))
+}
+
+// Set the zone of the volumes which are created without zone constraint.
+func (m *Server) setDefaultZone(w http.ResponseWriter, r *http.Request) {
+	var (
+		zoneName string
+		err      error
+	)
+	if zoneName, err = parseAndExtractZoneName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.setDefaultZone(zoneName); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set DefaultZone to %v successfully", zoneName)))
+}
+
+func (m *Server) getClusterConfig(w http.ResponseWriter, r *http.Request) {
+	sendOkReply(w, r, newSuccessHTTPReply(m.buildClusterConfigView()))
+}
+
+func (m *Server) buildClusterConfigView() (cv *proto.ClusterConfigView) {
+	return &proto.ClusterConfigView{
+		DefaultZone:        m.cluster.getDefaultZone(),
+		AllocationStrategy: getDataNodeAllocStrategy(),
+		MaxVolumes:         atomic.LoadUint64(&m.cluster.MaxVolumes),
+		DisableAutoAlloc:   m.cluster.DisableAutoAllocate,
+		FaultDomain:        m.cluster.FaultDomain,
+		HeartbeatVerbosity: m.cluster.getHeartbeatVerbosity(),
+	}
+}
+
+// Set the placement strategy of the data partitions, the supported strategies are:
+// 	1. default, the data nodes are weighted by the available space,
+//	2. wearLeveling, the available space weight is reduced by the bytes written to the disks of the data node.
+func (m *Server) setAllocationStrategy(w http.ResponseWriter, r *http.Request) {
+	var (
+		strategy string
+		err      error
+	)
+	if strategy, err = parseAndExtractAllocStrategy(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.setAllocationStrategy(strategy); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set AllocationStrategy to %v successfully", strategy)))
+}
+
+// Query the admin actions performed by a remote address during the given time range.
+// The audit events are kept in memory on the master that handled the requests.
+func (m *Server) getAuditByAddr(w http.ResponseWriter, r *http.Request) {
+	var (
+		addr      string
+		startTime int64
+		endTime   int64
+		err       error
+	)
+	if addr, startTime, endTime, err = parseRequestToGetAuditByAddr(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getAuditEventsByAddr(addr, startTime, endTime)))
+}
+
+// Query the most recent admin actions, of all the actions or of the given one.
+func (m *Server) getAuditLog(w http.ResponseWriter, r *http.Request) {
+	var (
+		count  int
+		action string
+		err    error
+	)
+	if count, action, err = parseRequestToGetAuditLog(r, m.config.auditLogCapacity); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getRecentAuditEvents(count, action)))
+}
+
+// Stream the cluster events as server-sent events, one JSON event per message, until the client goes away.
+// The clients reconnect to get the events after a disconnection, the events in between are not replayed.
+func (m *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendErrReply(w, r, newErrHTTPReply(fmt.Errorf("streaming is not supported")))
+		return
+	}
+	events, unsubscribe := m.cluster.events.subscribe()
+	defer unsubscribe()
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(eventKeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.LogErrorf("action[streamEvents] marshal event type[%v] err[%v]", event.Type, err)
+				continue
+			}
+			if _, err = fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				log.LogInfof("action[streamEvents] remoteAddr[%v] requestID[%v] err[%v]", r.RemoteAddr, requestID(r), err)
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// Change the log level of the master handling the request at runtime, the other masters are not changed.
+func (m *Server) setLogLevel(w http.ResponseWriter, r *http.Request) {
+	var (
+		level log.Level
+		err   error
+	)
+	defer func() { m.cluster.addAuditEvent("setLogLevel", r.RemoteAddr, r.FormValue(levelKey), err) }()
+	if level, err = parseRequestToSetLogLevel(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	log.SetLevel(level)
+	log.LogWarnf("action[setLogLevel] remoteAddr[%v] requestID[%v] set log level to %v", r.RemoteAddr, requestID(r), level)
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set log level to %v successfully", level)))
+}
+
+func (m *Server) getLogLevel(w http.ResponseWriter, r *http.Request) {
+	sendOkReply(w, r, newSuccessHTTPReply(log.GetLevel().String()))
+}
+
+// topologyFilter keeps the nodes of the type and the status asked for, the empty ones keep all of them.
+// The nodes kept are paged by start and count, separately for the data nodes and the meta nodes.
+type topologyFilter struct {
+	nodeType string
+	status   string
+	start    int
+	count    int
+}
+
+func (f *topologyFilter) inPage(index int) bool {
+	return index >= f.start && index-f.start < f.count
+}
+
+func (f *topologyFilter) keep(nodeType string, view proto.NodeView) bool {
+	if f.nodeType != "" && f.nodeType != nodeType {
+		return false
+	}
+	switch f.status {
+	case topologyStatusActive:
+		return view.Status
+	case topologyStatusInactive:
+		return !view.Status
+	}
+	return true
+}
+
+func parseTopologyFilter(r *http.Request) (filter *topologyFilter, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	filter = &topologyFilter{nodeType: r.FormValue(typeKey), status: r.FormValue(statusKey), count: math.MaxInt32}
+	if filter.nodeType != "" && filter.nodeType != topologyTypeData && filter.nodeType != topologyTypeMeta {
+		return nil, fmt.Errorf("parameter %v should be %v or %v, received %v", typeKey, topologyTypeData, topologyTypeMeta, filter.nodeType)
+	}
+	if filter.status != "" && filter.status != topologyStatusActive && filter.status != topologyStatusInactive {
+		return nil, fmt.Errorf("parameter %v should be %v or %v, received %v", statusKey, topologyStatusActive, topologyStatusInactive, filter.status)
+	}
+	if value := r.FormValue(startKey); value != "" {
+		if filter.start, err = strconv.Atoi(value); err != nil || filter.start < 0 {
+			return nil, unmatchedKey(startKey)
+		}
+	}
+	if value := r.FormValue(countKey); value != "" {
+		if filter.count, err = strconv.Atoi(value); err != nil || filter.count <= 0 {
+			return nil, unmatchedKey(countKey)
+		}
+	}
+	return
 }
 
 // View the topology of the cluster.
 func (m *Server) getTopology(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTopologyFilter(r)
+	if err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 	tv := &TopologyView{
 		Zones: make([]*ZoneView, 0),
 	}
+	// the zones, the node sets and the nodes are sorted, so that the pages are stable
 	zones := m.cluster.t.getAllZones()
+	sort.Slice(zones, func(i, j int) bool { return zones[i].name < zones[j].name })
 	for _, zone := range zones {
 		cv := newZoneView(zone.name)
 		cv.Status = zone.getStatusToString()
 		tv.Zones = append(tv.Zones, cv)
 		nsc := zone.getAllNodeSet()
+		sort.Slice(nsc, func(i, j int) bool { return nsc[i].ID < nsc[j].ID })
 		for _, ns := range nsc {
 			nsView := newNodeSetView(ns.dataNodeLen(), ns.metaNodeLen())
 			cv.NodeSet[ns.ID] = nsView
-			ns.dataNodes.Range(func(key, value interface{}) bool {
-				dataNode := value.(*DataNode)
-				nsView.DataNodes = append(nsView.DataNodes, proto.NodeView{ID: dataNode.ID, Addr: dataNode.Addr, Status: dataNode.isActive, IsWritable: dataNode.isWriteAble()})
-				return true
-			})
-			ns.metaNodes.Range(func(key, value interface{}) bool {
-				metaNode := value.(*MetaNode)
-				nsView.MetaNodes = append(nsView.MetaNodes, proto.NodeView{ID: metaNode.ID, Addr: metaNode.Addr, Status: metaNode.IsActive, IsWritable: metaNode.isWritable()})
-				return true
-			})
+			for _, view := range sortedNodeViews(ns.dataNodes, func(value interface{}) proto.NodeView { return value.(*DataNode).toNodeView() }) {
+				if filter.keep(topologyTypeData, view) {
+					if filter.inPage(tv.DataNodeTotal) {
+						nsView.DataNodes = append(nsView.DataNodes, view)
+					}
+					tv.DataNodeTotal++
+				}
+			}
+			for _, view := range sortedNodeViews(ns.metaNodes, func(value interface{}) proto.NodeView { return value.(*MetaNode).toNodeView() }) {
+				if filter.keep(topologyTypeMeta, view) {
+					if filter.inPage(tv.MetaNodeTotal) {
+						nsView.MetaNodes = append(nsView.MetaNodes, view)
+					}
+					tv.MetaNodeTotal++
+				}
+			}
 		}
 	}
 	sendOkReply(w, r, newSuccessHTTPReply(tv))
 }
 
+func sortedNodeViews(nodes *sync.Map, toNodeView func(value interface{}) proto.NodeView) (views []proto.NodeView) {
+	nodes.Range(func(key, value interface{}) bool {
+		views = append(views, toNodeView(value))
+		return true
+	})
+	sort.Slice(views, func(i, j int) bool { return views[i].ID < views[j].ID })
+	return
+}
+
+// View the utilization summary of all the node sets in the cluster.
+func (m *Server) getNodeSetStats(w http.ResponseWriter, r *http.Request) {
+	stats := make([]*proto.NodeSetStat, 0)
+	zones := m.cluster.t.getAllZones()
+	for _, zone := range zones {
+		nsc := zone.getAllNodeSet()
+		for _, ns := range nsc {
+			stats = append(stats, ns.getStat())
+		}
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(stats))
+}
+
 func (m *Server) updateZone(w http.ResponseWriter, r *http.Request) {
 	var (
 		name string
@@ -202,27 +483,137 @@ func (m *Server) clusterStat(w http.ResponseWriter, r *http.Request) {
 }
 
 func (m *Server) getCluster(w http.ResponseWriter, r *http.Request) {
-	cv := &proto.ClusterView{
-		Name:                m.cluster.Name,
-		LeaderAddr:          m.leaderInfo.addr,
-		DisableAutoAlloc:    m.cluster.DisableAutoAllocate,
-		MetaNodeThreshold:   m.cluster.cfg.MetaNodeThreshold,
-		Applied:             m.fsm.applied,
-		MaxDataPartitionID:  m.cluster.idAlloc.dataPartitionID,
-		MaxMetaNodeID:       m.cluster.idAlloc.commonID,
-		MaxMetaPartitionID:  m.cluster.idAlloc.metaPartitionID,
-		MetaNodes:           make([]proto.NodeView, 0),
-		DataNodes:           make([]proto.NodeView, 0),
-		VolStatInfo:         make([]*proto.VolStatInfo, 0),
-		BadPartitionIDs:     make([]proto.BadPartitionView, 0),
-		BadMetaPartitionIDs: make([]proto.BadPartitionView, 0),
+	sendOkReply(w, r, newSuccessHTTPReply(m.buildClusterView()))
+}
+
+// Turn the maintenance mode of the cluster on or off, the mutating APIs are rejected with 503 while it is on.
+// The mode is persisted by raft, so that it is kept by a new leader.
+func (m *Server) setMaintenance(w http.ResponseWriter, r *http.Request) {
+	var (
+		enable bool
+		err    error
+	)
+	if enable, err = parseAndExtractStatus(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("setMaintenance", r.RemoteAddr, strconv.FormatBool(enable), err) }()
+	if err = m.cluster.setMaintenanceMode(enable); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set MaintenanceMode to %v successfully", enable)))
+}
+
+// Return the config in effect on the master, including the settings changed by the APIs since it started.
+func (m *Server) getConfig(w http.ResponseWriter, r *http.Request) {
+	sendOkReply(w, r, newSuccessHTTPReply(m.buildConfigView()))
+}
+
+func (m *Server) buildConfigView() (cv *proto.MasterConfigView) {
+	cfg := m.config
+	cv = &proto.MasterConfigView{
+		ClusterName: m.cluster.Name,
+		Peers:       cfg.peerAddrs,
+		LeaderAddr:  m.leaderInfo.addr,
+
+		MetaNodeThreshold:           cfg.MetaNodeThreshold,
+		MetaNodeReservedMem:         cfg.metaNodeReservedMem,
+		MetaNodeDeleteBatchCount:    cfg.MetaNodeDeleteBatchCount,
+		MetaNodeDeleteWorkerSleepMs: cfg.MetaNodeDeleteWorkerSleepMs,
+		DataNodeDeleteLimitRate:     cfg.DataNodeDeleteLimitRate,
+		DataNodeAutoRepairLimitRate: cfg.DataNodeAutoRepairLimitRate,
+		DataPartitionUsageThreshold: cfg.DataPartitionUsageThreshold,
+
+		NodeTimeOutSec:                      cfg.NodeTimeOutSec,
+		DataPartitionTimeOutSec:             cfg.DataPartitionTimeOutSec,
+		MissingDataPartitionInterval:        cfg.MissingDataPartitionInterval,
+		IntervalToAlarmMissingDataPartition: cfg.IntervalToAlarmMissingDataPartition,
+		PeriodToLoadALLDataPartitions:       cfg.PeriodToLoadALLDataPartitions,
+		IntervalToCheckDataPartition:        cfg.IntervalToCheckDataPartition,
+		NumberOfDataPartitionsToLoad:        cfg.numberOfDataPartitionsToLoad,
+		SecondsToFreeDataPartitionAfterLoad: cfg.secondsToFreeDataPartitionAfterLoad,
+		NodeSetCapacity:                     cfg.nodeSetCapacity,
+		FaultDomain:                         cfg.faultDomain,
+		DomainNodeGrpBatchCnt:               cfg.DomainNodeGrpBatchCnt,
+		DomainBuildAsPossible:               cfg.DomainBuildAsPossible,
+
+		MinReplicaNum:       minReplicaNum,
+		MaxReplicaNum:       cfg.maxReplicaNum,
+		MinVolCapacity:      cfg.minVolCapacity,
+		MaxVolCapacity:      cfg.maxVolCapacity,
+		VolNameMinLen:       volNameRule.minLen,
+		VolNameMaxLen:       volNameRule.maxLen,
+		VolNamePattern:      volNameRule.pattern.String(),
+		VolDeletionGraceSec: cfg.volDeletionGraceSec,
+
+		SecureRead:                   cfg.secureRead,
+		StrictParamCheck:             cfg.strictParamCheck,
+		CORSAllowedOrigins:           cfg.corsAllowedOrigins,
+		CORSMutatingAPIs:             cfg.corsMutatingAPIs,
+		BatchDecommissionConcurrency: cfg.batchDecommissionConcurrency,
+		DecommissionRate:             cfg.decommissionRate,
+		RebalanceMaxConcurrentMoves:  cfg.rebalanceMaxConcurrentMoves,
+		ReadyMaxAppliedLag:           cfg.readyMaxAppliedLag,
+		TaskResponseQPS:              cfg.taskResponseQPS,
+		MaxTaskResponseBytes:         cfg.maxTaskResponseBytes,
+		JobRetentionSec:              cfg.jobRetentionSec,
+		GzipMinBytes:                 cfg.gzipMinBytes,
+		AuditLogCapacity:             cfg.auditLogCapacity,
+		DataNodeAllocStrategy:        cfg.dataNodeAllocStrategy,
+
+		Cluster: m.buildClusterConfigView(),
+	}
+	if cfg.adminToken != "" {
+		cv.AdminToken = maskedSecret
+	}
+	return
+}
+
+func (m *Server) buildClusterView() (cv *proto.ClusterView) {
+	cv = &proto.ClusterView{
+		Name:                   m.cluster.Name,
+		LeaderAddr:             m.leaderInfo.addr,
+		Version:                proto.Version,
+		StartTime:              m.startTime.Format(time.RFC3339),
+		UptimeSeconds:          int64(time.Since(m.startTime).Seconds()),
+		DisableAutoAlloc:       m.cluster.DisableAutoAllocate,
+		MaintenanceMode:        m.cluster.MaintenanceMode,
+		AllocationStrategy:     getDataNodeAllocStrategy(),
+		HeartbeatVerbosity:     m.cluster.getHeartbeatVerbosity(),
+		MinRWDataPartitions:    m.cluster.getMinRWDataPartitions(),
+		AutoAllocUsedRatio:     m.cluster.getAutoAllocUsedRatio(),
+		MaxVolumes:             atomic.LoadUint64(&m.cluster.MaxVolumes),
+		MetaNodeThreshold:      m.cluster.cfg.MetaNodeThreshold,
+		Applied:                m.fsm.applied,
+		MaxDataPartitionID:     m.cluster.idAlloc.dataPartitionID,
+		MaxMetaNodeID:          m.cluster.idAlloc.commonID,
+		MaxMetaPartitionID:     m.cluster.idAlloc.metaPartitionID,
+		MetaNodes:              make([]proto.NodeView, 0),
+		DataNodes:              make([]proto.NodeView, 0),
+		VolStatInfo:            make([]*proto.VolStatInfo, 0),
+		BadPartitionIDs:        make([]proto.BadPartitionView, 0),
+		BadMetaPartitionIDs:    make([]proto.BadPartitionView, 0),
+		VolAllocPriorities:     m.cluster.getActiveVolAllocPriorities(),
+		AutoRebalancePolicy:    m.cluster.autoRebalancer.getPolicy(),
+		LastAutoRebalance:      m.cluster.autoRebalancer.getLast(),
+		AutoDecommissionPolicy: m.cluster.autoDecommissioner.getPolicy(),
 	}
 
-	vols := m.cluster.allVolNames()
 	cv.MetaNodes = m.cluster.allMetaNodes()
 	cv.DataNodes = m.cluster.allDataNodes()
+	m.fillClusterStats(cv)
+	cv.PartitionSummary = m.cluster.getPartitionSummary()
+	return
+}
+
+// Fill the space stats, the volume stats and the bad partitions, which are exported by getMetrics as well.
+func (m *Server) fillClusterStats(cv *proto.ClusterView) {
+	vols := m.cluster.allVolNames()
+	cv.VolCount = len(vols)
 	cv.DataNodeStatInfo = m.cluster.dataNodeStatInfo
 	cv.MetaNodeStatInfo = m.cluster.metaNodeStatInfo
+	cv.VolStatInfo = make([]*proto.VolStatInfo, 0, len(vols))
 	for _, name := range vols {
 		stat, ok := m.cluster.volStatInfo.Load(name)
 		if !ok {
@@ -233,8 +624,56 @@ func (m *Server) getCluster(w http.ResponseWriter, r *http.Request) {
 	}
 	cv.BadPartitionIDs = m.cluster.getBadDataPartitionsView()
 	cv.BadMetaPartitionIDs = m.cluster.getBadMetaPartitionsView()
+}
+
+// Export the cluster stats in the Prometheus text format. The path differs from the /metrics of the exporter,
+// and the node views and the partition summary of getCluster are not built on every scrape.
+func (m *Server) getMetrics(w http.ResponseWriter, r *http.Request) {
+	cv := &proto.ClusterView{MetaNodeThreshold: m.cluster.cfg.MetaNodeThreshold}
+	m.fillClusterStats(cv)
+	reply := formatClusterMetrics(cv, m.cluster.dataNodeCount(), m.cluster.metaNodeCount())
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Header().Set("Content-Length", strconv.Itoa(len(reply)))
+	if _, err := w.Write(reply); err != nil {
+		log.LogErrorf("fail to write http reply[%s] len[%d].URL[%v],remoteAddr[%v],requestID[%v] err:[%v]", string(reply), len(reply), r.URL, r.RemoteAddr, requestID(r), err)
+	}
+}
+
+// The liveness probe, it succeeds as long as the http server is up.
+func (m *Server) livez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// The readiness probe, it succeeds on the leader with the metadata loaded, or on a follower which
+// has applied the raft log close to the committed index.
+func (m *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	if reason := m.notReadyReason(); reason != "" {
+		log.LogWarnf("action[readyz] not ready, reason[%v]", reason)
+		http.Error(w, reason, http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
 
-	sendOkReply(w, r, newSuccessHTTPReply(cv))
+func (m *Server) notReadyReason() string {
+	if m.partition.IsRaftLeader() {
+		if !m.metaReady {
+			return "the leader is loading the metadata"
+		}
+		return ""
+	}
+	if m.leaderInfo.addr == "" {
+		return "no leader"
+	}
+	committed, applied := m.partition.CommittedIndex(), m.partition.AppliedIndex()
+	if committed > applied+m.config.readyMaxAppliedLag {
+		return fmt.Sprintf("applied index %v falls behind the committed index %v", applied, committed)
+	}
+	return ""
 }
 
 func (m *Server) getIPAddr(w http.ResponseWriter, r *http.Request) {
@@ -270,18 +709,19 @@ func (m *Server) createMetaPartition(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
-	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprint("create meta partition successfully")))
+	sendCreatedReply(w, r, newSuccessHTTPReply(fmt.Sprint("create meta partition successfully")),
+		fmt.Sprintf("%v?%v=%v", proto.ClientMetaPartitions, nameKey, url.QueryEscape(volName)))
 }
 
+// Create the data partitions of a volume and reply the IDs of the created ones. If the batch stops on a failure,
+// the data partitions created before are kept and replied with the index and the reason of the failure.
 func (m *Server) createDataPartition(w http.ResponseWriter, r *http.Request) {
 	var (
-		rstMsg                     string
-		volName                    string
-		vol                        *Vol
-		reqCreateCount             int
-		lastTotalDataPartitions    int
-		clusterTotalDataPartitions int
-		err                        error
+		volName        string
+		vol            *Vol
+		reqCreateCount int
+		partitionIDs   []uint64
+		err            error
 	)
 
 	if reqCreateCount, volName, err = parseRequestToCreateDataPartition(r); err != nil {
@@ -293,18 +733,31 @@ func (m *Server) createDataPartition(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
 		return
 	}
-	lastTotalDataPartitions = len(vol.dataPartitions.partitions)
-	clusterTotalDataPartitions = m.cluster.getDataPartitionCount()
-	err = m.cluster.batchCreateDataPartition(vol, reqCreateCount)
-	rstMsg = fmt.Sprintf(" createDataPartition succeeeds. "+
-		"clusterLastTotalDataPartitions[%v],vol[%v] has %v data partitions previously and %v data partitions now",
-		clusterTotalDataPartitions, volName, lastTotalDataPartitions, len(vol.dataPartitions.partitions))
+	partitionIDs, err = m.cluster.batchCreateDataPartition(vol, reqCreateCount)
 	if err != nil {
 		log.LogErrorf("create data partition fail: volume(%v) err(%v)", volName, err)
-		sendErrReply(w, r, newErrHTTPReply(err))
+		failure := &proto.DataPartitionCreateFailure{
+			RequestCount: reqCreateCount,
+			CreatedCount: len(partitionIDs),
+			PartitionIDs: partitionIDs,
+			FailedIndex:  len(partitionIDs),
+			Reason:       m.cluster.getDataPartitionCreateFailureReason(err),
+			Detail:       err.Error(),
+		}
+		reply := newErrHTTPReply(err)
+		reply.Msg = fmt.Sprintf("createDataPartition stopped after %v of %v data partitions, reason[%v], err[%v]",
+			failure.CreatedCount, failure.RequestCount, failure.Reason, failure.Detail)
+		reply.Data = failure
+		sendErrReply(w, r, reply)
 		return
 	}
-	_ = sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
+	log.LogInfof("action[createDataPartition] vol[%v] created data partitions %v", volName, partitionIDs)
+	// the location is given only for a single data partition, the IDs of all the created ones are in the reply
+	var location string
+	if len(partitionIDs) == 1 {
+		location = fmt.Sprintf("%v?%v=%v&%v=%v", proto.AdminGetDataPartition, idKey, partitionIDs[0], nameKey, url.QueryEscape(volName))
+	}
+	sendCreatedReply(w, r, newSuccessHTTPReply(&proto.DataPartitionCreateResult{RequestCount: reqCreateCount, PartitionIDs: partitionIDs}), location)
 }
 
 func (m *Server) getDataPartition(w http.ResponseWriter, r *http.Request) {
@@ -363,9 +816,35 @@ func (m *Server) loadDataPartition(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(msg))
 }
 
+// Compare the file checksums of the replicas of a data partition, and reply whether they agree.
+func (m *Server) verifyDataPartition(w http.ResponseWriter, r *http.Request) {
+	var (
+		dp          *DataPartition
+		partitionID uint64
+		timeout     time.Duration
+		result      *proto.DataPartitionVerification
+		err         error
+	)
+
+	if partitionID, timeout, err = parseRequestToVerifyDataPartition(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	if dp, err = m.cluster.getDataPartitionByID(partitionID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataPartitionNotExists))
+		return
+	}
+
+	if result, err = m.cluster.verifyDataPartition(dp, timeout); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(result))
+}
+
 func (m *Server) addDataReplica(w http.ResponseWriter, r *http.Request) {
 	var (
-		msg         string
 		addr        string
 		dp          *DataPartition
 		partitionID uint64
@@ -382,6 +861,23 @@ func (m *Server) addDataReplica(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dp.RLock()
+	replicaNum := len(dp.Hosts) + 1
+	dp.RUnlock()
+	if replicaNum > m.config.maxReplicaNum {
+		err = fmt.Errorf("data partition[%v] would have %v replicas, more than %v of %v",
+			partitionID, replicaNum, m.config.maxReplicaNum, cfgMaxReplicaNum)
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	if addr == autoReplicaAddr {
+		if addr, err = m.cluster.chooseDataReplicaAddr(dp); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+	}
+
 	if err = m.cluster.addDataReplica(dp, addr); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -389,8 +885,10 @@ func (m *Server) addDataReplica(w http.ResponseWriter, r *http.Request) {
 	dp.Status = proto.ReadOnly
 	dp.isRecover = true
 	m.cluster.putBadDataPartitionIDs(nil, addr, dp.PartitionID)
-	msg = fmt.Sprintf("data partitionID :%v  add replica [%v] successfully", partitionID, addr)
-	sendOkReply(w, r, newSuccessHTTPReply(msg))
+	dp.RLock()
+	addition := &proto.DataReplicaAddition{PartitionID: partitionID, Addr: addr, Hosts: append([]string{}, dp.Hosts...)}
+	dp.RUnlock()
+	sendOkReply(w, r, newSuccessHTTPReply(addition))
 }
 
 func (m *Server) deleteDataReplica(w http.ResponseWriter, r *http.Request) {
@@ -489,6 +987,7 @@ func (m *Server) decommissionDataPartition(w http.ResponseWriter, r *http.Reques
 		dp          *DataPartition
 		addr        string
 		partitionID uint64
+		dryRun      bool
 		err         error
 	)
 
@@ -496,10 +995,18 @@ func (m *Server) decommissionDataPartition(w http.ResponseWriter, r *http.Reques
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if dryRun, err = extractDryRun(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 	if dp, err = m.cluster.getDataPartitionByID(partitionID); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataPartitionNotExists))
 		return
 	}
+	if dryRun {
+		sendOkReply(w, r, newSuccessHTTPReply(m.cluster.dryRunDecommission(addr, "", []*DataPartition{dp})))
+		return
+	}
 	if err = m.cluster.decommissionDataPartition(addr, dp, handleDataPartitionOfflineErr); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -508,6 +1015,76 @@ func (m *Server) decommissionDataPartition(w http.ResponseWriter, r *http.Reques
 	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 }
 
+// Move one replica of a data partition to another node for balancing, the source node is not taken as bad.
+func (m *Server) migrateDataPartition(w http.ResponseWriter, r *http.Request) {
+	var (
+		dp          *DataPartition
+		srcAddr     string
+		targetAddr  string
+		partitionID uint64
+		err         error
+	)
+	defer func() {
+		m.cluster.addAuditEvent("migrateDataPartition", r.RemoteAddr, fmt.Sprintf("%v:%v->%v", partitionID, srcAddr, targetAddr), err)
+	}()
+	if partitionID, srcAddr, targetAddr, err = parseRequestToMigrateDataPartition(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if dp, err = m.cluster.getDataPartitionByID(partitionID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataPartitionNotExists))
+		return
+	}
+	if err = m.cluster.moveDataPartition(srcAddr, targetAddr, dp); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	dp.RLock()
+	hosts := append([]string{}, dp.Hosts...)
+	dp.RUnlock()
+	sendOkReply(w, r, newSuccessHTTPReply(hosts))
+}
+
+// Move data partitions from the most loaded data nodes to the least loaded ones of the same node set,
+// the moves planned are replied and they are not executed if dryRun is set.
+func (m *Server) rebalanceDataPartitions(w http.ResponseWriter, r *http.Request) {
+	var (
+		plan   *proto.RebalancePlan
+		limit  int
+		dryRun bool
+		err    error
+	)
+	if limit, dryRun, err = parseRequestToRebalanceDataPartitions(r, m.config.rebalanceMaxConcurrentMoves); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if !dryRun {
+		defer func() {
+			m.cluster.addAuditEvent("rebalanceDataPartitions", r.RemoteAddr, fmt.Sprintf("maxMoves:%v", limit), err)
+		}()
+	}
+	if plan, err = m.cluster.rebalanceDataPartitions(limit, m.config.rebalanceMaxConcurrentMoves, dryRun); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(plan))
+}
+
+// Decommission the data partitions on a node, the partition IDs are given by the comma-separated ids
+// or by the JSON body. The result of each data partition is replied, a failed one does not fail the batch.
+func (m *Server) batchDecommissionDataPartition(w http.ResponseWriter, r *http.Request) {
+	var (
+		req *proto.BatchDecommissionDataPartitions
+		err error
+	)
+	if req, err = parseRequestToBatchDecommissionDataPartition(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	results := m.cluster.batchDecommissionDataPartitions(req.Addr, req.PartitionIDs, m.config.batchDecommissionConcurrency)
+	sendOkReply(w, r, newSuccessHTTPReply(results))
+}
+
 func (m *Server) diagnoseDataPartition(w http.ResponseWriter, r *http.Request) {
 	var (
 		err               error
@@ -547,20 +1124,178 @@ func (m *Server) diagnoseDataPartition(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 }
 
-// Mark the volume as deleted, which will then be deleted later.
-func (m *Server) markDeleteVol(w http.ResponseWriter, r *http.Request) {
-	var (
-		name    string
-		authKey string
-		err     error
-		msg     string
-	)
-
-	if name, authKey, err = parseRequestToDeleteVol(r); err != nil {
-		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
-		return
-	}
-	if err = m.cluster.markDeleteVol(name, authKey); err != nil {
+// Get the data partitions whose recorded hosts exceed the replica number.
+// The extra hosts can be cleaned up by calling AdminDeleteDataReplica for each of them.
+func (m *Server) getOverReplicatedPartitions(w http.ResponseWriter, r *http.Request) {
+	dps := m.cluster.checkOverReplicaDataPartitions()
+	views := make([]*proto.OverReplicatedPartitionView, 0, len(dps))
+	for _, dp := range dps {
+		dp.RLock()
+		if int(dp.ReplicaNum) >= len(dp.Hosts) {
+			dp.RUnlock()
+			continue
+		}
+		view := &proto.OverReplicatedPartitionView{
+			PartitionID: dp.PartitionID,
+			VolName:     dp.VolName,
+			ReplicaNum:  dp.ReplicaNum,
+			Hosts:       make([]string, len(dp.Hosts)),
+			ExtraHosts:  make([]string, 0),
+		}
+		copy(view.Hosts, dp.Hosts)
+		leaderAddr := dp.getLeaderAddr()
+		extraNum := len(dp.Hosts) - int(dp.ReplicaNum)
+		// prefer the tail hosts as extras, but never report the leader
+		for i := len(dp.Hosts) - 1; i >= 0 && len(view.ExtraHosts) < extraNum; i-- {
+			if dp.Hosts[i] == leaderAddr {
+				continue
+			}
+			view.ExtraHosts = append(view.ExtraHosts, dp.Hosts[i])
+		}
+		dp.RUnlock()
+		views = append(views, view)
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(views))
+}
+
+// Get the data and the meta partitions with fewer live replicas than the replica number.
+func (m *Server) getUnderReplicatedPartitions(w http.ResponseWriter, r *http.Request) {
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getUnderReplicatedPartitions()))
+}
+
+// List the nodes whose partitions are being moved off, with the progress of the moves.
+func (m *Server) getDecommissioningNodes(w http.ResponseWriter, r *http.Request) {
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getDecommissioningNodes()))
+}
+
+// Get the lag of each replica of the data partition behind its leader.
+func (m *Server) getReplicaLag(w http.ResponseWriter, r *http.Request) {
+	var (
+		dp          *DataPartition
+		partitionID uint64
+		lags        []*proto.ReplicaLagView
+		err         error
+	)
+	if partitionID, err = parseRequestToLoadDataPartition(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if dp, err = m.cluster.getDataPartitionByID(partitionID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataPartitionNotExists))
+		return
+	}
+	if lags, err = dp.getReplicaLags(); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(lags))
+}
+
+// Get the replicas which fall behind their leaders most in the cluster.
+func (m *Server) getLaggingReplicas(w http.ResponseWriter, r *http.Request) {
+	var (
+		count int
+		err   error
+	)
+	if count, err = parseRequestToGetLaggingReplicas(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getLaggingReplicas(count)))
+}
+
+// Reserve data partition ids for a volume without creating the partitions,
+// the data partitions created later for the volume take the reserved ids first.
+// The reservation is not persisted, the ids not consumed yet are lost when the leader changes.
+func (m *Server) reservePartitionIDs(w http.ResponseWriter, r *http.Request) {
+	var (
+		name    string
+		count   int
+		idRange *proto.PartitionIDRange
+		err     error
+	)
+	if name, count, err = parseRequestToReservePartitionIDs(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if idRange, err = m.cluster.reserveDataPartitionIDs(name, uint64(count)); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(idRange))
+}
+
+// Forecast when the data nodes are full by the growth of the used space during the last hours.
+func (m *Server) getCapacityForecast(w http.ResponseWriter, r *http.Request) {
+	var (
+		hours int
+		err   error
+	)
+	if hours, err = parseRequestToGetCapacityForecast(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getCapacityForecast(hours)))
+}
+
+// Query the counts of the bad data partitions sampled during the last hours.
+func (m *Server) getBadPartitionTrend(w http.ResponseWriter, r *http.Request) {
+	var (
+		hours int
+		err   error
+	)
+	if hours, err = parseRequestToGetBadPartitionTrend(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getBadPartitionTrend(hours)))
+}
+
+// Show the partitions and the space that markDeleteVol would release, the volume is not changed.
+func (m *Server) previewVolDeletion(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		vol  *Vol
+		err  error
+	)
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.previewVolDeletion(vol)))
+}
+
+// Mark the volume as deleted, which will then be deleted later.
+func (m *Server) markDeleteVol(w http.ResponseWriter, r *http.Request) {
+	var (
+		name    string
+		authKey string
+		err     error
+		msg     string
+		force   bool
+		vol     *Vol
+	)
+
+	if name, authKey, err = parseRequestToDeleteVol(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if force, err = extractForce(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	// the admin token has been checked by the authenticator if it is configured
+	if force && m.config.adminToken == "" {
+		err = fmt.Errorf("%v requires %v of the master config", forceKey, cfgAdminToken)
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("markDeleteVol", r.RemoteAddr, name, err) }()
+	if err = m.cluster.markDeleteVol(name, authKey); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
@@ -568,11 +1303,74 @@ func (m *Server) markDeleteVol(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
+	if force {
+		if vol, err = m.cluster.getVol(name); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
+			return
+		}
+		deletion := &proto.VolForceDeletion{Name: name, ScheduledPartitions: vol.forceDelete(m.cluster)}
+		Warn(m.cluster.Name, fmt.Sprintf("action[markDeleteVol] force delete vol[%v] with [%v] partitions,from[%v]",
+			name, deletion.ScheduledPartitions, r.RemoteAddr))
+		sendOkReply(w, r, newSuccessHTTPReply(deletion))
+		return
+	}
 	msg = fmt.Sprintf("delete vol[%v] successfully,from[%v]", name, r.RemoteAddr)
 	log.LogWarn(msg)
 	sendOkReply(w, r, newSuccessHTTPReply(msg))
 }
 
+// Restore a volume marked deleted, before the grace period ends and its partitions are reclaimed.
+func (m *Server) undeleteVol(w http.ResponseWriter, r *http.Request) {
+	var (
+		name    string
+		authKey string
+		err     error
+		vol     *Vol
+	)
+	if name, authKey, err = parseRequestToDeleteVol(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("undeleteVol", r.RemoteAddr, name, err) }()
+	if vol, err = m.cluster.undeleteVol(name, authKey); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	if err = m.associateVolWithUser(vol.Owner, name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("undelete vol[%v] successfully,from[%v]", name, r.RemoteAddr)))
+}
+
+// Rename the volume, the policies of the users on the volume follow the new name.
+// They are persisted apart from the volume, so the volume is renamed back if they fail to follow.
+func (m *Server) renameVol(w http.ResponseWriter, r *http.Request) {
+	var (
+		name    string
+		newName string
+		authKey string
+		err     error
+	)
+	if name, newName, authKey, err = parseRequestToRenameVol(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("renameVol", r.RemoteAddr, name, err) }()
+	if _, err = m.cluster.renameVol(name, newName, authKey); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	if err = m.user.renameVolPolicy(name, newName); err != nil {
+		if _, e := m.cluster.renameVol(newName, name, authKey); e != nil {
+			log.LogErrorf("action[renameVol] rename vol[%v] back to [%v] err[%v]", newName, name, e)
+		}
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("rename vol[%v] to [%v] successfully,from[%v]", name, newName, r.RemoteAddr)))
+}
+
 func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 	var (
 		name           string
@@ -587,6 +1385,10 @@ func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 		description    string
 		dpSelectorName string
 		dpSelectorParm string
+		force          bool
+		tags           map[string]string
+		bandwidthLimit uint64
+		iopsLimit      uint64
 		vol            *Vol
 	)
 
@@ -594,6 +1396,7 @@ func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	defer func() { m.cluster.addAuditEvent("updateVol", r.RemoteAddr, name, err) }()
 	if vol, err = m.cluster.getVol(name); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
 		return
@@ -603,17 +1406,42 @@ func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
-	if replicaNum != 0 && !(replicaNum == 2 || replicaNum == 3) {
-		err = fmt.Errorf("replicaNum can only be 2 and 3,received replicaNum is[%v]", replicaNum)
-		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
-		return
+	if replicaNum != 0 {
+		if err = checkReplicaNum(replicaNum, m.config.maxReplicaNum); err != nil {
+			sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+			return
+		}
+	}
+	// the volumes out of the bounds configured later are left alone until their capacity is changed
+	if capacity != vol.Capacity {
+		if err = checkVolCapacity(capacity, m.config.minVolCapacity, m.config.maxVolCapacity); err != nil {
+			sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+			return
+		}
 	}
 
 	if followerRead, authenticate, err = parseBoolFieldToUpdateVol(r, vol); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if force, err = extractForce(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if tags, err = parseVolTags(r.FormValue(tagsKey)); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if bandwidthLimit, err = extractQosLimit(r, bandwidthLimitKey); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if iopsLimit, err = extractQosLimit(r, iopsLimitKey); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 
+	oldCapacity := vol.Capacity
 	newArgs := getVolVarargs(vol)
 
 	newArgs.zoneName = zoneName
@@ -623,12 +1451,25 @@ func (m *Server) updateVol(w http.ResponseWriter, r *http.Request) {
 	newArgs.authenticate = authenticate
 	newArgs.dpSelectorName = dpSelectorName
 	newArgs.dpSelectorParm = dpSelectorParm
+	newArgs.force = force
+	// the tags are replaced only if given, an empty value removes all of them
+	if _, ok := r.Form[tagsKey]; ok {
+		newArgs.tags = tags
+	}
+	// the limits not given are kept, 0 removes the limit
+	if _, ok := r.Form[bandwidthLimitKey]; ok {
+		newArgs.bandwidthLimit = bandwidthLimit
+	}
+	if _, ok := r.Form[iopsLimitKey]; ok {
+		newArgs.iopsLimit = iopsLimit
+	}
 
+	newArgs.caller = r.RemoteAddr
 	if err = m.cluster.updateVol(name, authKey, newArgs); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
-	msg = fmt.Sprintf("update vol[%v] successfully\n", name)
+	msg = fmt.Sprintf("update vol[%v] successfully, capacity from %vGB to %vGB\n", name, oldCapacity, capacity)
 	sendOkReply(w, r, newSuccessHTTPReply(msg))
 }
 
@@ -645,6 +1486,7 @@ func (m *Server) volExpand(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	defer func() { m.cluster.addAuditEvent("volExpand", r.RemoteAddr, name, err) }()
 	if vol, err = m.cluster.getVol(name); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
 		return
@@ -654,10 +1496,15 @@ func (m *Server) volExpand(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if err = checkVolCapacity(uint64(capacity), m.config.minVolCapacity, m.config.maxVolCapacity); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 
 	newArgs := getVolVarargs(vol)
 	newArgs.capacity = uint64(capacity)
 
+	newArgs.caller = r.RemoteAddr
 	if err = m.cluster.updateVol(name, authKey, newArgs); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -673,12 +1520,14 @@ func (m *Server) volShrink(w http.ResponseWriter, r *http.Request) {
 		err      error
 		msg      string
 		capacity int
+		force    bool
 		vol      *Vol
 	)
 	if name, authKey, capacity, err = parseRequestToSetVolCapacity(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	defer func() { m.cluster.addAuditEvent("volShrink", r.RemoteAddr, name, err) }()
 	if vol, err = m.cluster.getVol(name); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeVolNotExists, Msg: err.Error()})
 		return
@@ -688,10 +1537,20 @@ func (m *Server) volShrink(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if err = checkVolCapacity(uint64(capacity), m.config.minVolCapacity, m.config.maxVolCapacity); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if force, err = extractForce(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
 
 	newArgs := getVolVarargs(vol)
 	newArgs.capacity = uint64(capacity)
+	newArgs.force = force
 
+	newArgs.caller = r.RemoteAddr
 	if err = m.cluster.updateVol(name, authKey, newArgs); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
@@ -715,45 +1574,130 @@ func (m *Server) createVol(w http.ResponseWriter, r *http.Request) {
 		authenticate    bool
 		crossZone       bool
 		defaultPriority bool
+		failIfExists    bool
 		zoneName        string
 		description     string
+		tags            map[string]string
+		bandwidthLimit  uint64
+		iopsLimit       uint64
 	)
 
 	if name, owner, zoneName, description,
 		mpCount, dpReplicaNum, size,
 		capacity, followerRead,
-		authenticate, crossZone, defaultPriority,
-		err = parseRequestToCreateVol(r); err != nil {
+		authenticate, crossZone, defaultPriority, failIfExists,
+		tags, bandwidthLimit, iopsLimit, err = parseRequestToCreateVol(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if m.isStrictParamCheck(r) {
+		if err = checkUnknownParams(r, createVolParamKeys); err != nil {
+			sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+			return
+		}
+	}
+	defer func() { m.cluster.addAuditEvent("createVol", r.RemoteAddr, name, err) }()
+	if err = checkReplicaNum(dpReplicaNum, m.config.maxReplicaNum); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
-	if !(dpReplicaNum == 2 || dpReplicaNum == 3) {
-		err = fmt.Errorf("replicaNum can only be 2 and 3,received replicaNum is[%v]", dpReplicaNum)
+	if err = checkVolCapacity(uint64(capacity), m.config.minVolCapacity, m.config.maxVolCapacity); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if vol, err = m.createVolWithSettings(name, owner, zoneName, description,
+		mpCount, dpReplicaNum, size, capacity,
+		followerRead, authenticate, crossZone, defaultPriority, failIfExists,
+		tags, bandwidthLimit, iopsLimit); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	if vol == nil {
+		msg = fmt.Sprintf("vol[%v] already exists", name)
+		sendOkReply(w, r, newSuccessHTTPReply(msg))
+		return
+	}
+	msg = fmt.Sprintf("create vol[%v] successfully, has allocate [%v] data partitions", name, len(vol.dataPartitions.partitions))
+	sendCreatedReply(w, r, newSuccessHTTPReply(msg), fmt.Sprintf("%v?%v=%v", proto.AdminGetVol, nameKey, url.QueryEscape(name)))
+}
+
+// Create the volume with its tags and QoS limits, and associate it with the owner. The vol returned is nil if the volume
+// exists with the same settings already and failIfExists is not set, the creation is taken as done by a retry.
+func (m *Server) createVolWithSettings(name, owner, zoneName, description string,
+	mpCount, dpReplicaNum, size, capacity int,
+	followerRead, authenticate, crossZone, defaultPriority, failIfExists bool,
+	tags map[string]string, bandwidthLimit, iopsLimit uint64) (vol *Vol, err error) {
 	if vol, err = m.cluster.createVol(name, owner, zoneName, description,
 		mpCount, dpReplicaNum, size, capacity,
 		followerRead, authenticate, crossZone,
-		defaultPriority); err != nil {
-		sendErrReply(w, r, newErrHTTPReply(err))
+		defaultPriority, tags, bandwidthLimit, iopsLimit); err != nil {
+		if err == proto.ErrDuplicateVol && !failIfExists && m.cluster.isVolCreatedWith(name, dpReplicaNum, size, capacity) {
+			return nil, nil
+		}
+		return
+	}
+	err = m.associateVolWithUser(owner, name)
+	return
+}
+
+// Create the volumes of a json array of proto.CreateVolRequest one by one. Each volume is checked by the rules of createVol,
+// an invalid or failed volume does not stop the others, and the result of each volume is returned.
+func (m *Server) batchCreateVol(w http.ResponseWriter, r *http.Request) {
+	var (
+		reqs []*proto.CreateVolRequest
+		err  error
+	)
+	if reqs, err = parseRequestToBatchCreateVol(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	results := make([]*proto.VolCreationResult, 0, len(reqs))
+	for _, req := range reqs {
+		result := &proto.VolCreationResult{Name: req.Name, Success: true}
+		if err = m.createVolOfBatch(req, result); err != nil {
+			log.LogErrorf("action[batchCreateVol] create vol[%v] requestID[%v] err[%v]", req.Name, requestID(r), err)
+			result.Success = false
+			result.Msg = err.Error()
+		}
+		m.cluster.addAuditEvent("createVol", r.RemoteAddr, req.Name, err)
+		results = append(results, result)
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(results))
+}
 
-	if err = m.associateVolWithUser(owner, name); err != nil {
-		sendErrReply(w, r, newErrHTTPReply(err))
+func (m *Server) createVolOfBatch(req *proto.CreateVolRequest, result *proto.VolCreationResult) (err error) {
+	var vol *Vol
+	if err = checkCreateVolRequest(req); err != nil {
 		return
 	}
-	msg = fmt.Sprintf("create vol[%v] successfully, has allocate [%v] data partitions", name, len(vol.dataPartitions.partitions))
-	sendOkReply(w, r, newSuccessHTTPReply(msg))
+	if err = checkReplicaNum(req.ReplicaNum, m.config.maxReplicaNum); err != nil {
+		return
+	}
+	if err = checkVolCapacity(uint64(req.Capacity), m.config.minVolCapacity, m.config.maxVolCapacity); err != nil {
+		return
+	}
+	if vol, err = m.createVolWithSettings(req.Name, req.Owner, req.ZoneName, req.Description,
+		req.MpCount, req.ReplicaNum, req.DataPartitionSize, req.Capacity,
+		req.FollowerRead, req.Authenticate, req.CrossZone, req.DefaultPriority, req.FailIfExists,
+		req.Tags, uint64(req.BandwidthLimit), uint64(req.IopsLimit)); err != nil {
+		return
+	}
+	if vol == nil {
+		result.Msg = fmt.Sprintf("vol[%v] already exists", req.Name)
+		if vol, err = m.cluster.getVol(req.Name); err != nil {
+			return
+		}
+	}
+	result.DataPartitionCount = len(vol.dataPartitions.partitions)
+	return
 }
 
-func (m *Server) getVolSimpleInfo(w http.ResponseWriter, r *http.Request) {
+// Export the full configuration of a volume, which can be used by importVol to recreate it.
+func (m *Server) exportVol(w http.ResponseWriter, r *http.Request) {
 	var (
-		err     error
-		name    string
-		vol     *Vol
-		volView *proto.SimpleVolView
+		err  error
+		name string
+		vol  *Vol
 	)
 	if name, err = parseAndExtractName(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
@@ -763,38 +1707,229 @@ func (m *Server) getVolSimpleInfo(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
 		return
 	}
-	volView = newSimpleView(vol)
-	sendOkReply(w, r, newSuccessHTTPReply(volView))
+	sendOkReply(w, r, newSuccessHTTPReply(newVolSpec(vol)))
 }
 
-func newSimpleView(vol *Vol) *proto.SimpleVolView {
+// List the latest changes of the capacity of a volume, the oldest first.
+func (m *Server) getVolHistory(w http.ResponseWriter, r *http.Request) {
 	var (
-		volInodeCount  uint64
-		volDentryCount uint64
+		err  error
+		name string
+		vol  *Vol
 	)
-	for _, mp := range vol.MetaPartitions {
-		volDentryCount = volDentryCount + mp.DentryCount
-		volInodeCount = volInodeCount + mp.InodeCount
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
 	}
-	maxPartitionID := vol.maxPartitionID()
-	return &proto.SimpleVolView{
-		ID:                 vol.ID,
-		Name:               vol.Name,
-		Owner:              vol.Owner,
-		ZoneName:           vol.zoneName,
-		DpReplicaNum:       vol.dpReplicaNum,
-		MpReplicaNum:       vol.mpReplicaNum,
-		InodeCount:         volInodeCount,
-		DentryCount:        volDentryCount,
-		MaxMetaPartitionID: maxPartitionID,
-		Status:             vol.Status,
-		Capacity:           vol.Capacity,
-		FollowerRead:       vol.FollowerRead,
-		NeedToLowerReplica: vol.NeedToLowerReplica,
-		Authenticate:       vol.authenticate,
-		CrossZone:          vol.crossZone,
-		DefaultPriority:    vol.defaultPriority,
-		DomainOn:           vol.domainOn,
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(vol.getCapacityHistory()))
+}
+
+// Recreate a volume (without data) from the spec returned by exportVol.
+func (m *Server) importVol(w http.ResponseWriter, r *http.Request) {
+	var (
+		err  error
+		msg  string
+		vol  *Vol
+		spec *proto.VolSpec
+	)
+	if spec, err = parseRequestToImportVol(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = checkReplicaNum(int(spec.DpReplicaNum), m.config.maxReplicaNum); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = checkVolCapacity(spec.Capacity, m.config.minVolCapacity, m.config.maxVolCapacity); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.importVol(spec); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	if err = m.associateVolWithUser(spec.Owner, spec.Name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	msg = fmt.Sprintf("import vol[%v] successfully, has allocate [%v] data partitions", spec.Name, len(vol.dataPartitions.partitions))
+	sendOkReply(w, r, newSuccessHTTPReply(msg))
+}
+
+// Temporarily boost the data partition allocation of a volume, the boost expires after ttl seconds.
+// Setting the priority to 0 removes the boost.
+func (m *Server) setVolAllocationPriority(w http.ResponseWriter, r *http.Request) {
+	var (
+		name     string
+		priority int
+		ttl      int64
+		err      error
+	)
+	if name, priority, ttl, err = parseRequestToSetVolAllocPriority(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if _, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
+		return
+	}
+	m.cluster.setVolAllocPriority(name, priority, time.Duration(ttl)*time.Second)
+	msg := fmt.Sprintf("set allocation priority of vol[%v] to %v for %v seconds successfully", name, priority, ttl)
+	sendOkReply(w, r, newSuccessHTTPReply(msg))
+}
+
+// Set the IO priority class of the volume, which is carried to the clients and nodes with the data partitions.
+func (m *Server) setVolIOPriority(w http.ResponseWriter, r *http.Request) {
+	var (
+		name  string
+		class string
+		err   error
+	)
+	if name, class, err = parseRequestToSetVolIOPriority(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("setVolIOPriority", r.RemoteAddr, name, err) }()
+	if err = m.cluster.setVolIOPriorityClass(name, class); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set IO priority class of vol[%v] to %v successfully", name, class)))
+}
+
+// Freeze or unfreeze the writes to the volume, the volume is kept with its data.
+func (m *Server) setVolReadOnly(w http.ResponseWriter, r *http.Request) {
+	var (
+		name     string
+		readOnly bool
+		err      error
+	)
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if readOnly, err = extractStatus(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("setVolReadOnly", r.RemoteAddr, name, err) }()
+	if err = m.cluster.setVolReadOnly(name, readOnly); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set readOnly of vol[%v] to %v successfully", name, readOnly)))
+}
+
+// Show how the data partitions of the volume deviate from an even distribution over the data nodes.
+func (m *Server) getVolSkew(w http.ResponseWriter, r *http.Request) {
+	var (
+		name string
+		vol  *Vol
+		err  error
+	)
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(m.cluster.getVolSkew(vol)))
+}
+
+func newVolSpec(vol *Vol) *proto.VolSpec {
+	vol.volLock.RLock()
+	defer vol.volLock.RUnlock()
+	return &proto.VolSpec{
+		Name:              vol.Name,
+		Owner:             vol.Owner,
+		ZoneName:          vol.zoneName,
+		Description:       vol.description,
+		Capacity:          vol.Capacity,
+		DataPartitionSize: vol.dataPartitionSize / util.GB,
+		DpReplicaNum:      vol.dpReplicaNum,
+		MpReplicaNum:      vol.mpReplicaNum,
+		MpCount:           len(vol.cloneMetaPartitionMap()),
+		FollowerRead:      vol.FollowerRead,
+		Authenticate:      vol.authenticate,
+		CrossZone:         vol.crossZone,
+		DefaultPriority:   vol.defaultPriority,
+		DpSelectorName:    vol.dpSelectorName,
+		DpSelectorParm:    vol.dpSelectorParm,
+		IOPriorityClass:   vol.ioPriorityClass,
+		BandwidthLimit:    vol.bandwidthLimit,
+		IopsLimit:         vol.iopsLimit,
+		ReadOnly:          vol.readOnly,
+		Tags:              vol.tags,
+	}
+}
+
+func (m *Server) getVolSimpleInfo(w http.ResponseWriter, r *http.Request) {
+	var (
+		err     error
+		name    string
+		vol     *Vol
+		volView *proto.SimpleVolView
+	)
+	if name, err = parseAndExtractName(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if vol, err = m.cluster.getVol(name); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
+		return
+	}
+	volView = newSimpleView(vol)
+	sendOkReply(w, r, newSuccessHTTPReply(volView))
+}
+
+// Reply the status only, 200 if the volume exists and 404 if not, so that its existence is checked cheaply.
+func (m *Server) headVol(w http.ResponseWriter, r *http.Request) {
+	name, err := parseAndExtractName(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if _, err = m.cluster.getVol(name); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func newSimpleView(vol *Vol) *proto.SimpleVolView {
+	var (
+		volInodeCount  uint64
+		volDentryCount uint64
+	)
+	for _, mp := range vol.MetaPartitions {
+		volDentryCount = volDentryCount + mp.DentryCount
+		volInodeCount = volInodeCount + mp.InodeCount
+	}
+	maxPartitionID := vol.maxPartitionID()
+	return &proto.SimpleVolView{
+		ID:                 vol.ID,
+		Name:               vol.Name,
+		Owner:              vol.Owner,
+		ZoneName:           vol.zoneName,
+		DpReplicaNum:       vol.dpReplicaNum,
+		MpReplicaNum:       vol.mpReplicaNum,
+		InodeCount:         volInodeCount,
+		DentryCount:        volDentryCount,
+		MaxMetaPartitionID: maxPartitionID,
+		Status:             vol.viewStatus(),
+		Capacity:           vol.Capacity,
+		FollowerRead:       vol.FollowerRead,
+		NeedToLowerReplica: vol.NeedToLowerReplica,
+		Authenticate:       vol.authenticate,
+		CrossZone:          vol.crossZone,
+		DefaultPriority:    vol.defaultPriority,
+		DomainOn:           vol.domainOn,
 		RwDpCnt:            vol.dataPartitions.readableAndWritableCnt,
 		MpCnt:              len(vol.MetaPartitions),
 		DpCnt:              len(vol.dataPartitions.partitionMap),
@@ -803,6 +1938,10 @@ func newSimpleView(vol *Vol) *proto.SimpleVolView {
 		DpSelectorName:     vol.dpSelectorName,
 		DpSelectorParm:     vol.dpSelectorParm,
 		DefaultZonePrior:   vol.defaultPriority,
+		IOPriorityClass:    vol.ioPriorityClass,
+		Tags:               vol.tags,
+		BandwidthLimit:     vol.bandwidthLimit,
+		IopsLimit:          vol.iopsLimit,
 	}
 }
 
@@ -815,11 +1954,7 @@ func checkIp(addr string) bool {
 		return false
 	}
 	ip := strings.Trim(addr, " ")
-	regStr := `^(([1-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.)(([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.){2}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])`
-	if match, _ := regexp.MatchString(regStr, ip); match {
-		return true
-	}
-	return false
+	return ipRegexp.MatchString(ip)
 }
 
 func (m *Server) addDataNode(w http.ResponseWriter, r *http.Request) {
@@ -846,13 +1981,59 @@ func (m *Server) addDataNode(w http.ResponseWriter, r *http.Request) {
 			sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		}
 	}
-	if id, err = m.cluster.addDataNode(nodeAddr, zoneName, nodesetId); err != nil {
+	if id, err = m.cluster.addDataNode(nodeAddr, zoneName, nodesetId); err == proto.ErrDuplicateNode {
+		sendDuplicateNodeReply(w, r, nodeAddr, id)
+		return
+	}
+	if err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
 	sendOkReply(w, r, newSuccessHTTPReply(id))
 }
 
+// List the data partitions with a replica on the data node across all the volumes.
+func (m *Server) getDataNodePartitions(w http.ResponseWriter, r *http.Request) {
+	var (
+		nodeAddr string
+		err      error
+	)
+	if nodeAddr, err = parseAndExtractNodeAddr(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	partitions := m.cluster.getAllDataPartitionByDataNode(nodeAddr)
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].PartitionID < partitions[j].PartitionID })
+	views := make([]*proto.NodeDataPartition, 0, len(partitions))
+	for _, dp := range partitions {
+		dpr := dp.convertToDataPartitionResponse()
+		views = append(views, &proto.NodeDataPartition{
+			DataPartitionResponse: dpr,
+			VolName:               dp.VolName,
+			IsLeader:              dpr.LeaderAddr == nodeAddr,
+		})
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(views))
+}
+
+// List the disks of the data node with their space and the number of the data partitions on them.
+func (m *Server) getDataNodeDisks(w http.ResponseWriter, r *http.Request) {
+	var (
+		nodeAddr string
+		dataNode *DataNode
+		err      error
+	)
+	if nodeAddr, err = parseAndExtractNodeAddr(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if dataNode, err = m.cluster.dataNode(nodeAddr); err != nil {
+		sendErrReplyWithStatus(w, r, http.StatusNotFound, newErrHTTPReply(proto.ErrDataNodeNotExists))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(dataNode.disks()))
+}
+
 func (m *Server) getDataNode(w http.ResponseWriter, r *http.Request) {
 	var (
 		nodeAddr     string
@@ -890,114 +2071,277 @@ func (m *Server) getDataNode(w http.ResponseWriter, r *http.Request) {
 		PersistenceDataPartitions: dataNode.PersistenceDataPartitions,
 		BadDisks:                  dataNode.BadDisks,
 		RdOnly:                    dataNode.RdOnly,
+		Tags:                      dataNode.Tags,
+		DiskWrittenSizes:          dataNode.getDiskWrittenSizes(),
+		DiskReports:               dataNode.diskReports,
 	}
 
 	sendOkReply(w, r, newSuccessHTTPReply(dataNodeInfo))
 }
 
-// Decommission a data node. This will decommission all the data partition on that node.
-func (m *Server) decommissionDataNode(w http.ResponseWriter, r *http.Request) {
+// Simulate the decommission of a data node, or of one disk of it, and report the data partitions
+// which could not keep their replica count on the remaining capacity.
+func (m *Server) preCheckDecommission(w http.ResponseWriter, r *http.Request) {
 	var (
-		rstMsg      string
-		offLineAddr string
-		limit       int
-		err         error
+		addr     string
+		diskPath string
+		result   *proto.DecommissionPreCheck
+		err      error
 	)
-
-	if offLineAddr, limit, err = parseDecomNodeReq(r); err != nil {
+	if addr, diskPath, err = parseRequestToPreCheckDecommission(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
-
-	if _, err = m.cluster.dataNode(offLineAddr); err != nil {
+	if _, err = m.cluster.dataNode(addr); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataNodeNotExists))
 		return
 	}
-
-	if err = m.cluster.migrateDataNode(offLineAddr, "", limit); err != nil {
+	if result, err = m.cluster.preCheckDecommission(addr, diskPath); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
-
-	rstMsg = fmt.Sprintf("decommission data node [%v] limit %d successfully", offLineAddr, limit)
-	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
+	sendOkReply(w, r, newSuccessHTTPReply(result))
 }
 
-func (m *Server) migrateDataNodeHandler(w http.ResponseWriter, r *http.Request) {
-	srcAddr, targetAddr, limit, err := parseMigrateNodeParam(r)
-	if err != nil {
+// Report the partitions that would be affected if all the nodes of the node set fail.
+func (m *Server) simulateNodeSetFailure(w http.ResponseWriter, r *http.Request) {
+	var (
+		id     uint64
+		result *proto.NodeSetFailureSimulation
+		err    error
+	)
+	if id, err = parseRequestToSimulateNodeSetFailure(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
-
-	if limit > defaultMigrateDpCnt {
-		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError,
-			Msg: fmt.Sprintf("limit %d can't be bigger than %d", limit, defaultMigrateDpCnt)})
+	if result, err = m.cluster.simulateNodeSetFailure(id); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
+	sendOkReply(w, r, newSuccessHTTPReply(result))
+}
 
-	srcNode, err := m.cluster.dataNode(srcAddr)
-	if err != nil {
-		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeDataNodeNotExists, Msg: err.Error()})
+func (m *Server) createNodeSet(w http.ResponseWriter, r *http.Request) {
+	var (
+		zoneName string
+		capacity int
+		ns       *nodeSet
+		members  *proto.NodeSetMembers
+		err      error
+	)
+	if zoneName, capacity, err = parseRequestToCreateNodeSet(r, m.config.nodeSetCapacity); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
-
-	targetNode, err := m.cluster.dataNode(targetAddr)
-	if err != nil {
-		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeDataNodeNotExists, Msg: err.Error()})
+	defer func() { m.cluster.addAuditEvent("createNodeSet", r.RemoteAddr, zoneName, err) }()
+	if ns, err = m.cluster.createNodeSet(zoneName, capacity); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	if members, err = m.cluster.getNodeSetMembers(ns.ID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
+	sendOkReply(w, r, newSuccessHTTPReply(members))
+}
 
-	if srcNode.NodeSetID != targetNode.NodeSetID {
-		err = fmt.Errorf("src %s and target %s must exist in the same nodeSet when migrate", srcAddr, targetAddr)
+func (m *Server) getNodeSet(w http.ResponseWriter, r *http.Request) {
+	var (
+		id      uint64
+		members *proto.NodeSetMembers
+		err     error
+	)
+	if id, err = parseRequestToGetNodeSet(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if members, err = m.cluster.getNodeSetMembers(id); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(members))
+}
 
-	if !targetNode.isWriteAble() {
-		err = fmt.Errorf("[%s] is not writable, can't used as target addr for migrate", targetAddr)
+func (m *Server) moveNodeToNodeSet(w http.ResponseWriter, r *http.Request) {
+	var (
+		addr     string
+		nodeType string
+		id       uint64
+		err      error
+	)
+	if addr, nodeType, id, err = parseRequestToMoveNodeToNodeSet(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
-
-	if err = m.cluster.migrateDataNode(srcAddr, targetAddr, limit); err != nil {
+	defer func() {
+		m.cluster.addAuditEvent("moveNodeToNodeSet", r.RemoteAddr, fmt.Sprintf("%v:%v->%v", nodeType, addr, id), err)
+	}()
+	if err = m.cluster.moveNodeToNodeSet(addr, nodeType, id); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
-
-	rstMsg := fmt.Sprintf("migrateDataNodeHandler from src [%v] to target[%v] has migrate successfully", srcAddr, targetAddr)
-	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("move %v node[%v] to nodeSet[%v] successfully", nodeType, addr, id)))
 }
 
-func (m *Server) setNodeInfoHandler(w http.ResponseWriter, r *http.Request) {
+// The decommission is refused if the pre-check does not pass, unless the force flag is set.
+// The error reply has been sent if the returned err is not nil.
+func (m *Server) preCheckDecommissionUnlessForced(w http.ResponseWriter, r *http.Request, addr, diskPath string) (err error) {
 	var (
-		params map[string]interface{}
-		err    error
+		force  bool
+		result *proto.DecommissionPreCheck
 	)
-	if params, err = parseAndExtractSetNodeInfoParams(r); err != nil {
+	if force, err = extractForce(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
-
-	if batchCount, ok := params[nodeDeleteBatchCountKey]; ok {
-		if bc, ok := batchCount.(uint64); ok {
-			if err = m.cluster.setMetaNodeDeleteBatchCount(bc); err != nil {
-				sendErrReply(w, r, newErrHTTPReply(err))
-				return
-			}
-		}
+	if force {
+		log.LogWarnf("action[preCheckDecommission] addr[%v] disk[%v] skip the pre-check by force, from[%v]", addr, diskPath, r.RemoteAddr)
+		return
 	}
-	if val, ok := params[nodeMarkDeleteRateKey]; ok {
-		if v, ok := val.(uint64); ok {
-			if err = m.cluster.setDataNodeDeleteLimitRate(v); err != nil {
-				sendErrReply(w, r, newErrHTTPReply(err))
-				return
-			}
-		}
+	if result, err = m.cluster.preCheckDecommission(addr, diskPath); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
 	}
-
-	if val, ok := params[nodeAutoRepairRateKey]; ok {
-		if v, ok := val.(uint64); ok {
+	if !result.Passed {
+		err = proto.ErrCannotBeOffLine
+		reply := newErrHTTPReply(err)
+		reply.Msg = fmt.Sprintf("decommission of [%v] disk[%v] would leave %v data partitions below their replica count, set %v=true to force it",
+			addr, diskPath, len(result.Violations), forceKey)
+		reply.Data = result
+		sendErrReply(w, r, reply)
+		return
+	}
+	return
+}
+
+// Decommission a data node. This will decommission all the data partition on that node.
+func (m *Server) decommissionDataNode(w http.ResponseWriter, r *http.Request) {
+	var (
+		rstMsg      string
+		offLineAddr string
+		limit       int
+		rate        int
+		jobID       string
+		err         error
+	)
+
+	if offLineAddr, limit, err = parseDecomNodeReq(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if rate, err = parseUintParam(r, rateKey); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if rate == 0 {
+		rate = m.config.decommissionRate
+	}
+	defer func() { m.cluster.addAuditEvent("decommissionDataNode", r.RemoteAddr, offLineAddr, err) }()
+
+	if _, err = m.cluster.dataNode(offLineAddr); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataNodeNotExists))
+		return
+	}
+
+	if err = m.preCheckDecommissionUnlessForced(w, r, offLineAddr, ""); err != nil {
+		return
+	}
+
+	// the paced decommission runs in the background, the job can be canceled by cancelDecommission
+	if rate > 0 {
+		if jobID, err = m.cluster.startDataNodeDecommission(offLineAddr, limit, rate); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+		sendOkReply(w, r, newSuccessHTTPReply(&proto.DataNodeDecommissionJob{JobID: jobID, Addr: offLineAddr, Rate: rate}))
+		return
+	}
+
+	if jobID, err = m.cluster.migrateDataNode(offLineAddr, "", limit); err != nil {
+		sendErrReply(w, r, newErrJobHTTPReply(err, jobID))
+		return
+	}
+
+	rstMsg = fmt.Sprintf("decommission data node [%v] limit %d successfully, job[%v]", offLineAddr, limit, jobID)
+	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
+}
+
+func (m *Server) migrateDataNodeHandler(w http.ResponseWriter, r *http.Request) {
+	srcAddr, targetAddr, limit, err := parseMigrateNodeParam(r)
+	if err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	if limit > defaultMigrateDpCnt {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError,
+			Msg: fmt.Sprintf("limit %d can't be bigger than %d", limit, defaultMigrateDpCnt)})
+		return
+	}
+
+	srcNode, err := m.cluster.dataNode(srcAddr)
+	if err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeDataNodeNotExists, Msg: err.Error()})
+		return
+	}
+
+	targetNode, err := m.cluster.dataNode(targetAddr)
+	if err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeDataNodeNotExists, Msg: err.Error()})
+		return
+	}
+
+	if srcNode.NodeSetID != targetNode.NodeSetID {
+		err = fmt.Errorf("src %s and target %s must exist in the same nodeSet when migrate", srcAddr, targetAddr)
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	if !targetNode.isWriteAble() {
+		err = fmt.Errorf("[%s] is not writable, can't used as target addr for migrate", targetAddr)
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	jobID, err := m.cluster.migrateDataNode(srcAddr, targetAddr, limit)
+	if err != nil {
+		sendErrReply(w, r, newErrJobHTTPReply(err, jobID))
+		return
+	}
+
+	rstMsg := fmt.Sprintf("migrateDataNodeHandler from src [%v] to target[%v] has migrate successfully, job[%v]", srcAddr, targetAddr, jobID)
+	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
+}
+
+func (m *Server) setNodeInfoHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		params map[string]interface{}
+		err    error
+	)
+	if params, err = parseAndExtractSetNodeInfoParams(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+
+	if batchCount, ok := params[nodeDeleteBatchCountKey]; ok {
+		if bc, ok := batchCount.(uint64); ok {
+			if err = m.cluster.setMetaNodeDeleteBatchCount(bc); err != nil {
+				sendErrReply(w, r, newErrHTTPReply(err))
+				return
+			}
+		}
+	}
+	if val, ok := params[nodeMarkDeleteRateKey]; ok {
+		if v, ok := val.(uint64); ok {
+			if err = m.cluster.setDataNodeDeleteLimitRate(v); err != nil {
+				sendErrReply(w, r, newErrHTTPReply(err))
+				return
+			}
+		}
+	}
+
+	if val, ok := params[nodeAutoRepairRateKey]; ok {
+		if v, ok := val.(uint64); ok {
 			if err = m.cluster.setDataNodeAutoRepairLimitRate(v); err != nil {
 				sendErrReply(w, r, newErrHTTPReply(err))
 				return
@@ -1338,6 +2682,83 @@ func parseNodeType(r *http.Request) (nodeType int, err error) {
 	return
 }
 
+// Set the detail level of the node heartbeats, the supported levels are:
+//  1. minimal, the default, the nodes report only what the master needs to manage the partitions,
+//  2. verbose, the data nodes also report the per-disk stats, shown as DiskReports by getDataNode.
+func (m *Server) setHeartbeatVerbosity(w http.ResponseWriter, r *http.Request) {
+	var (
+		verbosity string
+		err       error
+	)
+	if verbosity, err = parseAndExtractHeartbeatVerbosity(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.setHeartbeatVerbosity(verbosity); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set heartbeat verbosity to %v successfully", verbosity)))
+}
+
+func (m *Server) setAutoRebalancePolicy(w http.ResponseWriter, r *http.Request) {
+	var (
+		policy *proto.AutoRebalancePolicy
+		err    error
+	)
+	if policy, err = parseRequestToSetAutoRebalancePolicy(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if err = m.cluster.setAutoRebalancePolicy(policy); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set auto rebalance policy to %v successfully", *policy)))
+}
+
+// Turn on or off the automatic decommission of the data nodes without heartbeat for longer than the timeout.
+func (m *Server) setAutoDecommission(w http.ResponseWriter, r *http.Request) {
+	var (
+		policy *proto.AutoDecommissionPolicy
+		err    error
+	)
+	if policy, err = parseRequestToSetAutoDecommission(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("setAutoDecommission", r.RemoteAddr, fmt.Sprintf("%v", *policy), err) }()
+	if err = m.cluster.setAutoDecommissionPolicy(policy); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("set auto decommission policy to %v successfully", *policy)))
+}
+
+// Set the tags of many nodes by one request, the body is a json array of proto.NodeTags.
+// The failure of a node does not stop the others, the result of each node is returned.
+func (m *Server) batchSetNodeTags(w http.ResponseWriter, r *http.Request) {
+	var (
+		items []*proto.NodeTags
+		err   error
+	)
+	if items, err = parseRequestToBatchSetNodeTags(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	results := make([]*proto.NodeTagsResult, 0, len(items))
+	for _, item := range items {
+		result := &proto.NodeTagsResult{Addr: item.Addr, Success: true}
+		if err = m.cluster.setNodeTags(item.Addr, item.Tags); err != nil {
+			log.LogErrorf("[batchSetNodeTags] set tags %v of node %s, err (%s)", item.Tags, item.Addr, err.Error())
+			result.Success = false
+			result.Msg = err.Error()
+		}
+		results = append(results, result)
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(results))
+}
+
 func (m *Server) setNodeRdOnlyHandler(w http.ResponseWriter, r *http.Request) {
 
 	addr, nodeType, rdOnly, err := parseSetNodeRdOnlyParam(r)
@@ -1573,28 +2994,47 @@ func (m *Server) decommissionDisk(w http.ResponseWriter, r *http.Request) {
 		node                  *DataNode
 		rstMsg                string
 		offLineAddr, diskPath string
+		jobID                 string
 		err                   error
 		limit                 int
 		badPartitionIds       []uint64
 		badPartitions         []*DataPartition
+		dryRun                bool
 	)
 
 	if offLineAddr, diskPath, limit, err = parseReqToDecoDisk(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	if dryRun, err = extractDryRun(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if dryRun {
+		m.dryRunDecommissionDisk(w, r, offLineAddr, diskPath, limit)
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("decommissionDisk", r.RemoteAddr, offLineAddr+":"+diskPath, err) }()
 
 	if node, err = m.cluster.dataNode(offLineAddr); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataNodeNotExists))
 		return
 	}
+	if err = m.preCheckDecommissionUnlessForced(w, r, offLineAddr, diskPath); err != nil {
+		return
+	}
 	badPartitions = node.badPartitions(diskPath, m.cluster)
 	if len(badPartitions) == 0 {
+		if err = m.cluster.markDiskDecommissioned(node, diskPath); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
 		rstMsg = fmt.Sprintf("receive decommissionDisk node[%v] no any partitions on disk[%v],offline successfully",
 			node.Addr, diskPath)
 		sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 		return
 	}
+	total := len(badPartitions)
 	for _, bdp := range badPartitions {
 		badPartitionIds = append(badPartitionIds, bdp.PartitionID)
 	}
@@ -1604,19 +3044,139 @@ func (m *Server) decommissionDisk(w http.ResponseWriter, r *http.Request) {
 		badPartitions = badPartitions[:limit]
 	}
 
-	rstMsg = fmt.Sprintf("receive decommissionDisk node[%v] disk[%v] limit [%d], badPartitionIds[%v] has offline successfully",
-		node.Addr, diskPath, limit, badPartitionIds)
-	if err = m.cluster.decommissionDisk(node, diskPath, badPartitions); err != nil {
-		sendErrReply(w, r, newErrHTTPReply(err))
+	if jobID, err = m.cluster.decommissionDisk(node, diskPath, badPartitions); err != nil {
+		sendErrReply(w, r, newErrJobHTTPReply(err, jobID))
 		return
 	}
+	rstMsg = fmt.Sprintf("receive decommissionDisk node[%v] disk[%v] limit [%d], badPartitionIds[%v] has offline successfully, job[%v]",
+		node.Addr, diskPath, limit, badPartitionIds, jobID)
+	// the disk is taken out of service only if no partition is left on it
+	if len(badPartitions) == total {
+		if err = m.cluster.markDiskDecommissioned(node, diskPath); err != nil {
+			sendErrReply(w, r, newErrHTTPReply(err))
+			return
+		}
+	}
 	Warn(m.clusterName, rstMsg)
 	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 }
 
+// Reply the moves the decommission of the disk would make along with its pre-check, nothing is moved or audited.
+func (m *Server) dryRunDecommissionDisk(w http.ResponseWriter, r *http.Request, addr, diskPath string, limit int) {
+	var (
+		node     *DataNode
+		preCheck *proto.DecommissionPreCheck
+		err      error
+	)
+	if node, err = m.cluster.dataNode(addr); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrDataNodeNotExists))
+		return
+	}
+	if preCheck, err = m.cluster.preCheckDecommission(addr, diskPath); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	partitions := node.badPartitions(diskPath, m.cluster)
+	if limit > 0 && limit < len(partitions) {
+		partitions = partitions[:limit]
+	}
+	result := m.cluster.dryRunDecommission(addr, diskPath, partitions)
+	result.PreCheck = preCheck
+	sendOkReply(w, r, newSuccessHTTPReply(result))
+}
+
+// Put a disk decommissioned back into service, the space of the disk is returned.
+func (m *Server) recommissionDisk(w http.ResponseWriter, r *http.Request) {
+	var (
+		addr, diskPath string
+		disk           *proto.DataNodeDisk
+		err            error
+	)
+	if addr, diskPath, _, err = parseReqToDecoDisk(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("recommissionDisk", r.RemoteAddr, addr+":"+diskPath, err) }()
+	if disk, err = m.cluster.recommissionDisk(addr, diskPath); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(disk))
+}
+
+// Cancel the decommission of a disk in progress, the data partitions already moved stay on the new replicas.
+func (m *Server) cancelDecommissionDisk(w http.ResponseWriter, r *http.Request) {
+	var (
+		addr, diskPath string
+		jobID          string
+		pending        int
+		err            error
+	)
+	if addr, diskPath, _, err = parseReqToDecoDisk(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("cancelDecommissionDisk", r.RemoteAddr, addr+":"+diskPath, err) }()
+	if jobID, pending, err = m.cluster.cancelDiskDecommission(addr, diskPath); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(&proto.DiskDecommissionCancellation{JobID: jobID, Addr: addr, DiskPath: diskPath, PendingPartitions: pending}))
+}
+
+func (m *Server) cancelDecommissionDataNode(w http.ResponseWriter, r *http.Request) {
+	var (
+		addr    string
+		jobID   string
+		pending int
+		err     error
+	)
+	if addr, err = parseAndExtractNodeAddr(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("cancelDecommissionDataNode", r.RemoteAddr, addr, err) }()
+	if jobID, pending, err = m.cluster.cancelDataNodeDecommission(addr); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(&proto.DataNodeDecommissionCancellation{JobID: jobID, Addr: addr, PendingPartitions: pending}))
+}
+
+// Return the progress of the job with the id, or all the jobs retained if the id is not given.
+func (m *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	var (
+		job *asyncJob
+		err error
+	)
+	if err = r.ParseForm(); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	id := r.FormValue(idKey)
+	if id == "" {
+		sendOkReply(w, r, newSuccessHTTPReply(m.cluster.jobs.list()))
+		return
+	}
+	if job, err = m.cluster.jobs.get(id); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(job.toView()))
+}
+
 // handle tasks such as heartbeat，loadDataPartition，deleteDataPartition, etc.
 func (m *Server) handleDataNodeTaskResponse(w http.ResponseWriter, r *http.Request) {
-	tr, err := parseRequestToGetTaskResponse(r)
+	if !m.taskResponseLimiter.allow(r.RemoteAddr) {
+		sendTooManyTaskResponses(w, r)
+		return
+	}
+	tr, err := parseRequestToGetTaskResponse(w, r, m.config.maxTaskResponseBytes)
+	if err == errTaskResponseTooLarge {
+		sendErrReplyWithStatus(w, r, http.StatusRequestEntityTooLarge,
+			&proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: fmt.Sprintf("%v, the limit is %v bytes", err, m.config.maxTaskResponseBytes)})
+		return
+	}
 	if err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
@@ -1625,6 +3185,15 @@ func (m *Server) handleDataNodeTaskResponse(w http.ResponseWriter, r *http.Reque
 	go m.cluster.handleDataNodeTaskResponse(tr.OperatorAddr, tr)
 }
 
+// The existing id is carried in the data of the reply, so that the caller can tell the node was registered before.
+func sendDuplicateNodeReply(w http.ResponseWriter, r *http.Request, nodeAddr string, id uint64) {
+	sendErrReply(w, r, &proto.HTTPReply{
+		Code: proto.ErrCodeDuplicateNode,
+		Msg:  fmt.Sprintf("node[%v] already exists with id[%v]", nodeAddr, id),
+		Data: id,
+	})
+}
+
 func (m *Server) addMetaNode(w http.ResponseWriter, r *http.Request) {
 	var (
 		nodeAddr  string
@@ -1649,7 +3218,11 @@ func (m *Server) addMetaNode(w http.ResponseWriter, r *http.Request) {
 			sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		}
 	}
-	if id, err = m.cluster.addMetaNode(nodeAddr, zoneName, nodesetId); err != nil {
+	if id, err = m.cluster.addMetaNode(nodeAddr, zoneName, nodesetId); err == proto.ErrDuplicateNode {
+		sendDuplicateNodeReply(w, r, nodeAddr, id)
+		return
+	}
+	if err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
@@ -1695,6 +3268,34 @@ func (m *Server) updateMetaNode(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(id))
 }
 
+// List the meta partitions with a replica on the meta node across all the volumes, such as before it is decommissioned.
+func (m *Server) getMetaNodePartitions(w http.ResponseWriter, r *http.Request) {
+	var (
+		nodeAddr string
+		err      error
+	)
+	if nodeAddr, err = parseAndExtractNodeAddr(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if _, err = m.cluster.metaNode(nodeAddr); err != nil {
+		sendErrReplyWithStatus(w, r, http.StatusNotFound, newErrHTTPReply(proto.ErrMetaNodeNotExists))
+		return
+	}
+	partitions := m.cluster.getAllMetaPartitionByMetaNode(nodeAddr)
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].PartitionID < partitions[j].PartitionID })
+	views := make([]*proto.NodeMetaPartition, 0, len(partitions))
+	for _, mp := range partitions {
+		mpView := getMetaPartitionView(mp)
+		views = append(views, &proto.NodeMetaPartition{
+			MetaPartitionView: mpView,
+			VolName:           mp.volName,
+			IsLeader:          mpView.LeaderAddr == nodeAddr,
+		})
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(views))
+}
+
 func (m *Server) getMetaNode(w http.ResponseWriter, r *http.Request) {
 	var (
 		nodeAddr     string
@@ -1730,6 +3331,7 @@ func (m *Server) getMetaNode(w http.ResponseWriter, r *http.Request) {
 		NodeSetID:                 metaNode.NodeSetID,
 		PersistenceMetaPartitions: metaNode.PersistenceMetaPartitions,
 		RdOnly:                    metaNode.RdOnly,
+		Tags:                      metaNode.Tags,
 	}
 	sendOkReply(w, r, newSuccessHTTPReply(metaNodeInfo))
 }
@@ -1758,14 +3360,52 @@ func (m *Server) decommissionMetaPartition(w http.ResponseWriter, r *http.Reques
 	sendOkReply(w, r, newSuccessHTTPReply(msg))
 }
 
-func (m *Server) loadMetaPartition(w http.ResponseWriter, r *http.Request) {
+// Split the meta partition at the inode, a new meta partition takes the inodes above it.
+func (m *Server) splitMetaPartition(w http.ResponseWriter, r *http.Request) {
 	var (
-		msg         string
-		mp          *MetaPartition
 		partitionID uint64
+		inode       uint64
+		mp          *MetaPartition
+		nextMp      *MetaPartition
 		err         error
 	)
-
+	if partitionID, inode, err = parseRequestToSplitMetaPartition(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if mp, err = m.cluster.getMetaPartitionByID(partitionID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrMetaPartitionNotExists))
+		return
+	}
+	mp.RLock()
+	start, end := mp.Start, mp.End
+	mp.RUnlock()
+	if inode < start || inode >= end {
+		err = fmt.Errorf("inode[%v] is not in the range [%v, %v) of meta partition[%v]", inode, start, end, partitionID)
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	if nextMp, err = m.cluster.splitMetaPartition(mp, inode); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(&proto.MetaPartitionSplit{
+		PartitionID:    partitionID,
+		End:            inode,
+		NewPartitionID: nextMp.PartitionID,
+		NewStart:       nextMp.Start,
+		NewEnd:         nextMp.End,
+	}))
+}
+
+func (m *Server) loadMetaPartition(w http.ResponseWriter, r *http.Request) {
+	var (
+		msg         string
+		mp          *MetaPartition
+		partitionID uint64
+		err         error
+	)
+
 	if partitionID, err = parseRequestToLoadMetaPartition(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
@@ -1811,6 +3451,47 @@ func parseMigrateNodeParam(r *http.Request) (srcAddr, targetAddr string, limit i
 	return
 }
 
+func parseRequestToMigrateDataPartition(r *http.Request) (partitionID uint64, srcAddr, targetAddr string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if partitionID, err = extractDataPartitionID(r); err != nil {
+		return
+	}
+	if srcAddr = r.FormValue(srcAddrKey); srcAddr == "" {
+		err = keyNotFound(srcAddrKey)
+		return
+	}
+	if targetAddr = r.FormValue(targetAddrKey); targetAddr == "" {
+		err = keyNotFound(targetAddrKey)
+		return
+	}
+	if srcAddr == targetAddr {
+		err = fmt.Errorf("%v and %v should be different", srcAddrKey, targetAddrKey)
+		return
+	}
+	return
+}
+
+func parseRequestToRebalanceDataPartitions(r *http.Request, maxConcurrentMoves int) (limit int, dryRun bool, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	var value string
+	if value = r.FormValue(maxMovesKey); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			err = unmatchedKey(maxMovesKey)
+			return
+		}
+		if limit <= 0 || limit > maxConcurrentMoves {
+			err = fmt.Errorf("%v[%v] should be in [1, %v]", maxMovesKey, limit, maxConcurrentMoves)
+			return
+		}
+	}
+	dryRun, err = extractDryRun(r)
+	return
+}
+
 func parseUintParam(r *http.Request, key string) (num int, err error) {
 	val := r.FormValue(key)
 	if val == "" {
@@ -1865,12 +3546,13 @@ func (m *Server) migrateMetaNodeHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err = m.cluster.migrateMetaNode(srcAddr, targetAddr, limit); err != nil {
-		sendErrReply(w, r, newErrHTTPReply(err))
+	jobID, err := m.cluster.migrateMetaNode(srcAddr, targetAddr, limit)
+	if err != nil {
+		sendErrReply(w, r, newErrJobHTTPReply(err, jobID))
 		return
 	}
 
-	rstMsg := fmt.Sprintf("migrateMetaNodeHandler from src [%v] to targaet[%s] has migrate successfully", srcAddr, targetAddr)
+	rstMsg := fmt.Sprintf("migrateMetaNodeHandler from src [%v] to targaet[%s] has migrate successfully, job[%v]", srcAddr, targetAddr, jobID)
 	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 }
 
@@ -1879,6 +3561,7 @@ func (m *Server) decommissionMetaNode(w http.ResponseWriter, r *http.Request) {
 		rstMsg      string
 		offLineAddr string
 		limit       int
+		jobID       string
 		err         error
 	)
 
@@ -1886,21 +3569,31 @@ func (m *Server) decommissionMetaNode(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	defer func() { m.cluster.addAuditEvent("decommissionMetaNode", r.RemoteAddr, offLineAddr, err) }()
 
 	if _, err = m.cluster.metaNode(offLineAddr); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrMetaNodeNotExists))
 		return
 	}
-	if err = m.cluster.migrateMetaNode(offLineAddr, "", limit); err != nil {
-		sendErrReply(w, r, newErrHTTPReply(err))
+	if jobID, err = m.cluster.migrateMetaNode(offLineAddr, "", limit); err != nil {
+		sendErrReply(w, r, newErrJobHTTPReply(err, jobID))
 		return
 	}
-	rstMsg = fmt.Sprintf("decommissionMetaNode metaNode [%v] limit %d has offline successfully", offLineAddr, limit)
+	rstMsg = fmt.Sprintf("decommissionMetaNode metaNode [%v] limit %d has offline successfully, job[%v]", offLineAddr, limit, jobID)
 	sendOkReply(w, r, newSuccessHTTPReply(rstMsg))
 }
 
 func (m *Server) handleMetaNodeTaskResponse(w http.ResponseWriter, r *http.Request) {
-	tr, err := parseRequestToGetTaskResponse(r)
+	if !m.taskResponseLimiter.allow(r.RemoteAddr) {
+		sendTooManyTaskResponses(w, r)
+		return
+	}
+	tr, err := parseRequestToGetTaskResponse(w, r, m.config.maxTaskResponseBytes)
+	if err == errTaskResponseTooLarge {
+		sendErrReplyWithStatus(w, r, http.StatusRequestEntityTooLarge,
+			&proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: fmt.Sprintf("%v, the limit is %v bytes", err, m.config.maxTaskResponseBytes)})
+		return
+	}
 	if err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
@@ -1919,6 +3612,7 @@ func (m *Server) addRaftNode(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	defer func() { m.cluster.addAuditEvent("addRaftNode", r.RemoteAddr, addr, err) }()
 
 	if err = m.cluster.addRaftNode(id, addr); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
@@ -1928,6 +3622,78 @@ func (m *Server) addRaftNode(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(msg))
 }
 
+// Query the raft status seen by this master, the leader knows the progress of all the peers.
+func (m *Server) getRaftStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := m.cluster.getRaftStatus()
+	if err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(status))
+}
+
+// Move the leadership of the masters to the given peer, which should be caught up with the leader.
+func (m *Server) transferRaftLeader(w http.ResponseWriter, r *http.Request) {
+	var (
+		id   uint64
+		addr string
+		err  error
+	)
+	if id, err = parseRequestToTransferLeader(r); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	defer func() { m.cluster.addAuditEvent("transferRaftLeader", r.RemoteAddr, fmt.Sprintf("%v", id), err) }()
+	if addr, err = m.transferLeader(id); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("master[%v] addr[%v] is campaigning for the leadership", id, addr)))
+}
+
+// Give up the leadership of the master requested, the successor is chosen by the master itself.
+func (m *Server) stepDownRaftLeader(w http.ResponseWriter, r *http.Request) {
+	var (
+		id   uint64
+		addr string
+		err  error
+	)
+	defer func() { m.cluster.addAuditEvent("stepDownRaftLeader", r.RemoteAddr, fmt.Sprintf("%v", m.id), err) }()
+	if id, addr, err = m.stepDownLeader(); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("master[%v] steps down, master[%v] addr[%v] is campaigning for the leadership", m.id, id, addr)))
+}
+
+// Campaign for the leadership, it is requested by the leader transferring the leadership to this master.
+func (m *Server) tryToRaftLeader(w http.ResponseWriter, r *http.Request) {
+	if m.partition.IsRaftLeader() {
+		sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("master[%v] is the leader already", m.id)))
+		return
+	}
+	if err := m.partition.TryToLeader(GroupID); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	log.LogWarnf("action[tryToRaftLeader] master[%v] campaigns for the leadership, requested by remoteAddr[%v] requestID[%v]", m.id, r.RemoteAddr, requestID(r))
+	sendOkReply(w, r, newSuccessHTTPReply(fmt.Sprintf("master[%v] is campaigning for the leadership", m.id)))
+}
+
+// Compact the raft log of the master requested at once, rather than waiting for retainLogs entries to be applied.
+func (m *Server) snapshotRaftLog(w http.ResponseWriter, r *http.Request) {
+	var (
+		view *proto.RaftSnapshotView
+		err  error
+	)
+	defer func() { m.cluster.addAuditEvent("snapshotRaftLog", r.RemoteAddr, fmt.Sprintf("%v", m.id), err) }()
+	if view, err = m.cluster.compactRaftLog(); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(err))
+		return
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(view))
+}
+
 // Dynamically remove a master node. Similar to addRaftNode, this operation is performed online.
 func (m *Server) removeRaftNode(w http.ResponseWriter, r *http.Request) {
 	var msg string
@@ -1936,6 +3702,7 @@ func (m *Server) removeRaftNode(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	defer func() { m.cluster.addAuditEvent("removeRaftNode", r.RemoteAddr, addr, err) }()
 	err = m.cluster.removeRaftNode(id, addr)
 	if err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
@@ -1945,6 +3712,23 @@ func (m *Server) removeRaftNode(w http.ResponseWriter, r *http.Request) {
 	sendOkReply(w, r, newSuccessHTTPReply(msg))
 }
 
+// Parse the request that transfers the leadership to the raft node of the id.
+func parseRequestToTransferLeader(r *http.Request) (id uint64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	var value string
+	if value = r.FormValue(idKey); value == "" {
+		err = keyNotFound(idKey)
+		return
+	}
+	if id, err = strconv.ParseUint(value, 10, 64); err != nil {
+		err = unmatchedKey(idKey)
+		return
+	}
+	return
+}
+
 // Parse the request that adds/deletes a raft node.
 func parseRequestForRaftNode(r *http.Request) (id uint64, host string, err error) {
 	if err = r.ParseForm(); err != nil {
@@ -1956,438 +3740,1260 @@ func parseRequestForRaftNode(r *http.Request) (id uint64, host string, err error
 		return
 	}
 
-	if id, err = strconv.ParseUint(idStr, 10, 64); err != nil {
+	if id, err = strconv.ParseUint(idStr, 10, 64); err != nil {
+		return
+	}
+	if host = r.FormValue(addrKey); host == "" {
+		err = keyNotFound(addrKey)
+		return
+	}
+
+	if arr := strings.Split(host, colonSplit); len(arr) < 2 {
+		err = unmatchedKey(addrKey)
+		return
+	}
+	return
+}
+
+func parseRequestForUpdateMetaNode(r *http.Request) (nodeAddr string, id uint64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if nodeAddr, err = extractNodeAddr(r); err != nil {
+		return
+	}
+	if id, err = extractNodeID(r); err != nil {
+		return
+	}
+	return
+}
+
+func parseRequestForAddNode(r *http.Request) (nodeAddr, zoneName string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if nodeAddr, err = extractNodeAddr(r); err != nil {
+		return
+	}
+	if zoneName = r.FormValue(zoneNameKey); zoneName == "" {
+		zoneName = DefaultZoneName
+	}
+	return
+}
+
+func parseDecomNodeReq(r *http.Request) (nodeAddr string, limit int, err error) {
+	nodeAddr, err = parseAndExtractNodeAddr(r)
+	if err != nil {
+		return
+	}
+
+	limit, err = parseUintParam(r, countKey)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func parseAndExtractNodeAddr(r *http.Request) (nodeAddr string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	return extractNodeAddr(r)
+}
+
+func parseRequestToPreCheckDecommission(r *http.Request) (nodeAddr, diskPath string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if nodeAddr, err = extractNodeAddr(r); err != nil {
+		return
+	}
+	diskPath = r.FormValue(diskPathKey)
+	return
+}
+
+func parseRequestToSimulateNodeSetFailure(r *http.Request) (id uint64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	var value string
+	if value = r.FormValue(idKey); value == "" {
+		err = keyNotFound(idKey)
+		return
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func parseRequestToGetNodeSet(r *http.Request) (id uint64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	var value string
+	if value = r.FormValue(idKey); value == "" {
+		err = keyNotFound(idKey)
+		return
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func parseRequestToCreateNodeSet(r *http.Request, defaultCapacity int) (zoneName string, capacity int, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if zoneName = r.FormValue(zoneNameKey); zoneName == "" {
+		zoneName = DefaultZoneName
+	}
+	if capacity, err = parseUintParam(r, nodeSetCapacityKey); err != nil {
+		return
+	}
+	if capacity == 0 {
+		capacity = defaultCapacity
+	}
+	if capacity < defaultReplicaNum {
+		err = fmt.Errorf("parameter %v should not be less than %v, received %v", nodeSetCapacityKey, defaultReplicaNum, capacity)
+	}
+	return
+}
+
+func parseRequestToMoveNodeToNodeSet(r *http.Request) (addr, nodeType string, id uint64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if addr, err = extractNodeAddr(r); err != nil {
+		return
+	}
+	if nodeType = r.FormValue(typeKey); nodeType != topologyTypeData && nodeType != topologyTypeMeta {
+		err = fmt.Errorf("parameter %v should be %v or %v, received %v", typeKey, topologyTypeData, topologyTypeMeta, nodeType)
+		return
+	}
+	id, err = extractNodeID(r)
+	return
+}
+
+func parseReqToDecoDisk(r *http.Request) (nodeAddr, diskPath string, limit int, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	nodeAddr, err = extractNodeAddr(r)
+	if err != nil {
+		return
+	}
+	diskPath, err = extractDiskPath(r)
+	if err != nil {
+		return
+	}
+
+	limit, err = parseUintParam(r, countKey)
+	return
+}
+
+func sendTooManyTaskResponses(w http.ResponseWriter, r *http.Request) {
+	log.LogWarnf("action[handleTaskResponse] reject remoteAddr[%v] requestID[%v], too many task responses", r.RemoteAddr, requestID(r))
+	sendErrReplyWithStatus(w, r, http.StatusTooManyRequests,
+		&proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: "too many task responses from the node"})
+}
+
+var errTaskResponseTooLarge = errors.New("the task response is too large")
+
+// The body is capped by maxBytes, a buggy node sending a huge task response should not run the master out of memory.
+func parseRequestToGetTaskResponse(w http.ResponseWriter, r *http.Request, maxBytes int64) (tr *proto.AdminTask, err error) {
+	var body []byte
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if body, err = ioutil.ReadAll(r.Body); err != nil {
+		if int64(len(body)) >= maxBytes {
+			err = errTaskResponseTooLarge
+		}
+		return
+	}
+	tr = &proto.AdminTask{}
+	decoder := json.NewDecoder(bytes.NewBuffer([]byte(body)))
+	decoder.UseNumber()
+	err = decoder.Decode(tr)
+	return
+}
+
+func parseVolName(r *http.Request) (name string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if name, err = extractName(r); err != nil {
+		return
+	}
+	return
+}
+
+type getVolParameter struct {
+	name                string
+	authKey             string
+	skipOwnerValidation bool
+}
+
+func parseGetVolParameter(r *http.Request) (p *getVolParameter, err error) {
+	p = &getVolParameter{}
+	skipOwnerValidationVal := r.Header.Get(proto.SkipOwnerValidation)
+	if len(skipOwnerValidationVal) > 0 {
+		if p.skipOwnerValidation, err = strconv.ParseBool(skipOwnerValidationVal); err != nil {
+			return
+		}
+	}
+	if p.name = r.FormValue(nameKey); p.name == "" {
+		err = keyNotFound(nameKey)
+		return
+	}
+	if err = checkVolName(p.name); err != nil {
+		return
+	}
+	if p.authKey = r.FormValue(volAuthKey); !p.skipOwnerValidation && len(p.authKey) == 0 {
+		err = keyNotFound(volAuthKey)
+		return
+	}
+	return
+}
+
+func parseVolNameAndAuthKey(r *http.Request) (name, authKey string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if name, err = extractName(r); err != nil {
+		return
+	}
+	if authKey, err = extractAuthKey(r); err != nil {
+		return
+	}
+	return
+
+}
+
+func parseRequestToDeleteVol(r *http.Request) (name, authKey string, err error) {
+	return parseVolNameAndAuthKey(r)
+
+}
+
+func parseRequestToRenameVol(r *http.Request) (name, newName, authKey string, err error) {
+	if name, authKey, err = parseVolNameAndAuthKey(r); err != nil {
+		return
+	}
+	if newName = r.FormValue(newNameKey); newName == "" {
+		err = keyNotFound(newNameKey)
+		return
+	}
+	if err = checkVolName(newName); err != nil {
+		return
+	}
+	if newName == name {
+		err = fmt.Errorf("newName is the same as the name of vol[%v]", name)
+	}
+	return
+}
+
+func parseRequestToUpdateVol(r *http.Request) (name, authKey, description string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if name, err = extractName(r); err != nil {
+		return
+	}
+	if authKey, err = extractAuthKey(r); err != nil {
+		return
+	}
+	description = r.FormValue(descriptionKey)
+	return
+}
+
+func parseDefaultInfoToUpdateVol(r *http.Request, vol *Vol) (zoneName string, capacity uint64, replicaNum int,
+	dpSelectorName string, dpSelectorParm string, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if zoneName = r.FormValue(zoneNameKey); zoneName == "" {
+		zoneName = vol.zoneName
+	}
+	if capacityStr := r.FormValue(volCapacityKey); capacityStr != "" {
+		var capacityInt int
+		if capacityInt, err = strconv.Atoi(capacityStr); err != nil {
+			err = unmatchedKey(volCapacityKey)
+			return
+		}
+		capacity = uint64(capacityInt)
+	} else {
+		capacity = vol.Capacity
+	}
+	if replicaNumStr := r.FormValue(replicaNumKey); replicaNumStr != "" {
+		if replicaNum, err = strconv.Atoi(replicaNumStr); err != nil {
+			err = unmatchedKey(replicaNumKey)
+			return
+		}
+	} else {
+		replicaNum = int(vol.dpReplicaNum)
+	}
+	dpSelectorName = r.FormValue(dpSelectorNameKey)
+	dpSelectorParm = r.FormValue(dpSelectorParmKey)
+	if (dpSelectorName == "") || (dpSelectorParm == "") {
+		if (dpSelectorName != "") || (dpSelectorParm != "") {
+			err = keyNotFound(dpSelectorNameKey + " or " + dpSelectorParmKey)
+			return
+		}
+		dpSelectorName = vol.dpSelectorName
+		dpSelectorParm = vol.dpSelectorParm
+	}
+	return
+}
+
+func parseBoolFieldToUpdateVol(r *http.Request, vol *Vol) (followerRead, authenticate bool, err error) {
+	if followerReadStr := r.FormValue(followerReadKey); followerReadStr != "" {
+		if followerRead, err = strconv.ParseBool(followerReadStr); err != nil {
+			err = unmatchedKey(followerReadKey)
+			return
+		}
+	} else {
+		followerRead = vol.FollowerRead
+	}
+	if authenticateStr := r.FormValue(authenticateKey); authenticateStr != "" {
+		if authenticate, err = strconv.ParseBool(authenticateStr); err != nil {
+			err = unmatchedKey(authenticateKey)
+			return
+		}
+	} else {
+		authenticate = vol.authenticate
+	}
+	return
+}
+
+func parseRequestToSetVolCapacity(r *http.Request) (name, authKey string, capacity int, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if name, err = extractName(r); err != nil {
+		return
+	}
+	if authKey, err = extractAuthKey(r); err != nil {
+		return
+	}
+	if capacity, err = extractCapacity(r); err != nil {
+		return
+	}
+	return
+}
+
+// the parameters accepted by createVol
+var createVolParamKeys = []string{
+	nameKey, volOwnerKey, metaPartitionCountKey, replicaNumKey, dataPartitionSizeKey, volCapacityKey,
+	followerReadKey, authenticateKey, crossZoneKey, defaultPriority, zoneNameKey, zoneKey, descriptionKey,
+	failIfExistsKey, tagsKey, bandwidthLimitKey, iopsLimitKey, tokenKey,
+}
+
+// The strict parameter check is enabled by the config or by the header of the request.
+func (m *Server) isStrictParamCheck(r *http.Request) bool {
+	if m.config.strictParamCheck {
+		return true
+	}
+	strict, _ := strconv.ParseBool(r.Header.Get(proto.HeadStrictParamCheck))
+	return strict
+}
+
+// return an error listing the parameters of the request which are not in the known keys
+func checkUnknownParams(r *http.Request, knownKeys []string) (err error) {
+	known := make(map[string]bool, len(knownKeys))
+	for _, key := range knownKeys {
+		known[key] = true
+	}
+	unknownKeys := make([]string, 0)
+	for key := range r.Form {
+		if !known[key] {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	if len(unknownKeys) == 0 {
+		return
+	}
+	sort.Strings(unknownKeys)
+	return fmt.Errorf("unknown parameters %v", unknownKeys)
+}
+
+func parseRequestToCreateVol(r *http.Request) (name, owner, zoneName, description string,
+	mpCount, dpReplicaNum, size,
+	capacity int, followerRead,
+	authenticate, crossZone, defaultPriority, failIfExists bool,
+	tags map[string]string, bandwidthLimit, iopsLimit uint64, err error) {
+	if isJSONRequest(r) {
+		var req *proto.CreateVolRequest
+		if req, err = parseJSONToCreateVol(r); err != nil {
+			return
+		}
+		return req.Name, req.Owner, req.ZoneName, req.Description,
+			req.MpCount, req.ReplicaNum, req.DataPartitionSize,
+			req.Capacity, req.FollowerRead,
+			req.Authenticate, req.CrossZone, req.DefaultPriority, req.FailIfExists,
+			req.Tags, uint64(req.BandwidthLimit), uint64(req.IopsLimit), nil
+	}
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if name, err = extractName(r); err != nil {
+		return
+	}
+	if owner, err = extractOwner(r); err != nil {
+		return
+	}
+
+	if mpCountStr := r.FormValue(metaPartitionCountKey); mpCountStr != "" {
+		if mpCount, err = strconv.Atoi(mpCountStr); err != nil {
+			mpCount = defaultInitMetaPartitionCount
+		}
+	}
+
+	if replicaStr := r.FormValue(replicaNumKey); replicaStr == "" {
+		dpReplicaNum = defaultReplicaNum
+	} else if dpReplicaNum, err = strconv.Atoi(replicaStr); err != nil {
+		err = unmatchedKey(replicaNumKey)
+		return
+	}
+
+	if sizeStr := r.FormValue(dataPartitionSizeKey); sizeStr != "" {
+		if size, err = strconv.Atoi(sizeStr); err != nil {
+			err = unmatchedKey(dataPartitionSizeKey)
+			return
+		}
+	}
+
+	if capacity, err = extractCapacity(r); err != nil {
+		return
+	}
+
+	if followerRead, err = extractFollowerRead(r); err != nil {
+		return
+	}
+
+	if authenticate, err = extractAuthenticate(r); err != nil {
+		return
+	}
+
+	if crossZone, err = extractCrossZone(r); err != nil {
+		return
+	}
+	if defaultPriority, err = extractDefaulPriority(r); err != nil {
+		return
+	}
+
+	if zoneName, err = extractVolZone(r); err != nil {
+		return
+	}
+	if value := r.FormValue(failIfExistsKey); value != "" {
+		if failIfExists, err = strconv.ParseBool(value); err != nil {
+			err = unmatchedKey(failIfExistsKey)
+			return
+		}
+	}
+	if tags, err = parseVolTags(r.FormValue(tagsKey)); err != nil {
+		return
+	}
+	if bandwidthLimit, err = extractQosLimit(r, bandwidthLimitKey); err != nil {
+		return
+	}
+	if iopsLimit, err = extractQosLimit(r, iopsLimitKey); err != nil {
+		return
+	}
+	description = r.FormValue(descriptionKey)
+	return
+}
+
+// The QoS limit of a volume is non-negative, 0 by default means unlimited.
+func extractQosLimit(r *http.Request, key string) (limit uint64, err error) {
+	var (
+		value string
+		num   int64
+	)
+	if value = r.FormValue(key); value == "" {
+		return
+	}
+	if num, err = strconv.ParseInt(value, 10, 64); err != nil {
+		err = unmatchedKey(key)
+		return
+	}
+	if err = checkQosLimit(key, num); err != nil {
+		return
+	}
+	return uint64(num), nil
+}
+
+func checkQosLimit(key string, limit int64) (err error) {
+	if limit < 0 {
+		return fmt.Errorf("%v[%v] should not be negative", key, limit)
+	}
+	return
+}
+
+// Parse the tags given as a JSON object or as comma separated key=value pairs.
+func parseVolTags(value string) (tags map[string]string, err error) {
+	tags = make(map[string]string)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	if strings.HasPrefix(value, "{") {
+		if err = json.Unmarshal([]byte(value), &tags); err != nil {
+			return nil, fmt.Errorf("invalid %v: %v", tagsKey, err)
+		}
+		return tags, checkVolTags(tags)
+	}
+	for _, pair := range strings.Split(value, commaSplit) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid tag[%v], should be key=value", pair)
+		}
+		tags[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return tags, checkVolTags(tags)
+}
+
+func checkVolTags(tags map[string]string) (err error) {
+	if len(tags) > maxVolTags {
+		return fmt.Errorf("too many tags, at most %v", maxVolTags)
+	}
+	for key, value := range tags {
+		if key == "" || len(key) > maxVolTagKeyLen {
+			return fmt.Errorf("tag key[%v] should have 1 to %v characters", key, maxVolTagKeyLen)
+		}
+		if len(value) > maxVolTagValueLen {
+			return fmt.Errorf("value of tag[%v] should have at most %v characters", key, maxVolTagValueLen)
+		}
+	}
+	return
+}
+
+// The tags filtering the volumes, given as key=value or as key to match any value of it.
+func parseTagFilter(r *http.Request) (tags map[string]string) {
+	tags = make(map[string]string)
+	for _, value := range r.Form[tagKey] {
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) == 2 {
+			tags[kv[0]] = kv[1]
+		} else {
+			tags[kv[0]] = ""
+		}
+	}
+	return
+}
+
+func isJSONRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+}
+
+// The JSON body of createVol is checked by the same rules as the form parameters.
+func parseJSONToCreateVol(r *http.Request) (req *proto.CreateVolRequest, err error) {
+	var body []byte
+	if body, err = ioutil.ReadAll(r.Body); err != nil {
+		return
+	}
+	req = &proto.CreateVolRequest{}
+	if err = json.Unmarshal(body, req); err != nil {
+		return
+	}
+	err = checkCreateVolRequest(req)
+	return
+}
+
+// Check the volume to create given by json, and fill in the default replica number.
+func checkCreateVolRequest(req *proto.CreateVolRequest) (err error) {
+	if req.Name == "" {
+		err = keyNotFound(nameKey)
+		return
+	}
+	if err = checkVolName(req.Name); err != nil {
+		return
+	}
+	if req.Owner == "" {
+		err = keyNotFound(volOwnerKey)
+		return
+	}
+	if err = checkVolOwner(req.Owner); err != nil {
+		return
+	}
+	if err = checkVolTags(req.Tags); err != nil {
+		return
+	}
+	if err = checkQosLimit(bandwidthLimitKey, req.BandwidthLimit); err != nil {
+		return
+	}
+	if err = checkQosLimit(iopsLimitKey, req.IopsLimit); err != nil {
+		return
+	}
+	if req.Capacity == 0 {
+		err = keyNotFound(volCapacityKey)
+		return
+	}
+	if req.ReplicaNum == 0 {
+		req.ReplicaNum = defaultReplicaNum
+	}
+	return
+}
+
+func parseRequestToBatchCreateVol(r *http.Request) (reqs []*proto.CreateVolRequest, err error) {
+	var body []byte
+	if body, err = ioutil.ReadAll(r.Body); err != nil {
+		return
+	}
+	reqs = make([]*proto.CreateVolRequest, 0)
+	if err = json.Unmarshal(body, &reqs); err != nil {
+		return
+	}
+	if len(reqs) == 0 {
+		err = fmt.Errorf("no vol to create")
+		return
+	}
+	if len(reqs) > maxBatchCreateVolCount {
+		err = fmt.Errorf("too many vols to create, at most %v by a request", maxBatchCreateVolCount)
+		return
+	}
+	for i, req := range reqs {
+		if req == nil {
+			err = fmt.Errorf("vol[%v] of the request is null", i)
+			return
+		}
+	}
+	return
+}
+
+// The zone constraining the placement of the partitions is given by zoneName or its short form zone.
+func extractVolZone(r *http.Request) (zoneName string, err error) {
+	zoneName = r.FormValue(zoneNameKey)
+	if zone := r.FormValue(zoneKey); zone != "" {
+		if zoneName != "" && zoneName != zone {
+			err = fmt.Errorf("%v[%v] conflicts with %v[%v]", zoneKey, zone, zoneNameKey, zoneName)
+			return
+		}
+		zoneName = zone
+	}
+	return
+}
+
+func parseRequestToImportVol(r *http.Request) (spec *proto.VolSpec, err error) {
+	var body []byte
+	if body, err = ioutil.ReadAll(r.Body); err != nil {
+		return
+	}
+	spec = &proto.VolSpec{}
+	if err = json.Unmarshal(body, spec); err != nil {
+		return
+	}
+	if spec.Name == "" {
+		err = keyNotFound(nameKey)
+		return
+	}
+	if err = checkVolName(spec.Name); err != nil {
+		return
+	}
+	if spec.Owner == "" {
+		err = keyNotFound(volOwnerKey)
+		return
+	}
+	if !ownerRegexp.MatchString(spec.Owner) {
+		err = errors.New("owner can only be number and letters")
+		return
+	}
+	if spec.IOPriorityClass != "" && !proto.IsValidIOPriorityClass(spec.IOPriorityClass) {
+		err = fmt.Errorf("invalid IOPriorityClass[%v]", spec.IOPriorityClass)
+		return
+	}
+	if err = checkVolTags(spec.Tags); err != nil {
+		return
+	}
+	if spec.DpReplicaNum == 0 {
+		spec.DpReplicaNum = defaultReplicaNum
+	}
+	// the meta partitions are always created with the default replica number
+	if spec.MpReplicaNum == 0 {
+		spec.MpReplicaNum = defaultReplicaNum
+	}
+	if spec.MpReplicaNum != defaultReplicaNum {
+		err = fmt.Errorf("MpReplicaNum[%v] is not supported, the meta partitions are created with %v replicas",
+			spec.MpReplicaNum, defaultReplicaNum)
+		return
+	}
+	return
+}
+
+// The capacity of a volume in GB should be in [minCapacity, maxCapacity], 0 means no bound on that side.
+func checkVolCapacity(capacity, minCapacity, maxCapacity uint64) (err error) {
+	if capacity >= minCapacity && (maxCapacity == 0 || capacity <= maxCapacity) {
+		return
+	}
+	if maxCapacity == 0 {
+		return withCode(proto.ErrCodeParamError, fmt.Errorf("parameter %v not match, it should be at least %vGB, received %vGB",
+			volCapacityKey, minCapacity, capacity))
+	}
+	return withCode(proto.ErrCodeParamError, fmt.Errorf("parameter %v not match, it should be in [%vGB, %vGB], received %vGB",
+		volCapacityKey, minCapacity, maxCapacity, capacity))
+}
+
+// The replica number of the data partitions should be in [2, maxReplicaNum]. The even numbers greater than 2
+// are rejected, they raise the raft quorum without tolerating more failed replicas than the odd number below.
+// 2 is kept for the two replica volumes, a failed replica blocks the writes of them.
+func checkReplicaNum(replicaNum, maxReplicaNum int) (err error) {
+	if replicaNum < minReplicaNum || replicaNum > maxReplicaNum || (replicaNum > 2 && replicaNum%2 == 0) {
+		return withCode(proto.ErrCodeParamError, fmt.Errorf("parameter %v not match, it should be 2 or an odd number in [3, %v], received %v",
+			replicaNumKey, maxReplicaNum, replicaNum))
+	}
+	if replicaNum == 2 {
+		log.LogWarnf("action[checkReplicaNum] a volume with 2 replicas can not write when a replica fails")
+	}
+	return
+}
+
+func parseRequestToSetVolIOPriority(r *http.Request) (name, class string, err error) {
+	if name, err = parseAndExtractName(r); err != nil {
 		return
 	}
-	if host = r.FormValue(addrKey); host == "" {
-		err = keyNotFound(addrKey)
+	if class = r.FormValue(ioPriorityClassKey); class == "" {
+		err = keyNotFound(ioPriorityClassKey)
 		return
 	}
-
-	if arr := strings.Split(host, colonSplit); len(arr) < 2 {
-		err = unmatchedKey(addrKey)
+	if !proto.IsValidIOPriorityClass(class) {
+		err = fmt.Errorf("%v can only be %v, %v or %v", ioPriorityClassKey,
+			proto.IOPriorityHigh, proto.IOPriorityNormal, proto.IOPriorityLow)
 		return
 	}
 	return
 }
 
-func parseRequestForUpdateMetaNode(r *http.Request) (nodeAddr string, id uint64, err error) {
-	if err = r.ParseForm(); err != nil {
+func parseRequestToSetVolAllocPriority(r *http.Request) (name string, priority int, ttl int64, err error) {
+	if name, err = parseAndExtractName(r); err != nil {
 		return
 	}
-	if nodeAddr, err = extractNodeAddr(r); err != nil {
+	var value string
+	if value = r.FormValue(priorityKey); value == "" {
+		err = keyNotFound(priorityKey)
 		return
 	}
-	if id, err = extractNodeID(r); err != nil {
+	if priority, err = strconv.Atoi(value); err != nil || priority < 0 {
+		err = unmatchedKey(priorityKey)
 		return
 	}
+	ttl = defaultVolAllocPriorityTTL
+	if value = r.FormValue(ttlKey); value != "" {
+		if ttl, err = strconv.ParseInt(value, 10, 64); err != nil || ttl <= 0 || ttl > maxVolAllocPriorityTTL {
+			err = fmt.Errorf("%v should be in range (0,%v]", ttlKey, maxVolAllocPriorityTTL)
+			return
+		}
+	}
 	return
 }
 
-func parseRequestForAddNode(r *http.Request) (nodeAddr, zoneName string, err error) {
-	if err = r.ParseForm(); err != nil {
+func parseRequestToReservePartitionIDs(r *http.Request) (name string, count int, err error) {
+	if name, err = parseAndExtractName(r); err != nil {
 		return
 	}
-	if nodeAddr, err = extractNodeAddr(r); err != nil {
+	var value string
+	if value = r.FormValue(countKey); value == "" {
+		err = keyNotFound(countKey)
 		return
 	}
-	if zoneName = r.FormValue(zoneNameKey); zoneName == "" {
-		zoneName = DefaultZoneName
+	if count, err = strconv.Atoi(value); err != nil || count <= 0 || count > maxReservedPartitionIDCount {
+		err = fmt.Errorf("%v should be in range (0,%v]", countKey, maxReservedPartitionIDCount)
+		return
 	}
 	return
 }
 
-func parseDecomNodeReq(r *http.Request) (nodeAddr string, limit int, err error) {
-	nodeAddr, err = parseAndExtractNodeAddr(r)
-	if err != nil {
+func parseRequestToGetBadPartitionTrend(r *http.Request) (hours int, err error) {
+	if err = r.ParseForm(); err != nil {
 		return
 	}
+	hours = defaultBadPartitionTrendHours
+	if value := r.FormValue(hoursKey); value != "" {
+		if hours, err = strconv.Atoi(value); err != nil || hours <= 0 || hours > maxBadPartitionTrendHours {
+			err = fmt.Errorf("%v should be in range (0,%v]", hoursKey, maxBadPartitionTrendHours)
+			return
+		}
+	}
+	return
+}
 
-	limit, err = parseUintParam(r, countKey)
-	if err != nil {
+func parseRequestToGetCapacityForecast(r *http.Request) (hours int, err error) {
+	if err = r.ParseForm(); err != nil {
 		return
 	}
-
+	hours = defaultCapacityForecastHours
+	if value := r.FormValue(hoursKey); value != "" {
+		if hours, err = strconv.Atoi(value); err != nil || hours <= 0 || hours > maxCapacityForecastHours {
+			err = fmt.Errorf("%v should be in range (0,%v]", hoursKey, maxCapacityForecastHours)
+			return
+		}
+	}
 	return
 }
 
-func parseAndExtractNodeAddr(r *http.Request) (nodeAddr string, err error) {
+func parseRequestToGetLaggingReplicas(r *http.Request) (count int, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	return extractNodeAddr(r)
+	count = defaultLaggingReplicaCount
+	if value := r.FormValue(countKey); value != "" {
+		if count, err = strconv.Atoi(value); err != nil || count <= 0 {
+			err = unmatchedKey(countKey)
+			return
+		}
+	}
+	return
 }
 
-func parseReqToDecoDisk(r *http.Request) (nodeAddr, diskPath string, limit int, err error) {
+func parseRequestToCreateDataPartition(r *http.Request) (count int, name string, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	nodeAddr, err = extractNodeAddr(r)
-	if err != nil {
+	if countStr := r.FormValue(countKey); countStr == "" {
+		err = keyNotFound(countKey)
+		return
+	} else if count, err = strconv.Atoi(countStr); err != nil || count == 0 {
+		err = unmatchedKey(countKey)
 		return
 	}
-	diskPath, err = extractDiskPath(r)
-	if err != nil {
+	if name, err = extractName(r); err != nil {
 		return
 	}
-
-	limit, err = parseUintParam(r, countKey)
 	return
 }
 
-func parseRequestToGetTaskResponse(r *http.Request) (tr *proto.AdminTask, err error) {
-	var body []byte
+func parseRequestToGetDataPartition(r *http.Request) (ID uint64, volName string, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if body, err = ioutil.ReadAll(r.Body); err != nil {
+	if ID, err = extractDataPartitionID(r); err != nil {
 		return
 	}
-	tr = &proto.AdminTask{}
-	decoder := json.NewDecoder(bytes.NewBuffer([]byte(body)))
-	decoder.UseNumber()
-	err = decoder.Decode(tr)
+	volName = r.FormValue(nameKey)
 	return
 }
 
-func parseVolName(r *http.Request) (name string, err error) {
+func parseRequestToLoadDataPartition(r *http.Request) (ID uint64, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if name, err = extractName(r); err != nil {
+	if ID, err = extractDataPartitionID(r); err != nil {
 		return
 	}
 	return
 }
 
-type getVolParameter struct {
-	name                string
-	authKey             string
-	skipOwnerValidation bool
-}
-
-func parseGetVolParameter(r *http.Request) (p *getVolParameter, err error) {
-	p = &getVolParameter{}
-	skipOwnerValidationVal := r.Header.Get(proto.SkipOwnerValidation)
-	if len(skipOwnerValidationVal) > 0 {
-		if p.skipOwnerValidation, err = strconv.ParseBool(skipOwnerValidationVal); err != nil {
-			return
-		}
-	}
-	if p.name = r.FormValue(nameKey); p.name == "" {
-		err = keyNotFound(nameKey)
+func parseRequestToVerifyDataPartition(r *http.Request) (ID uint64, timeout time.Duration, err error) {
+	if ID, err = parseRequestToLoadDataPartition(r); err != nil {
 		return
 	}
-	if !volNameRegexp.MatchString(p.name) {
-		err = errors.New("name can only be number and letters")
-		return
+	timeoutSec := int64(defaultVerifyDataPartitionTimeoutSec)
+	if value := r.FormValue(timeoutKey); value != "" {
+		if timeoutSec, err = strconv.ParseInt(value, 10, 64); err != nil {
+			err = unmatchedKey(timeoutKey)
+			return
+		}
 	}
-	if p.authKey = r.FormValue(volAuthKey); !p.skipOwnerValidation && len(p.authKey) == 0 {
-		err = keyNotFound(volAuthKey)
+	if timeoutSec <= 0 || timeoutSec > timeToWaitForResponse {
+		err = fmt.Errorf("%v must be between 1 and %v seconds", timeoutKey, timeToWaitForResponse)
 		return
 	}
+	timeout = time.Duration(timeoutSec) * time.Second
 	return
 }
 
-func parseVolNameAndAuthKey(r *http.Request) (name, authKey string, err error) {
+func parseRequestToAddMetaReplica(r *http.Request) (ID uint64, addr string, err error) {
+	return extractMetaPartitionIDAndAddr(r)
+}
+
+func parseRequestToRemoveMetaReplica(r *http.Request) (ID uint64, addr string, err error) {
+	return extractMetaPartitionIDAndAddr(r)
+}
+
+func extractMetaPartitionIDAndAddr(r *http.Request) (ID uint64, addr string, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if name, err = extractName(r); err != nil {
+	if ID, err = extractMetaPartitionID(r); err != nil {
 		return
 	}
-	if authKey, err = extractAuthKey(r); err != nil {
+	if addr, err = extractNodeAddr(r); err != nil {
 		return
 	}
 	return
-
 }
 
-func parseRequestToDeleteVol(r *http.Request) (name, authKey string, err error) {
-	return parseVolNameAndAuthKey(r)
+func parseRequestToAddDataReplica(r *http.Request) (ID uint64, addr string, err error) {
+	return extractDataPartitionIDAndAddr(r)
+}
 
+func parseRequestToRemoveDataReplica(r *http.Request) (ID uint64, addr string, err error) {
+	return extractDataPartitionIDAndAddr(r)
 }
 
-func parseRequestToUpdateVol(r *http.Request) (name, authKey, description string, err error) {
+func extractDataPartitionIDAndAddr(r *http.Request) (ID uint64, addr string, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if name, err = extractName(r); err != nil {
+	if ID, err = extractDataPartitionID(r); err != nil {
 		return
 	}
-	if authKey, err = extractAuthKey(r); err != nil {
+	if addr, err = extractNodeAddr(r); err != nil {
 		return
 	}
-	description = r.FormValue(descriptionKey)
 	return
 }
 
-func parseDefaultInfoToUpdateVol(r *http.Request, vol *Vol) (zoneName string, capacity uint64, replicaNum int,
-	dpSelectorName string, dpSelectorParm string, err error) {
-	if err = r.ParseForm(); err != nil {
+func extractDataPartitionID(r *http.Request) (ID uint64, err error) {
+	var value string
+	if value = r.FormValue(idKey); value == "" {
+		err = keyNotFound(idKey)
 		return
 	}
-	if zoneName = r.FormValue(zoneNameKey); zoneName == "" {
-		zoneName = vol.zoneName
-	}
-	if capacityStr := r.FormValue(volCapacityKey); capacityStr != "" {
-		var capacityInt int
-		if capacityInt, err = strconv.Atoi(capacityStr); err != nil {
-			err = unmatchedKey(volCapacityKey)
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func parseRequestToDecommissionDataPartition(r *http.Request) (ID uint64, nodeAddr string, err error) {
+	return extractDataPartitionIDAndAddr(r)
+}
+
+func parseRequestToBatchDecommissionDataPartition(r *http.Request) (req *proto.BatchDecommissionDataPartitions, err error) {
+	req = &proto.BatchDecommissionDataPartitions{}
+	if ids := r.FormValue(idsKey); ids != "" {
+		if req.Addr, err = extractNodeAddr(r); err != nil {
 			return
 		}
-		capacity = uint64(capacityInt)
-	} else {
-		capacity = vol.Capacity
-	}
-	if replicaNumStr := r.FormValue(replicaNumKey); replicaNumStr != "" {
-		if replicaNum, err = strconv.Atoi(replicaNumStr); err != nil {
-			err = unmatchedKey(replicaNumKey)
-			return
+		for _, id := range strings.Split(ids, commaSplit) {
+			var partitionID uint64
+			if partitionID, err = strconv.ParseUint(strings.TrimSpace(id), 10, 64); err != nil {
+				err = unmatchedKey(idsKey)
+				return
+			}
+			req.PartitionIDs = append(req.PartitionIDs, partitionID)
 		}
-	} else {
-		replicaNum = int(vol.dpReplicaNum)
+		return
 	}
-	dpSelectorName = r.FormValue(dpSelectorNameKey)
-	dpSelectorParm = r.FormValue(dpSelectorParmKey)
-	if (dpSelectorName == "") || (dpSelectorParm == "") {
-		if (dpSelectorName != "") || (dpSelectorParm != "") {
-			err = keyNotFound(dpSelectorNameKey + " or " + dpSelectorParmKey)
-			return
-		}
-		dpSelectorName = vol.dpSelectorName
-		dpSelectorParm = vol.dpSelectorParm
+	var body []byte
+	if body, err = ioutil.ReadAll(r.Body); err != nil {
+		return
+	}
+	if len(body) == 0 {
+		err = keyNotFound(idsKey)
+		return
+	}
+	if err = json.Unmarshal(body, req); err != nil {
+		return
+	}
+	if req.Addr == "" {
+		err = keyNotFound(addrKey)
+		return
+	}
+	if len(req.PartitionIDs) == 0 {
+		err = keyNotFound(idsKey)
+		return
 	}
 	return
 }
 
-func parseBoolFieldToUpdateVol(r *http.Request, vol *Vol) (followerRead, authenticate bool, err error) {
-	if followerReadStr := r.FormValue(followerReadKey); followerReadStr != "" {
-		if followerRead, err = strconv.ParseBool(followerReadStr); err != nil {
-			err = unmatchedKey(followerReadKey)
-			return
-		}
-	} else {
-		followerRead = vol.FollowerRead
-	}
-	if authenticateStr := r.FormValue(authenticateKey); authenticateStr != "" {
-		if authenticate, err = strconv.ParseBool(authenticateStr); err != nil {
-			err = unmatchedKey(authenticateKey)
-			return
-		}
-	} else {
-		authenticate = vol.authenticate
+func extractNodeAddr(r *http.Request) (nodeAddr string, err error) {
+	if nodeAddr = r.FormValue(addrKey); nodeAddr == "" {
+		err = keyNotFound(addrKey)
+		return
 	}
 	return
 }
 
-func parseRequestToSetVolCapacity(r *http.Request) (name, authKey string, capacity int, err error) {
-	if err = r.ParseForm(); err != nil {
+func extractNodeID(r *http.Request) (ID uint64, err error) {
+	var value string
+	if value = r.FormValue(idKey); value == "" {
+		err = keyNotFound(idKey)
 		return
 	}
-	if name, err = extractName(r); err != nil {
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func extractDiskPath(r *http.Request) (diskPath string, err error) {
+	if diskPath = r.FormValue(diskPathKey); diskPath == "" {
+		err = keyNotFound(diskPathKey)
 		return
 	}
-	if authKey, err = extractAuthKey(r); err != nil {
+	return
+}
+
+func parseRequestToLoadMetaPartition(r *http.Request) (partitionID uint64, err error) {
+	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if capacity, err = extractCapacity(r); err != nil {
+	if partitionID, err = extractMetaPartitionID(r); err != nil {
 		return
 	}
 	return
 }
 
-func parseRequestToCreateVol(r *http.Request) (name, owner, zoneName, description string,
-	mpCount, dpReplicaNum, size,
-	capacity int, followerRead,
-	authenticate, crossZone, defaultPriority bool,
-	err error) {
+func parseRequestToDecommissionMetaPartition(r *http.Request) (partitionID uint64, nodeAddr string, err error) {
+	return extractMetaPartitionIDAndAddr(r)
+}
+
+func parseRequestToSplitMetaPartition(r *http.Request) (partitionID, inode uint64, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if name, err = extractName(r); err != nil {
+	if partitionID, err = extractMetaPartitionID(r); err != nil {
 		return
 	}
-	if owner, err = extractOwner(r); err != nil {
+	var value string
+	if value = r.FormValue(inodeKey); value == "" {
+		err = keyNotFound(inodeKey)
 		return
 	}
-
-	if mpCountStr := r.FormValue(metaPartitionCountKey); mpCountStr != "" {
-		if mpCount, err = strconv.Atoi(mpCountStr); err != nil {
-			mpCount = defaultInitMetaPartitionCount
-		}
+	if inode, err = strconv.ParseUint(value, 10, 64); err != nil {
+		err = unmatchedKey(inodeKey)
+		return
 	}
+	return
+}
 
-	if replicaStr := r.FormValue(replicaNumKey); replicaStr == "" {
-		dpReplicaNum = defaultReplicaNum
-	} else if dpReplicaNum, err = strconv.Atoi(replicaStr); err != nil {
-		err = unmatchedKey(replicaNumKey)
+// The count is defaultAuditLogQueryCount by default, it can not exceed the capacity of the audit log.
+func parseRequestToGetAuditLog(r *http.Request, capacity int) (count int, action string, err error) {
+	if err = r.ParseForm(); err != nil {
 		return
 	}
-
-	if sizeStr := r.FormValue(dataPartitionSizeKey); sizeStr != "" {
-		if size, err = strconv.Atoi(sizeStr); err != nil {
-			err = unmatchedKey(dataPartitionSizeKey)
+	count = defaultAuditLogQueryCount
+	if value := r.FormValue(countKey); value != "" {
+		if count, err = strconv.Atoi(value); err != nil || count <= 0 {
+			err = unmatchedKey(countKey)
 			return
 		}
 	}
+	if count > capacity {
+		count = capacity
+	}
+	action = r.FormValue(actionKey)
+	return
+}
 
-	if capacity, err = extractCapacity(r); err != nil {
+func parseRequestToSetLogLevel(r *http.Request) (level log.Level, err error) {
+	if err = r.ParseForm(); err != nil {
 		return
 	}
-
-	if followerRead, err = extractFollowerRead(r); err != nil {
+	value := r.FormValue(levelKey)
+	if value == "" {
+		err = keyNotFound(levelKey)
 		return
 	}
+	return log.ParseLevel(value)
+}
 
-	if authenticate, err = extractAuthenticate(r); err != nil {
+func parseRequestToGetAuditByAddr(r *http.Request) (addr string, startTime, endTime int64, err error) {
+	if err = r.ParseForm(); err != nil {
 		return
 	}
-
-	if crossZone, err = extractCrossZone(r); err != nil {
+	if addr = r.FormValue(addrKey); addr == "" {
+		err = keyNotFound(addrKey)
 		return
 	}
-	if defaultPriority, err = extractDefaulPriority(r); err != nil {
+	if value := r.FormValue(startTimeKey); value != "" {
+		if startTime, err = strconv.ParseInt(value, 10, 64); err != nil {
+			err = unmatchedKey(startTimeKey)
+			return
+		}
+	}
+	endTime = time.Now().Unix()
+	if value := r.FormValue(endTimeKey); value != "" {
+		if endTime, err = strconv.ParseInt(value, 10, 64); err != nil {
+			err = unmatchedKey(endTimeKey)
+			return
+		}
+	}
+	if startTime > endTime {
+		err = fmt.Errorf("%v[%v] is later than %v[%v]", startTimeKey, startTime, endTimeKey, endTime)
 		return
 	}
-
-	zoneName = r.FormValue(zoneNameKey)
-	description = r.FormValue(descriptionKey)
 	return
 }
 
-func parseRequestToCreateDataPartition(r *http.Request) (count int, name string, err error) {
+func parseAndExtractZoneName(r *http.Request) (zoneName string, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if countStr := r.FormValue(countKey); countStr == "" {
-		err = keyNotFound(countKey)
-		return
-	} else if count, err = strconv.Atoi(countStr); err != nil || count == 0 {
-		err = unmatchedKey(countKey)
-		return
-	}
-	if name, err = extractName(r); err != nil {
+	if zoneName = r.FormValue(zoneNameKey); zoneName == "" {
+		err = keyNotFound(zoneNameKey)
 		return
 	}
 	return
 }
 
-func parseRequestToGetDataPartition(r *http.Request) (ID uint64, volName string, err error) {
+func parseAndExtractHeartbeatVerbosity(r *http.Request) (verbosity string, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if ID, err = extractDataPartitionID(r); err != nil {
+	if verbosity = r.FormValue(verbosityKey); verbosity == "" {
+		err = keyNotFound(verbosityKey)
+		return
+	}
+	if verbosity != proto.HeartbeatVerbosityMinimal && verbosity != proto.HeartbeatVerbosityVerbose {
+		err = fmt.Errorf("verbosity[%v] should be %v or %v", verbosity, proto.HeartbeatVerbosityMinimal, proto.HeartbeatVerbosityVerbose)
 		return
 	}
-	volName = r.FormValue(nameKey)
 	return
 }
 
-func parseRequestToLoadDataPartition(r *http.Request) (ID uint64, err error) {
+func parseRequestToSetAutoRebalancePolicy(r *http.Request) (policy *proto.AutoRebalancePolicy, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if ID, err = extractDataPartitionID(r); err != nil {
+	policy = &proto.AutoRebalancePolicy{
+		MinBalanceScore:    defaultAutoRebalanceMinBalanceScore,
+		MaxConcurrentMoves: defaultAutoRebalanceMaxMoves,
+	}
+	if policy.Enable, err = extractStatus(r); err != nil {
 		return
 	}
+	var value string
+	if value = r.FormValue(minBalanceScoreKey); value != "" {
+		if policy.MinBalanceScore, err = strconv.ParseFloat(value, 64); err != nil {
+			err = unmatchedKey(minBalanceScoreKey)
+			return
+		}
+	}
+	if value = r.FormValue(windowStartKey); value != "" {
+		if policy.WindowStartHour, err = strconv.Atoi(value); err != nil {
+			err = unmatchedKey(windowStartKey)
+			return
+		}
+	}
+	if value = r.FormValue(windowEndKey); value != "" {
+		if policy.WindowEndHour, err = strconv.Atoi(value); err != nil {
+			err = unmatchedKey(windowEndKey)
+			return
+		}
+	}
+	if value = r.FormValue(maxMovesKey); value != "" {
+		if policy.MaxConcurrentMoves, err = strconv.Atoi(value); err != nil {
+			err = unmatchedKey(maxMovesKey)
+			return
+		}
+	}
+	err = checkAutoRebalancePolicy(policy)
 	return
 }
 
-func parseRequestToAddMetaReplica(r *http.Request) (ID uint64, addr string, err error) {
-	return extractMetaPartitionIDAndAddr(r)
-}
-
-func parseRequestToRemoveMetaReplica(r *http.Request) (ID uint64, addr string, err error) {
-	return extractMetaPartitionIDAndAddr(r)
-}
-
-func extractMetaPartitionIDAndAddr(r *http.Request) (ID uint64, addr string, err error) {
+func parseRequestToSetAutoDecommission(r *http.Request) (policy *proto.AutoDecommissionPolicy, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if ID, err = extractMetaPartitionID(r); err != nil {
-		return
+	policy = &proto.AutoDecommissionPolicy{
+		MaxConcurrentNodes: defaultAutoDecommissionMaxNodes,
+		MaxInactiveRatio:   defaultAutoDecommissionInactiveRatio,
 	}
-	if addr, err = extractNodeAddr(r); err != nil {
+	if policy.Enable, err = extractStatus(r); err != nil {
 		return
 	}
+	var value string
+	if value = r.FormValue(timeoutKey); value != "" {
+		if policy.TimeoutSec, err = strconv.ParseInt(value, 10, 64); err != nil {
+			err = unmatchedKey(timeoutKey)
+			return
+		}
+	}
+	if value = r.FormValue(maxNodesKey); value != "" {
+		if policy.MaxConcurrentNodes, err = strconv.Atoi(value); err != nil {
+			err = unmatchedKey(maxNodesKey)
+			return
+		}
+	}
+	if value = r.FormValue(maxInactiveRatioKey); value != "" {
+		if policy.MaxInactiveRatio, err = strconv.ParseFloat(value, 64); err != nil {
+			err = unmatchedKey(maxInactiveRatioKey)
+			return
+		}
+	}
+	err = checkAutoDecommissionPolicy(policy)
 	return
 }
 
-func parseRequestToAddDataReplica(r *http.Request) (ID uint64, addr string, err error) {
-	return extractDataPartitionIDAndAddr(r)
-}
-
-func parseRequestToRemoveDataReplica(r *http.Request) (ID uint64, addr string, err error) {
-	return extractDataPartitionIDAndAddr(r)
-}
-
-func extractDataPartitionIDAndAddr(r *http.Request) (ID uint64, addr string, err error) {
-	if err = r.ParseForm(); err != nil {
+func parseRequestToBatchSetNodeTags(r *http.Request) (items []*proto.NodeTags, err error) {
+	var body []byte
+	if body, err = ioutil.ReadAll(r.Body); err != nil {
 		return
 	}
-	if ID, err = extractDataPartitionID(r); err != nil {
+	items = make([]*proto.NodeTags, 0)
+	if err = json.Unmarshal(body, &items); err != nil {
 		return
 	}
-	if addr, err = extractNodeAddr(r); err != nil {
+	if len(items) == 0 {
+		err = fmt.Errorf("no node to set tags")
 		return
 	}
+	for _, item := range items {
+		if item.Addr == "" {
+			err = keyNotFound(addrKey)
+			return
+		}
+	}
 	return
 }
 
-func extractDataPartitionID(r *http.Request) (ID uint64, err error) {
-	var value string
-	if value = r.FormValue(idKey); value == "" {
-		err = keyNotFound(idKey)
+func parseRequestToSetAutoAllocThreshold(r *http.Request, oldCount int, oldRatio float64) (count int, usedRatio float64, err error) {
+	if err = r.ParseForm(); err != nil {
 		return
 	}
-	return strconv.ParseUint(value, 10, 64)
-}
-
-func parseRequestToDecommissionDataPartition(r *http.Request) (ID uint64, nodeAddr string, err error) {
-	return extractDataPartitionIDAndAddr(r)
-}
-
-func extractNodeAddr(r *http.Request) (nodeAddr string, err error) {
-	if nodeAddr = r.FormValue(addrKey); nodeAddr == "" {
-		err = keyNotFound(addrKey)
+	countValue, ratioValue := r.FormValue(countKey), r.FormValue(ratio)
+	if countValue == "" && ratioValue == "" {
+		err = fmt.Errorf("parameter %v or %v is required", countKey, ratio)
 		return
 	}
+	count, usedRatio = oldCount, oldRatio
+	if countValue != "" {
+		if count, err = strconv.Atoi(countValue); err != nil {
+			err = unmatchedKey(countKey)
+			return
+		}
+		if count < 1 || count > maxNumberOfDataPartitionsForExpansion {
+			err = fmt.Errorf("parameter %v should be between 1 and %v, received %v", countKey, maxNumberOfDataPartitionsForExpansion, count)
+			return
+		}
+	}
+	if ratioValue != "" {
+		if usedRatio, err = strconv.ParseFloat(ratioValue, 64); err != nil {
+			err = unmatchedKey(ratio)
+			return
+		}
+		if usedRatio <= 0 || usedRatio > 1 {
+			err = fmt.Errorf("parameter %v should be in (0, 1], received %v", ratio, usedRatio)
+			return
+		}
+	}
 	return
 }
 
-func extractNodeID(r *http.Request) (ID uint64, err error) {
+func parseRequestToSetMaxVolumes(r *http.Request) (maxVolumes uint64, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
 	var value string
-	if value = r.FormValue(idKey); value == "" {
-		err = keyNotFound(idKey)
+	if value = r.FormValue(countKey); value == "" {
+		err = keyNotFound(countKey)
 		return
 	}
-	return strconv.ParseUint(value, 10, 64)
-}
-
-func extractDiskPath(r *http.Request) (diskPath string, err error) {
-	if diskPath = r.FormValue(diskPathKey); diskPath == "" {
-		err = keyNotFound(diskPathKey)
+	if maxVolumes, err = strconv.ParseUint(value, 10, 64); err != nil {
+		err = unmatchedKey(countKey)
 		return
 	}
 	return
 }
 
-func parseRequestToLoadMetaPartition(r *http.Request) (partitionID uint64, err error) {
+func parseAndExtractAllocStrategy(r *http.Request) (strategy string, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
 	}
-	if partitionID, err = extractMetaPartitionID(r); err != nil {
+	if strategy = r.FormValue(strategyKey); strategy == "" {
+		err = keyNotFound(strategyKey)
+		return
+	}
+	if !isValidAllocStrategy(strategy) {
+		err = fmt.Errorf("%v can only be one of %v", strategyKey, strings.Join(allocStrategies, ", "))
 		return
 	}
 	return
 }
 
-func parseRequestToDecommissionMetaPartition(r *http.Request) (partitionID uint64, nodeAddr string, err error) {
-	return extractMetaPartitionIDAndAddr(r)
-}
-
 func parseAndExtractStatus(r *http.Request) (status bool, err error) {
 
 	if err = r.ParseForm(); err != nil {
@@ -2408,6 +5014,30 @@ func extractStatus(r *http.Request) (status bool, err error) {
 	return
 }
 
+func extractForce(r *http.Request) (force bool, err error) {
+	var value string
+	if value = r.FormValue(forceKey); value == "" {
+		return
+	}
+	if force, err = strconv.ParseBool(value); err != nil {
+		err = unmatchedKey(forceKey)
+		return
+	}
+	return
+}
+
+func extractDryRun(r *http.Request) (dryRun bool, err error) {
+	var value string
+	if value = r.FormValue(dryRunKey); value == "" {
+		return
+	}
+	if dryRun, err = strconv.ParseBool(value); err != nil {
+		err = unmatchedKey(dryRunKey)
+		return
+	}
+	return
+}
+
 func extractFollowerRead(r *http.Request) (followerRead bool, err error) {
 	var value string
 	if value = r.FormValue(followerReadKey); value == "" {
@@ -2468,6 +5098,10 @@ func parseAndExtractThreshold(r *http.Request) (threshold float64, err error) {
 	if threshold, err = strconv.ParseFloat(value, 64); err != nil {
 		return
 	}
+	if threshold <= 0 || threshold > 1 {
+		err = fmt.Errorf("threshold[%v] should be in (0, 1]", threshold)
+		return
+	}
 	return
 }
 func parseSetNodeSetCapParams(r *http.Request) (count, id int, zoneName string, err error) {
@@ -2573,9 +5207,21 @@ func newErrHTTPReply(err error) *proto.HTTPReply {
 	if ok {
 		return &proto.HTTPReply{Code: code, Msg: err.Error()}
 	}
+	if ce, ok := err.(*codedError); ok {
+		return &proto.HTTPReply{Code: ce.code, Msg: err.Error()}
+	}
 	return &proto.HTTPReply{Code: proto.ErrCodeInternalError, Msg: err.Error()}
 }
 
+// The reply of the error of an operation tracked by a job, the data is the id of the job if it was started.
+func newErrJobHTTPReply(err error, jobID string) *proto.HTTPReply {
+	reply := newErrHTTPReply(err)
+	if jobID != "" {
+		reply.Data = jobID
+	}
+	return reply
+}
+
 func sendOkReply(w http.ResponseWriter, r *http.Request, httpReply *proto.HTTPReply) (err error) {
 	switch httpReply.Data.(type) {
 	case *DataPartition:
@@ -2597,7 +5243,7 @@ func sendOkReply(w http.ResponseWriter, r *http.Request, httpReply *proto.HTTPRe
 	}
 	reply, err := json.Marshal(httpReply)
 	if err != nil {
-		log.LogErrorf("fail to marshal http reply[%v]. URL[%v],remoteAddr[%v] err:[%v]", httpReply, r.URL, r.RemoteAddr, err)
+		log.LogErrorf("fail to marshal http reply[%v]. URL[%v],remoteAddr[%v],requestID[%v] err:[%v]", httpReply, r.URL, r.RemoteAddr, requestID(r), err)
 		http.Error(w, "fail to marshal http reply", http.StatusBadRequest)
 		return
 	}
@@ -2605,29 +5251,58 @@ func sendOkReply(w http.ResponseWriter, r *http.Request, httpReply *proto.HTTPRe
 	return
 }
 
+// Reply 201 to the request which created a resource, location is the URI to get the resource if it is not empty.
+func sendCreatedReply(w http.ResponseWriter, r *http.Request, httpReply *proto.HTTPReply, location string) {
+	reply, err := json.Marshal(httpReply)
+	if err != nil {
+		log.LogErrorf("fail to marshal http reply[%v]. URL[%v],remoteAddr[%v],requestID[%v] err:[%v]", httpReply, r.URL, r.RemoteAddr, requestID(r), err)
+		http.Error(w, "fail to marshal http reply", http.StatusBadRequest)
+		return
+	}
+	if location != "" {
+		w.Header().Set("Location", location)
+	}
+	sendWithStatus(w, r, http.StatusCreated, reply)
+}
+
 func send(w http.ResponseWriter, r *http.Request, reply []byte) {
+	sendWithStatus(w, r, http.StatusOK, reply)
+}
+
+func sendWithStatus(w http.ResponseWriter, r *http.Request, statusCode int, reply []byte) {
 	w.Header().Set("content-type", "application/json")
 	w.Header().Set("Content-Length", strconv.Itoa(len(reply)))
+	w.WriteHeader(statusCode)
 	if _, err := w.Write(reply); err != nil {
-		log.LogErrorf("fail to write http reply[%s] len[%d].URL[%v],remoteAddr[%v] err:[%v]", string(reply), len(reply), r.URL, r.RemoteAddr, err)
+		log.LogErrorf("fail to write http reply[%s] len[%d].URL[%v],remoteAddr[%v],requestID[%v] err:[%v]", string(reply), len(reply), r.URL, r.RemoteAddr, requestID(r), err)
 		return
 	}
-	log.LogInfof("URL[%v],remoteAddr[%v],response ok", r.URL, r.RemoteAddr)
+	log.LogInfof("URL[%v],remoteAddr[%v],requestID[%v],response ok", r.URL, r.RemoteAddr, requestID(r))
 	return
 }
 
+// The error reply is a json object with the code, the message and the operation named by the last element of the url path,
+// e.g. {"code":7,"msg":"vol not exists","op":"getVol","data":null}.
 func sendErrReply(w http.ResponseWriter, r *http.Request, httpReply *proto.HTTPReply) {
-	log.LogInfof("URL[%v],remoteAddr[%v],response err[%v]", r.URL, r.RemoteAddr, httpReply)
+	sendErrReplyWithStatus(w, r, http.StatusOK, httpReply)
+}
+
+func sendErrReplyWithStatus(w http.ResponseWriter, r *http.Request, statusCode int, httpReply *proto.HTTPReply) {
+	if httpReply.Op == "" {
+		httpReply.Op = path.Base(r.URL.Path)
+	}
+	log.LogInfof("URL[%v],remoteAddr[%v],requestID[%v],response err[%v]", r.URL, r.RemoteAddr, requestID(r), httpReply)
 	reply, err := json.Marshal(httpReply)
 	if err != nil {
-		log.LogErrorf("fail to marshal http reply[%v]. URL[%v],remoteAddr[%v] err:[%v]", httpReply, r.URL, r.RemoteAddr, err)
+		log.LogErrorf("fail to marshal http reply[%v]. URL[%v],remoteAddr[%v],requestID[%v] err:[%v]", httpReply, r.URL, r.RemoteAddr, requestID(r), err)
 		http.Error(w, "fail to marshal http reply", http.StatusBadRequest)
 		return
 	}
 	w.Header().Set("content-type", "application/json")
 	w.Header().Set("Content-Length", strconv.Itoa(len(reply)))
+	w.WriteHeader(statusCode)
 	if _, err = w.Write(reply); err != nil {
-		log.LogErrorf("fail to write http reply[%s] len[%d].URL[%v],remoteAddr[%v] err:[%v]", string(reply), len(reply), r.URL, r.RemoteAddr, err)
+		log.LogErrorf("fail to write http reply[%s] len[%d].URL[%v],remoteAddr[%v],requestID[%v] err:[%v]", string(reply), len(reply), r.URL, r.RemoteAddr, requestID(r), err)
 	}
 	return
 }
@@ -2658,12 +5333,14 @@ func (m *Server) getMetaPartitions(w http.ResponseWriter, r *http.Request) {
 // Obtain all the data partitions in a volume.
 func (m *Server) getDataPartitions(w http.ResponseWriter, r *http.Request) {
 	var (
-		body []byte
-		name string
-		vol  *Vol
-		err  error
+		body          []byte
+		name          string
+		groupByStatus bool
+		page          *dataPartitionsPageParam
+		vol           *Vol
+		err           error
 	)
-	if name, err = parseAndExtractName(r); err != nil {
+	if name, groupByStatus, page, err = parseRequestToGetDataPartitions(r); err != nil {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
@@ -2678,19 +5355,79 @@ func (m *Server) getDataPartitions(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		m.cluster.followerReadManager.rwMutex.RUnlock()
-		send(w, r, body)
+		if page != nil {
+			sendDataPartitionsPageFromView(w, r, body, page)
+			return
+		}
+		sendDataPartitionsView(w, r, body, groupByStatus)
 		return
 	}
 	if vol, err = m.cluster.getVol(name); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
 		return
 	}
+	if page != nil {
+		result := &proto.DataPartitionsPage{Start: page.start}
+		result.Total, result.DataPartitions = vol.dataPartitions.getDataPartitionsPage(page.start, page.count)
+		sendOkReply(w, r, newSuccessHTTPReply(result))
+		return
+	}
 
 	if body, err = vol.getDataPartitionsView(); err != nil {
 		sendErrReply(w, r, newErrHTTPReply(err))
 		return
 	}
-	send(w, r, body)
+	sendDataPartitionsView(w, r, body, groupByStatus)
+}
+
+// The followers only have the cached data partitions view, so the page is cut from it in the order of the partition id.
+func sendDataPartitionsPageFromView(w http.ResponseWriter, r *http.Request, body []byte, page *dataPartitionsPageParam) {
+	view := proto.NewDataPartitionsView()
+	if err := json.Unmarshal(body, &proto.HTTPReply{Data: view}); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrMarshalData))
+		return
+	}
+	sort.Slice(view.DataPartitions, func(i, j int) bool {
+		return view.DataPartitions[i].PartitionID < view.DataPartitions[j].PartitionID
+	})
+	result := &proto.DataPartitionsPage{
+		Total:          len(view.DataPartitions),
+		Start:          page.start,
+		DataPartitions: make([]*proto.DataPartitionResponse, 0),
+	}
+	if page.start < result.Total {
+		end := result.Total
+		if page.count < result.Total-page.start {
+			end = page.start + page.count
+		}
+		result.DataPartitions = view.DataPartitions[page.start:end]
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(result))
+}
+
+// Send the cached data partitions view as it is, or bucketed by the partition status if groupByStatus is set.
+func sendDataPartitionsView(w http.ResponseWriter, r *http.Request, body []byte, groupByStatus bool) {
+	if !groupByStatus {
+		send(w, r, body)
+		return
+	}
+	view := proto.NewDataPartitionsView()
+	if err := json.Unmarshal(body, &proto.HTTPReply{Data: view}); err != nil {
+		sendErrReply(w, r, newErrHTTPReply(proto.ErrMarshalData))
+		return
+	}
+	grouped := proto.NewDataPartitionsGroupedView()
+	for _, dp := range view.DataPartitions {
+		switch dp.Status {
+		case proto.ReadWrite:
+			grouped.ReadWrite = append(grouped.ReadWrite, dp)
+		case proto.ReadOnly:
+			grouped.ReadOnly = append(grouped.ReadOnly, dp)
+		default:
+			grouped.Unavailable = append(grouped.Unavailable, dp)
+		}
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(grouped))
 }
 
 func (m *Server) getVol(w http.ResponseWriter, r *http.Request) {
@@ -2780,14 +5517,15 @@ func getMetaPartitionView(mp *MetaPartition) (mpView *proto.MetaPartitionView) {
 	for _, host := range mp.Hosts {
 		mpView.Members = append(mpView.Members, host)
 	}
+	// the counts come from the reports of the replicas, they are shown even if the leader is unknown
+	mpView.MaxInodeID = mp.MaxInodeID
+	mpView.InodeCount = mp.InodeCount
+	mpView.DentryCount = mp.DentryCount
 	mr, err := mp.getMetaReplicaLeader()
 	if err != nil {
 		return
 	}
 	mpView.LeaderAddr = mr.Addr
-	mpView.MaxInodeID = mp.MaxInodeID
-	mpView.InodeCount = mp.InodeCount
-	mpView.DentryCount = mp.DentryCount
 	mpView.IsRecover = mp.IsRecover
 	return
 }
@@ -2855,6 +5593,7 @@ func (m *Server) listVols(w http.ResponseWriter, r *http.Request) {
 	var (
 		err      error
 		keywords string
+		owner    string
 		vol      *Vol
 		volsInfo []*proto.VolInfo
 	)
@@ -2862,6 +5601,8 @@ func (m *Server) listVols(w http.ResponseWriter, r *http.Request) {
 		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
 		return
 	}
+	owner = r.FormValue(volOwnerKey)
+	tags := parseTagFilter(r)
 	volsInfo = make([]*proto.VolInfo, 0)
 	for _, name := range m.cluster.allVolNames() {
 		if strings.Contains(name, keywords) {
@@ -2869,14 +5610,56 @@ func (m *Server) listVols(w http.ResponseWriter, r *http.Request) {
 				sendErrReply(w, r, newErrHTTPReply(proto.ErrVolNotExists))
 				return
 			}
+			if (owner != "" && vol.Owner != owner) || !vol.matchTags(tags) {
+				continue
+			}
 			stat := volStat(vol)
 			volInfo := proto.NewVolInfo(vol.Name, vol.Owner, vol.createTime, vol.status(), stat.TotalSize, stat.UsedSize)
+			volInfo.Tags = vol.tags
 			volsInfo = append(volsInfo, volInfo)
 		}
 	}
 	sendOkReply(w, r, newSuccessHTTPReply(volsInfo))
 }
 
+// List the summary of all the volumes, whose names contain the optional keyword and which are owned by the optional owner.
+func (m *Server) getAllVols(w http.ResponseWriter, r *http.Request) {
+	var (
+		keyword string
+		owner   string
+		vol     *Vol
+		err     error
+	)
+	if err = r.ParseForm(); err != nil {
+		sendErrReply(w, r, &proto.HTTPReply{Code: proto.ErrCodeParamError, Msg: err.Error()})
+		return
+	}
+	keyword = r.FormValue(keywordKey)
+	owner = r.FormValue(volOwnerKey)
+	tags := parseTagFilter(r)
+	vols := make([]*proto.VolSummary, 0)
+	for _, name := range m.cluster.allVolNames() {
+		if !strings.Contains(name, keyword) {
+			continue
+		}
+		if vol, err = m.cluster.getVol(name); err != nil {
+			continue
+		}
+		if (owner != "" && vol.Owner != owner) || !vol.matchTags(tags) {
+			continue
+		}
+		stat := volStat(vol)
+		vols = append(vols, &proto.VolSummary{
+			Name:     vol.Name,
+			Status:   vol.status(),
+			Capacity: vol.Capacity,
+			UsedSize: stat.UsedSize,
+			OwnerID:  vol.Owner,
+		})
+	}
+	sendOkReply(w, r, newSuccessHTTPReply(vols))
+}
+
 func parseAndExtractPartitionInfo(r *http.Request) (partitionID uint64, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
@@ -2916,6 +5699,51 @@ func extractAuthKey(r *http.Request) (authKey string, err error) {
 	return
 }
 
+// dataPartitionsPageParam is the page asked by the start and count parameters of getDataPartitions.
+type dataPartitionsPageParam struct {
+	start int
+	count int
+}
+
+// The page is nil if neither start nor count is given.
+func parseRequestToGetDataPartitions(r *http.Request) (name string, groupByStatus bool, page *dataPartitionsPageParam, err error) {
+	if err = r.ParseForm(); err != nil {
+		return
+	}
+	if name, err = extractName(r); err != nil {
+		return
+	}
+	var value string
+	if value = r.FormValue(groupByStatusKey); value != "" {
+		if groupByStatus, err = strconv.ParseBool(value); err != nil {
+			err = unmatchedKey(groupByStatusKey)
+			return
+		}
+	}
+	startValue, countValue := r.FormValue(startKey), r.FormValue(countKey)
+	if startValue == "" && countValue == "" {
+		return
+	}
+	if groupByStatus {
+		err = fmt.Errorf("%v can not be used with %v or %v", groupByStatusKey, startKey, countKey)
+		return
+	}
+	page = &dataPartitionsPageParam{count: math.MaxInt32}
+	if startValue != "" {
+		if page.start, err = strconv.Atoi(startValue); err != nil || page.start < 0 {
+			err = unmatchedKey(startKey)
+			return
+		}
+	}
+	if countValue != "" {
+		if page.count, err = strconv.Atoi(countValue); err != nil || page.count <= 0 {
+			err = unmatchedKey(countKey)
+			return
+		}
+	}
+	return
+}
+
 func parseAndExtractName(r *http.Request) (name string, err error) {
 	if err = r.ParseForm(); err != nil {
 		return
@@ -2928,25 +5756,36 @@ func extractName(r *http.Request) (name string, err error) {
 		err = keyNotFound(nameKey)
 		return
 	}
-	if !volNameRegexp.MatchString(name) {
-		return "", errors.New("name can only be number and letters")
+	if err = checkVolName(name); err != nil {
+		return "", err
 	}
 
 	return
 }
 
+func checkVolName(name string) (err error) {
+	return volNameRule.check(name)
+}
+
 func extractOwner(r *http.Request) (owner string, err error) {
 	if owner = r.FormValue(volOwnerKey); owner == "" {
 		err = keyNotFound(volOwnerKey)
 		return
 	}
-	if !ownerRegexp.MatchString(owner) {
-		return "", errors.New("owner can only be number and letters")
+	if err = checkVolOwner(owner); err != nil {
+		return "", err
 	}
 
 	return
 }
 
+func checkVolOwner(owner string) (err error) {
+	if !ownerRegexp.MatchString(owner) {
+		return errors.New("owner can only be number and letters")
+	}
+	return
+}
+
 func parseAndCheckTicket(r *http.Request, key []byte, volName string) (jobj proto.APIAccessReq, ticket cryptoutil.Ticket, ts int64, err error) {
 	var (
 		plaintext []byte
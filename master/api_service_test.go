@@ -15,21 +15,30 @@
 package master
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"net/http/httptest"
 	_ "net/http/pprof"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/cubefs/cubefs/master/mocktest"
 	"github.com/cubefs/cubefs/proto"
+	masterSDK "github.com/cubefs/cubefs/sdk/master"
+	"github.com/cubefs/cubefs/util"
 	"github.com/cubefs/cubefs/util/config"
 	"github.com/cubefs/cubefs/util/log"
 )
@@ -102,7 +111,7 @@ func createDefaultMasterServerForTest() *Server {
 	testServer.cluster.checkMetaNodeHeartbeat()
 	time.Sleep(5 * time.Second)
 	testServer.cluster.scheduleToUpdateStatInfo()
-	vol, err := testServer.cluster.createVol(commonVolName, "cfs", testZone2, "", 3, 3, 3, 100, false, false, false, false)
+	vol, err := testServer.cluster.createVol(commonVolName, "cfs", testZone2, "", 3, 3, 3, 100, false, false, false, false, nil, 0, 0)
 	if err != nil {
 		panic(err)
 	}
@@ -199,6 +208,23 @@ func TestSetMetaNodeThreshold(t *testing.T) {
 		t.Errorf("set metanode threshold to %v failed", threshold)
 		return
 	}
+	for _, invalid := range []float64{0, -1, 5.0} {
+		reqURL = fmt.Sprintf("%v%v?threshold=%v", hostAddr, proto.AdminSetMetaNodeThreshold, invalid)
+		resp, err := http.Get(reqURL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil || reply.Code != proto.ErrCodeParamError {
+			t.Errorf("expect threshold %v to be rejected, reply[%v] err[%v]", invalid, reply, err)
+		}
+	}
+	if server.cluster.cfg.MetaNodeThreshold != float32(threshold) {
+		t.Errorf("expect metanode threshold %v, but got %v", threshold, server.cluster.cfg.MetaNodeThreshold)
+	}
 }
 
 func TestSetDisableAutoAlloc(t *testing.T) {
@@ -210,76 +236,2805 @@ func TestSetDisableAutoAlloc(t *testing.T) {
 		t.Errorf("set disableAutoAlloc to %v failed", enable)
 		return
 	}
-	server.cluster.DisableAutoAllocate = false
+	defer func() { server.cluster.DisableAutoAllocate = false }()
+	reply := process(fmt.Sprintf("%v%v", hostAddr, proto.AdminGetCluster), t)
+	if reply == nil {
+		return
+	}
+	if disabled := reply.Data.(map[string]interface{})["DisableAutoAlloc"]; disabled != enable {
+		t.Errorf("expect DisableAutoAlloc %v in the cluster view, but got %v", enable, disabled)
+	}
+}
+
+func TestSetAutoAllocThreshold(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	defer func() { server.cluster.MinRWDataPartitions, server.cluster.AutoAllocUsedRatio = 0, 0 }()
+	if err := mc.AdminAPI().SetAutoAllocThreshold(20, 0.8); err != nil {
+		t.Error(err)
+		return
+	}
+	// the ratio absent keeps its value
+	if err := mc.AdminAPI().SetAutoAllocThreshold(30, 0); err != nil {
+		t.Error(err)
+		return
+	}
+	cv, err := mc.AdminAPI().GetCluster()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cv.MinRWDataPartitions != 30 || cv.AutoAllocUsedRatio != 0.8 {
+		t.Errorf("expect the thresholds 30 and 0.8 in the cluster view, but got %v and %v", cv.MinRWDataPartitions, cv.AutoAllocUsedRatio)
+	}
+	for _, query := range []string{"", "count=0", "count=101", "ratio=0", "ratio=1.5", "ratio=x"} {
+		resp, err := http.Get(fmt.Sprintf("%v%v?%v", hostAddr, proto.AdminSetAutoAllocThreshold, query))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil || reply.Code != proto.ErrCodeParamError {
+			t.Errorf("expect the thresholds [%v] rejected, but got %v, err %v", query, reply, err)
+		}
+	}
+	if count := commonVol.calculateExpansionNum(30); count != 30 {
+		t.Errorf("expect 30 data partitions created for vol[%v] of capacity %v, but got %v", commonVol.Name, commonVol.Capacity, count)
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	cv, err := mc.AdminAPI().GetConfig()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cv.MetaNodeThreshold != server.config.MetaNodeThreshold || cv.MaxReplicaNum != server.config.maxReplicaNum {
+		t.Errorf("expect threshold %v and max replica num %v, but got %v and %v", server.config.MetaNodeThreshold,
+			server.config.maxReplicaNum, cv.MetaNodeThreshold, cv.MaxReplicaNum)
+	}
+	if cv.Cluster == nil || cv.Cluster.DisableAutoAlloc != server.cluster.DisableAutoAllocate {
+		t.Errorf("expect the cluster settings in the config, but got %v", cv.Cluster)
+	}
+	if cv.AdminToken != "" {
+		t.Errorf("expect no admin token, but got %v", cv.AdminToken)
+	}
+	server.config.adminToken = "secret"
+	defer func() { server.config.adminToken = "" }()
+	if cv = server.buildConfigView(); cv.AdminToken != maskedSecret {
+		t.Errorf("expect the admin token to be masked, but got %v", cv.AdminToken)
+	}
+}
+
+func TestSetMaintenance(t *testing.T) {
+	process(fmt.Sprintf("%v%v?enable=true", hostAddr, proto.AdminSetMaintenance), t)
+	defer func() { server.cluster.MaintenanceMode = false }()
+	if !server.cluster.MaintenanceMode {
+		t.Errorf("set maintenance mode to true failed")
+		return
+	}
+	reply := process(fmt.Sprintf("%v%v", hostAddr, proto.AdminGetCluster), t)
+	if reply == nil {
+		return
+	}
+	if mode := reply.Data.(map[string]interface{})["MaintenanceMode"]; mode != true {
+		t.Errorf("expect MaintenanceMode true in the cluster view, but got %v", mode)
+	}
+	name := "maintenanceVol"
+	resp, err := http.Get(fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfs&zoneName=%v", hostAddr, proto.AdminCreateVol, name, testZone2))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	httpReply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(httpReply)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable || httpReply.Code != proto.ErrCodeClusterInMaintenance {
+		t.Errorf("expect createVol to be rejected with 503, status[%v] reply[%v] err[%v]", resp.StatusCode, httpReply, err)
+	}
+	if _, err = server.cluster.getVol(name); err == nil {
+		t.Errorf("expect vol %v not to be created in maintenance", name)
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err = mc.AdminAPI().SetAutoDecommission(false, 0); err != proto.ErrClusterInMaintenance {
+		t.Errorf("expect err %v, but got %v", proto.ErrClusterInMaintenance, err)
+	}
+	process(fmt.Sprintf("%v%v?enable=false", hostAddr, proto.AdminSetMaintenance), t)
+	if server.cluster.MaintenanceMode {
+		t.Errorf("set maintenance mode to false failed")
+	}
+}
+
+func TestServiceUnavailableNotInMaintenance(t *testing.T) {
+	unready := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "raft is not ready", http.StatusServiceUnavailable)
+	}))
+	defer unready.Close()
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(unready.URL, "http://")}, false)
+	if _, err := mc.AdminAPI().GetCluster(); err != masterSDK.ErrNoValidMaster {
+		t.Errorf("expect err %v, but got %v", masterSDK.ErrNoValidMaster, err)
+	}
+}
+
+func TestSetDefaultZone(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?zoneName=%v", hostAddr, proto.AdminSetDefaultZone, testZone2)
+	fmt.Println(reqURL)
+	process(reqURL, t)
+	defer func() { server.cluster.DefaultZone = "" }()
+	reqURL = fmt.Sprintf("%v%v", hostAddr, proto.AdminGetClusterConfig)
+	reply := process(reqURL, t)
+	if reply == nil {
+		return
+	}
+	if zone := reply.Data.(map[string]interface{})["DefaultZone"]; zone != testZone2 {
+		t.Errorf("expect default zone %v, but got %v", testZone2, zone)
+	}
+}
+
+func TestSetMaxVolumes(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	defer mc.AdminAPI().SetMaxVolumes(0)
+	count := uint64(server.cluster.volCount())
+	if err := mc.AdminAPI().SetMaxVolumes(count); err != nil {
+		t.Error(err)
+		return
+	}
+	cv, err := mc.AdminAPI().GetCluster()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cv.MaxVolumes != count {
+		t.Errorf("expect MaxVolumes %v, but got %v", count, cv.MaxVolumes)
+	}
+	volName := "maxVolumesVol"
+	reqURL := fmt.Sprintf("%v%v?name=%v&replicas=3&type=extent&capacity=100&owner=cfs&mpCount=2&zoneName=%v",
+		hostAddr, proto.AdminCreateVol, volName, testZone2)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code == proto.ErrCodeSuccess {
+		t.Errorf("expect the vol beyond the limit %v rejected, reply[%v] err[%v]", count, reply, err)
+	}
+	if _, err = server.cluster.getVol(volName); err == nil {
+		t.Errorf("expect vol %v not created", volName)
+	}
+	// one more vol is allowed after the limit is raised
+	if err = mc.AdminAPI().SetMaxVolumes(count + 1); err != nil {
+		t.Error(err)
+		return
+	}
+	createVol(volName, t)
+	defer markDeleteVol(volName, t)
+	if _, err = server.cluster.getVol(volName); err != nil {
+		t.Errorf("expect vol %v created under the raised limit, err[%v]", volName, err)
+	}
+	reqURL = fmt.Sprintf("%v%v?count=many", hostAddr, proto.AdminSetMaxVolumes)
+	if resp, err = http.Get(reqURL); err != nil {
+		t.Error(err)
+		return
+	}
+	reply = &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the invalid count rejected, reply[%v] err[%v]", reply, err)
+	}
 }
 
 func TestGetCluster(t *testing.T) {
 	reqURL := fmt.Sprintf("%v%v", hostAddr, proto.AdminGetCluster)
 	fmt.Println(reqURL)
-	process(reqURL, t)
+	reply := process(reqURL, t)
+	for _, node := range reply.Data.(map[string]interface{})["DataNodes"].([]interface{}) {
+		if total := node.(map[string]interface{})["Total"]; total == nil {
+			t.Errorf("expect the total space of data node %v", node)
+		}
+		if reportTime, ok := node.(map[string]interface{})["ReportTime"].(string); !ok {
+			t.Errorf("expect the report time of data node %v", node)
+		} else if _, err := time.Parse(time.RFC3339, reportTime); err != nil {
+			t.Errorf("parse the report time of data node %v, err[%v]", node, err)
+		}
+	}
+}
+
+func TestGetClusterVersion(t *testing.T) {
+	oldVersion := proto.Version
+	proto.Version = "test-version"
+	defer func() { proto.Version = oldVersion }()
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	cv, err := mc.AdminAPI().GetCluster()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cv.Version != proto.Version {
+		t.Errorf("expect version %v, but got %v", proto.Version, cv.Version)
+	}
+	startTime, err := time.Parse(time.RFC3339, cv.StartTime)
+	if err != nil || startTime.Unix() != server.startTime.Unix() {
+		t.Errorf("expect start time %v, but got %v, err[%v]", server.startTime, cv.StartTime, err)
+	}
+	if uptime := int64(time.Since(server.startTime).Seconds()); cv.UptimeSeconds <= 0 || cv.UptimeSeconds > uptime {
+		t.Errorf("expect uptime up to %v seconds, but got %v", uptime, cv.UptimeSeconds)
+	}
+}
+
+func TestGetClusterPartitionSummary(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	cv, err := mc.AdminAPI().GetCluster()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cv.PartitionSummary == nil {
+		t.Errorf("expect the partition summary in the cluster view")
+		return
+	}
+	var dps, mps int
+	for _, vol := range server.cluster.copyVols() {
+		dps += len(vol.dataPartitions.partitions)
+		mps += len(vol.cloneMetaPartitionMap())
+	}
+	data, meta := cv.PartitionSummary.DataPartitions, cv.PartitionSummary.MetaPartitions
+	if data.ReadWrite+data.ReadOnly+data.Unavailable != dps || meta.ReadWrite+meta.ReadOnly+meta.Unavailable != mps {
+		t.Errorf("expect %v data partitions and %v meta partitions counted, but got %v", dps, mps, *cv.PartitionSummary)
+	}
+}
+
+func TestAuditLogRecent(t *testing.T) {
+	auditLog := newAuditLog(3)
+	for i := 0; i < 5; i++ {
+		auditLog.append(&proto.AuditEvent{Time: int64(i), Action: fmt.Sprintf("action%v", i%2)})
+	}
+	events := auditLog.recent(2, func(event *proto.AuditEvent) bool { return true })
+	if len(events) != 2 || events[0].Time != 3 || events[1].Time != 4 {
+		t.Errorf("expect the events at 3 and 4, but got %v", events)
+	}
+	events = auditLog.recent(10, func(event *proto.AuditEvent) bool { return event.Action == "action0" })
+	if len(events) != 2 || events[0].Time != 2 || events[1].Time != 4 {
+		t.Errorf("expect the events of action0 at 2 and 4, but got %v", events)
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	action := "testGetAuditLog"
+	for i := 0; i < 3; i++ {
+		server.cluster.addAuditEvent(action, "127.0.0.1:10000", fmt.Sprintf("target%v", i), nil)
+	}
+	server.cluster.addAuditEvent("otherAction", "127.0.0.1:10000", "target", nil)
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	events, err := mc.AdminAPI().GetAuditLog(2, action)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(events) != 2 || events[0].Target != "target1" || events[1].Target != "target2" {
+		t.Errorf("expect the last 2 events of %v, but got %v", action, events)
+	}
+	reqURL := fmt.Sprintf("%v%v?count=-1", hostAddr, proto.AdminGetAuditLog)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the negative count rejected, reply[%v] err[%v]", reply, err)
+	}
+}
+
+func TestGetAuditByAddr(t *testing.T) {
+	callers := []string{"10.9.9.9:1000", "10.9.9.9:2000", "10.9.9.90:1000", "10.9.9.9:1000"}
+	for i, caller := range callers {
+		server.cluster.auditLog.append(&proto.AuditEvent{
+			Time:       int64(i+1) * 100,
+			Action:     "testGetAuditByAddr",
+			CallerAddr: caller,
+			Target:     fmt.Sprintf("target%v", i+1),
+		})
+	}
+	targetsOf := func(events []*proto.AuditEvent) (targets []string) {
+		for _, event := range events {
+			targets = append(targets, event.Target)
+		}
+		return
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	// the host matches the callers on any port, but not the host it is a prefix of
+	events, err := mc.AdminAPI().GetAuditByAddr("10.9.9.9", 150, 400)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if targets := targetsOf(events); len(targets) != 2 || targets[0] != "target2" || targets[1] != "target4" {
+		t.Errorf("expect target2 and target4 of the host within the time range, but got %v", targets)
+	}
+	events, err = mc.AdminAPI().GetAuditByAddr("10.9.9.9:1000", 0, 400)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if targets := targetsOf(events); len(targets) != 2 || targets[0] != "target1" || targets[1] != "target4" {
+		t.Errorf("expect target1 and target4 of the exact address, but got %v", targets)
+	}
+	// the end time is now if it is not given
+	reqURL := fmt.Sprintf("%v%v?addr=10.9.9.9&startTime=350", hostAddr, proto.AdminGetAuditByAddr)
+	reply := &proto.HTTPReply{Data: &events}
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeSuccess {
+		t.Errorf("expect the events since 350, reply[%v] err[%v]", reply, err)
+		return
+	}
+	if targets := targetsOf(events); len(targets) != 1 || targets[0] != "target4" {
+		t.Errorf("expect target4 since 350, but got %v", targets)
+	}
+	reqURL = fmt.Sprintf("%v%v?addr=10.9.9.9&startTime=400&endTime=300", hostAddr, proto.AdminGetAuditByAddr)
+	if resp, err = http.Get(reqURL); err != nil {
+		t.Error(err)
+		return
+	}
+	reply = &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the start time later than the end time rejected, reply[%v] err[%v]", reply, err)
+	}
+}
+
+func TestGetRaftStatus(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	status, err := mc.AdminAPI().GetRaftStatus()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if status.LeaderID == 0 || status.LeaderID != status.NodeID || status.Applied == 0 {
+		t.Errorf("expect the status of the leader, but got %v", *status)
+	}
+	leaders := 0
+	for _, peer := range status.Peers {
+		if peer.IsLeader {
+			leaders++
+			if peer.ID != status.LeaderID || peer.Addr != server.leaderInfo.addr {
+				t.Errorf("expect the leader %v at %v, but got %v", status.LeaderID, server.leaderInfo.addr, *peer)
+			}
+		}
+	}
+	if leaders != 1 {
+		t.Errorf("expect one leader among the peers, but got %v", leaders)
+	}
+}
+
+func TestTransferRaftLeader(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	status, err := mc.AdminAPI().GetRaftStatus()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, id := range []uint64{status.LeaderID, status.LeaderID + 100} {
+		if err = mc.AdminAPI().TransferRaftLeader(id); err == nil {
+			t.Errorf("expect the transfer to master[%v] rejected", id)
+		}
+	}
+	reply := process(fmt.Sprintf("%v%v", hostAddr, proto.TryToRaftLeader), t)
+	if reply != nil && !strings.Contains(reply.Data.(string), "leader already") {
+		t.Errorf("expect the leader not to campaign again, but got %v", reply.Data)
+	}
+}
+
+func TestStepDownRaftLeader(t *testing.T) {
+	// the test cluster has a single master, there is no peer to take over the leadership
+	resp, err := http.Get(fmt.Sprintf("%v%v", hostAddr, proto.StepDownRaftLeader))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	reply := &proto.HTTPReply{}
+	if err = json.NewDecoder(resp.Body).Decode(reply); err != nil {
+		t.Error(err)
+		return
+	}
+	if reply.Code == proto.ErrCodeSuccess || !strings.Contains(reply.Msg, "no active peer") {
+		t.Errorf("expect the step-down rejected without a peer, but got %v", reply)
+	}
+	if !server.partition.IsRaftLeader() {
+		t.Errorf("expect master[%v] still the leader", server.id)
+	}
+}
+
+func TestSnapshotRaftLog(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	view, err := mc.AdminAPI().SnapshotRaftLog()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if view.NodeID != server.id || view.Index == 0 {
+		t.Errorf("expect the log of master[%v] compacted, but got %v", server.id, *view)
+	}
+	createVol("snapshotRaftLogVol", t)
+	markDeleteVol("snapshotRaftLogVol", t)
+	if status, err := mc.AdminAPI().GetRaftStatus(); err != nil || status.Applied <= view.Index {
+		t.Errorf("expect the raft log applied after the compaction, but got %v, err %v", status, err)
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	oldLevel := log.GetLevel()
+	defer log.SetLevel(oldLevel)
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err := mc.AdminAPI().SetLogLevel("warn"); err != nil {
+		t.Error(err)
+		return
+	}
+	level, err := mc.AdminAPI().GetLogLevel()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if level != "warn" || log.GetLevel() != log.WarnLevel {
+		t.Errorf("expect log level warn, but got %v", level)
+	}
+	reqURL := fmt.Sprintf("%v%v?level=verbose", hostAddr, proto.AdminSetLogLevel)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError || log.GetLevel() != log.WarnLevel {
+		t.Errorf("expect the unknown level rejected, reply[%v] err[%v]", reply, err)
+	}
+}
+
+func TestGetCapacityForecast(t *testing.T) {
+	server.cluster.updateStatInfo()
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	forecast, err := mc.AdminAPI().GetCapacityForecast(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if forecast.TotalSize == 0 || forecast.Samples == 0 || forecast.UsedSize+forecast.AvailSize != forecast.TotalSize {
+		t.Errorf("expect the space of the data nodes sampled, but got %v", *forecast)
+	}
+	if _, err = mc.AdminAPI().GetCapacityForecast(maxCapacityForecastHours + 1); err == nil {
+		t.Errorf("expect hours above %v rejected", maxCapacityForecastHours)
+	}
+}
+
+func TestBadPartitionTrendExpire(t *testing.T) {
+	trend := newBadPartitionTrend()
+	now := time.Now().Unix()
+	trend.add(&proto.BadPartitionSample{Time: now - maxBadPartitionTrendHours*3600 - 60, Count: 1})
+	trend.add(&proto.BadPartitionSample{Time: now - 2*3600, Count: 2})
+	trend.add(&proto.BadPartitionSample{Time: now, Count: 3})
+	if len(trend.samples) != 2 || trend.samples[0].Count != 2 {
+		t.Errorf("expect the sample older than %v hours dropped, but got %v", maxBadPartitionTrendHours, trend.samples)
+	}
+	if samples := trend.since(now - 3600); len(samples) != 1 || samples[0].Count != 3 {
+		t.Errorf("expect the sample of the last hour, but got %v", samples)
+	}
+}
+
+func TestGetBadPartitionTrend(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	lastCount := func() int {
+		samples, err := mc.AdminAPI().GetBadPartitionTrend(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(samples) == 0 {
+			t.Fatal("expect the bad partitions sampled")
+		}
+		return samples[len(samples)-1].Count
+	}
+	server.cluster.updateBadPartitionTrend()
+	count := lastCount()
+	key := "127.0.0.1:9999:/cfs/disk"
+	// the ids of no data partition, so that the recovery check only drops them
+	server.cluster.BadDataPartitionIds.Store(key, []uint64{1 << 40, 1<<40 + 1, 1<<40 + 2})
+	defer server.cluster.BadDataPartitionIds.Delete(key)
+	server.cluster.updateBadPartitionTrend()
+	if newCount := lastCount(); newCount != count+3 {
+		t.Errorf("expect %v bad partitions sampled, but got %v", count+3, newCount)
+	}
+	for _, hours := range []int{-1, maxBadPartitionTrendHours + 1} {
+		if _, err := mc.AdminAPI().GetBadPartitionTrend(hours); err == nil {
+			t.Errorf("expect hours %v rejected", hours)
+		}
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	request := func(id string) string {
+		req, err := http.NewRequest(http.MethodGet, hostAddr+proto.AdminGetCluster, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != "" {
+			req.Header.Set(proto.HeadRequestID, id)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.Header.Get(proto.HeadRequestID)
+	}
+	if id := request("provision-42"); id != "provision-42" {
+		t.Errorf("expect the request id provision-42 echoed, but got %v", id)
+	}
+	generated := request("")
+	if generated == "" {
+		t.Errorf("expect a request id generated")
+	}
+	if id := request(""); id == generated {
+		t.Errorf("expect a new request id, but got %v again", id)
+	}
+	for _, invalid := range []string{"bad id", strings.Repeat("a", maxRequestIDLen+1)} {
+		if id := request(invalid); id == invalid || id == "" {
+			t.Errorf("expect the request id %q replaced, but got %q", invalid, id)
+		}
+	}
+}
+
+func TestProxyRequestID(t *testing.T) {
+	leader := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(proto.HeadRequestID, r.Header.Get(proto.HeadRequestID))
+	}))
+	defer leader.Close()
+	follower := &Server{leaderInfo: &LeaderInfo{addr: strings.TrimPrefix(leader.URL, "http://")}}
+	follower.reverseProxy = follower.newReverseProxy()
+	r := httptest.NewRequest(http.MethodGet, proto.AdminGetCluster, nil)
+	r.Header.Set(proto.HeadRequestID, "provision-42")
+	w := httptest.NewRecorder()
+	w.Header().Set(proto.HeadRequestID, "provision-42")
+	follower.proxy(w, r)
+	if ids := w.Header()[http.CanonicalHeaderKey(proto.HeadRequestID)]; len(ids) != 1 || ids[0] != "provision-42" {
+		t.Errorf("expect the request id provision-42 once in the proxied reply, but got %v", ids)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	origin := "http://dashboard.example.com"
+	server.config.corsAllowedOrigins = []string{origin}
+	defer func() { server.config.corsAllowedOrigins = nil }()
+	request := func(method, path, origin string) *http.Response {
+		req, err := http.NewRequest(method, hostAddr+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	resp := request(http.MethodOptions, proto.AdminGetCluster, origin)
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != origin ||
+		resp.Header.Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("expect the preflight allowed with 204, but got %v and headers %v", resp.StatusCode, resp.Header)
+	}
+	resp = request(http.MethodGet, proto.AdminGetCluster, origin)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != origin {
+		t.Errorf("expect the read allowed to %v, but got %v and headers %v", origin, resp.StatusCode, resp.Header)
+	}
+	resp = request(http.MethodOptions, proto.AdminGetCluster, "http://other.example.com")
+	if resp.StatusCode != http.StatusForbidden || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expect the preflight of other origins forbidden, but got %v and headers %v", resp.StatusCode, resp.Header)
+	}
+	resp = request(http.MethodOptions, proto.AdminCreateVol, origin)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expect the preflight of the mutating APIs forbidden, but got %v", resp.StatusCode)
+	}
+	server.config.corsMutatingAPIs = true
+	defer func() { server.config.corsMutatingAPIs = false }()
+	resp = request(http.MethodOptions, proto.AdminCreateVol, origin)
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != origin {
+		t.Errorf("expect the preflight of the mutating APIs allowed, but got %v and headers %v", resp.StatusCode, resp.Header)
+	}
+}
+
+func TestGzipResponse(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v", hostAddr, proto.AdminGetCluster)
+	getCluster := func(minBytes int) (resp *http.Response, body []byte, err error) {
+		server.config.gzipMinBytes = minBytes
+		defer func() { server.config.gzipMinBytes = defaultGzipMinBytes }()
+		req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+		if err != nil {
+			return
+		}
+		req.Header.Set("Accept-Encoding", "gzip")
+		if resp, err = http.DefaultClient.Do(req); err != nil {
+			return
+		}
+		defer resp.Body.Close()
+		body, err = ioutil.ReadAll(resp.Body)
+		return
+	}
+	resp, body, err := getCluster(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("expect the response compressed, but got header %v", resp.Header)
+		return
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	if err = json.NewDecoder(zr).Decode(reply); err != nil || reply.Code != proto.ErrCodeSuccess {
+		t.Errorf("expect the cluster view, reply[%v] err[%v]", reply, err)
+	}
+	if resp, body, err = getCluster(len(body) * 1000); err != nil {
+		t.Error(err)
+		return
+	}
+	if resp.Header.Get("Content-Encoding") != "" || json.Unmarshal(body, &proto.HTTPReply{}) != nil {
+		t.Errorf("expect the response below the threshold not compressed, but got header %v", resp.Header)
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v", hostAddr, proto.AdminMetrics)
+	fmt.Println(reqURL)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	metrics := string(body)
+	expects := []string{
+		"cubefs_datanode_total ",
+		"cubefs_bad_datapartition_count ",
+		"cubefs_metanode_threshold ",
+		fmt.Sprintf("cubefs_vol_used_bytes{vol=%q} ", commonVol.Name),
+	}
+	for _, expect := range expects {
+		if !strings.Contains(metrics, expect) {
+			t.Errorf("expect metric %v in %v", expect, metrics)
+		}
+	}
+}
+
+func TestExporterMetrics(t *testing.T) {
+	// the /metrics of the exporter is not shadowed by the cluster stats
+	resp, err := http.Get(fmt.Sprintf("%v/metrics", hostAddr))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if metrics := string(body); !strings.Contains(metrics, "# TYPE go_goroutines gauge") || strings.Contains(metrics, "cubefs_vol_used_bytes") {
+		t.Errorf("expect the Prometheus metrics of the exporter, but got %v", metrics)
+	}
+}
+
+func TestHealthProbes(t *testing.T) {
+	for _, path := range []string{proto.AdminLivez, proto.AdminReadyz} {
+		resp, err := http.Get(fmt.Sprintf("%v%v", hostAddr, path))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expect status code %v of %v, but got %v", http.StatusOK, path, resp.StatusCode)
+		}
+	}
+	server.metaReady = false
+	defer func() { server.metaReady = true }()
+	w := httptest.NewRecorder()
+	server.readyz(w, httptest.NewRequest(http.MethodGet, proto.AdminReadyz, nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expect status code %v before the metadata is loaded, but got %v", http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+// The cached views of the clients are sent as they are, they should carry the same headers as the other replies.
+func TestCachedViewReplyHeaders(t *testing.T) {
+	for _, path := range []string{proto.ClientDataPartitions, proto.ClientMetaPartitions} {
+		resp, err := http.Get(fmt.Sprintf("%v%v?name=%v", hostAddr, path, commonVolName))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if contentType := resp.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("expect content type application/json of %v, but got %v", path, contentType)
+		}
+		if resp.ContentLength != int64(len(body)) {
+			t.Errorf("expect content length %v of %v, but got %v", len(body), path, resp.ContentLength)
+		}
+	}
+}
+
+func TestTaskResponseLimiter(t *testing.T) {
+	limiter := server.taskResponseLimiter
+	defer func() { server.taskResponseLimiter = limiter }()
+	server.taskResponseLimiter = newAddrLimiter(1)
+	send := func(remoteAddr string) int {
+		r := httptest.NewRequest(http.MethodPost, proto.GetDataNodeTaskResponse, bytes.NewBufferString("{}"))
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		server.handleDataNodeTaskResponse(w, r)
+		return w.Code
+	}
+	if code := send("192.168.0.1:10001"); code != http.StatusOK {
+		t.Errorf("expect status code %v of the first response, but got %v", http.StatusOK, code)
+	}
+	if code := send("192.168.0.1:10002"); code != http.StatusTooManyRequests {
+		t.Errorf("expect status code %v of the second response, but got %v", http.StatusTooManyRequests, code)
+	}
+	if code := send("192.168.0.2:10001"); code != http.StatusOK {
+		t.Errorf("expect status code %v of another node, but got %v", http.StatusOK, code)
+	}
+}
+
+func TestAddrLimiterEvictIdle(t *testing.T) {
+	limiter := newAddrLimiter(1)
+	now := time.Now()
+	limiter.allowAt("192.168.0.1:10001", now)
+	limiter.allowAt("192.168.0.2:10001", now.Add(addrLimiterIdleTimeout/2))
+	// the sweep drops the address idle for the timeout and keeps the one seen since
+	if !limiter.allowAt("192.168.0.3:10001", now.Add(addrLimiterIdleTimeout)) {
+		t.Errorf("expect the first response of a new address allowed")
+	}
+	if _, ok := limiter.limiters["192.168.0.1"]; ok || len(limiter.limiters) != 2 {
+		t.Errorf("expect the idle address evicted and 2 addresses left, but got %v", limiter.limiters)
+	}
+	if limiter.allowAt("192.168.0.3:10002", now.Add(addrLimiterIdleTimeout)) {
+		t.Errorf("expect the bucket of the address kept after the sweep")
+	}
+}
+
+func TestTaskResponseTooLarge(t *testing.T) {
+	maxBytes := server.config.maxTaskResponseBytes
+	defer func() { server.config.maxTaskResponseBytes = maxBytes }()
+	server.config.maxTaskResponseBytes = 16
+	r := httptest.NewRequest(http.MethodPost, proto.GetMetaNodeTaskResponse, strings.NewReader(`{"ID":"`+strings.Repeat("x", 32)+`"}`))
+	w := httptest.NewRecorder()
+	server.handleMetaNodeTaskResponse(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expect status code %v, but got %v", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+func TestGetDecommissioningNodes(t *testing.T) {
+	dataNode, err := server.cluster.dataNode(mds2Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	metaNode, err := server.cluster.metaNode(mms2Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	dataJob := server.cluster.jobs.register(proto.JobDecommissionDataNode, mds2Addr, 4)
+	dataJob.complete()
+	metaJob := server.cluster.jobs.register(proto.JobMigrateMetaNode, mms2Addr+"->", 2)
+	dataNode.ToBeOffline, metaNode.ToBeOffline = true, true
+	defer func() {
+		dataNode.ToBeOffline, metaNode.ToBeOffline = false, false
+		dataJob.finish(nil)
+		metaJob.finish(nil)
+	}()
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	nodes, err := mc.AdminAPI().GetDecommissioningNodes()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(nodes) != 2 {
+		t.Errorf("expect 2 decommissioning nodes, but got %v", len(nodes))
+		return
+	}
+	if nodes[0].Addr != mds2Addr || nodes[0].Type != topologyTypeData || nodes[0].Job == nil ||
+		nodes[0].Job.ID != dataJob.id || nodes[0].Job.Completed != 1 {
+		t.Errorf("expect data node %v with job %v, but got %v", mds2Addr, dataJob.id, nodes[0])
+	}
+	if nodes[0].RemainingPartitions != len(server.cluster.getAllDataPartitionByDataNode(mds2Addr)) {
+		t.Errorf("expect the partitions remaining on %v, but got %v", mds2Addr, nodes[0].RemainingPartitions)
+	}
+	if nodes[1].Addr != mms2Addr || nodes[1].Type != topologyTypeMeta || nodes[1].Job == nil || nodes[1].Job.ID != metaJob.id {
+		t.Errorf("expect meta node %v with job %v, but got %v", mms2Addr, metaJob.id, nodes[1])
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	job := server.cluster.jobs.register(proto.JobDecommissionDisk, "127.0.0.1:9101:/cfs", 2)
+	job.complete()
+	job.fail()
+	job.finish(fmt.Errorf("partition not exists"))
+	reqURL := fmt.Sprintf("%v%v?id=%v", hostAddr, proto.AdminGetJob, job.id)
+	reply := process(reqURL, t)
+	view := &proto.JobView{}
+	data, _ := json.Marshal(reply.Data)
+	if err := json.Unmarshal(data, view); err != nil {
+		t.Error(err)
+		return
+	}
+	if view.ID != job.id || view.Total != 2 || view.Completed != 1 || view.Failed != 1 || view.State != proto.JobStateFailed {
+		t.Errorf("unexpected job %v", view)
+	}
+
+	reply = process(fmt.Sprintf("%v%v", hostAddr, proto.AdminGetJob), t)
+	found := false
+	for _, item := range reply.Data.([]interface{}) {
+		if item.(map[string]interface{})["ID"] == job.id {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("job %v is not listed", job.id)
+	}
+
+	// the finished jobs are dropped after the retention
+	registry := newJobRegistry(0)
+	expired := registry.register(proto.JobDecommissionDisk, "127.0.0.1:9101:/cfs", 1)
+	expired.finish(nil)
+	time.Sleep(time.Millisecond)
+	if _, err := registry.get(expired.id); err == nil {
+		t.Errorf("job %v should be dropped after the retention", expired.id)
+	}
+}
+
+func TestAddDuplicateNode(t *testing.T) {
+	dataNode, err := server.cluster.dataNode(mds1Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	metaNode, err := server.cluster.metaNode(mms1Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for path, node := range map[string]struct {
+		addr string
+		id   uint64
+	}{
+		proto.AddDataNode: {mds1Addr, dataNode.ID},
+		proto.AddMetaNode: {mms1Addr, metaNode.ID},
+	} {
+		resp, err := http.Get(fmt.Sprintf("%v%v?addr=%v&zoneName=%v", hostAddr, path, node.addr, testZone1))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil || reply.Code != proto.ErrCodeDuplicateNode || reply.Data != float64(node.id) {
+			t.Errorf("expect %v to be rejected with the existing id %v, reply[%v] err[%v]", node.addr, node.id, reply, err)
+		}
+	}
+
+	// the nodes registering themselves again go on with the existing ids
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if id, err := mc.NodeAPI().AddDataNode(mds1Addr, testZone1); err != nil || id != dataNode.ID {
+		t.Errorf("expect the existing id %v of %v, but got %v, err[%v]", dataNode.ID, mds1Addr, id, err)
+	}
+	if id, err := mc.NodeAPI().AddMetaNode(mms1Addr, testZone1); err != nil || id != metaNode.ID {
+		t.Errorf("expect the existing id %v of %v, but got %v, err[%v]", metaNode.ID, mms1Addr, id, err)
+	}
+}
+
+func TestGetUnderReplicatedPartitions(t *testing.T) {
+	process(fmt.Sprintf("%v%v", hostAddr, proto.AdminGetUnderReplicated), t)
+
+	dp := commonVol.dataPartitions.partitions[0]
+	dp.Lock()
+	if len(dp.Replicas) == 0 {
+		dp.Unlock()
+		t.Errorf("no replicas of data partition[%v]", dp.PartitionID)
+		return
+	}
+	replica := dp.Replicas[0]
+	reportTime := replica.ReportTime
+	replica.ReportTime = 0
+	dp.Unlock()
+	report := server.cluster.getUnderReplicatedPartitions()
+	dp.Lock()
+	replica.ReportTime = reportTime
+	dp.Unlock()
+
+	for _, view := range report.DataPartitions {
+		if view.PartitionID != dp.PartitionID {
+			continue
+		}
+		if view.LiveReplicaNum >= view.ReplicaNum || contains(view.LiveHosts, replica.Addr) {
+			t.Errorf("expect replica %v of data partition[%v] not to be live, but got %v", replica.Addr, dp.PartitionID, view)
+		}
+		return
+	}
+	t.Errorf("data partition[%v] is not reported as under replicated", dp.PartitionID)
+}
+
+func TestGetOverReplicatedPartitions(t *testing.T) {
+	extraAddr := "127.0.0.1:9999"
+	dp := commonVol.dataPartitions.partitions[0]
+	dp.Lock()
+	dp.Hosts = append(dp.Hosts, extraAddr)
+	dp.Unlock()
+	defer func() {
+		dp.Lock()
+		dp.Hosts = dp.Hosts[:len(dp.Hosts)-1]
+		dp.Unlock()
+	}()
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	views, err := mc.AdminAPI().GetOverReplicatedDataPartitions()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, view := range views {
+		if view.PartitionID != dp.PartitionID {
+			continue
+		}
+		if len(view.ExtraHosts) != 1 || view.ExtraHosts[0] != extraAddr || len(view.Hosts) != int(view.ReplicaNum)+1 {
+			t.Errorf("expect the extra host %v of data partition[%v], but got %v", extraAddr, dp.PartitionID, view)
+		}
+		return
+	}
+	t.Errorf("data partition[%v] is not reported as over replicated", dp.PartitionID)
+}
+
+func TestGetReplicaLag(t *testing.T) {
+	dp := commonVol.dataPartitions.partitions[0]
+	dp.Lock()
+	if len(dp.Replicas) < 2 {
+		dp.Unlock()
+		t.Errorf("expect at least 2 replicas of data partition[%v], but got %v", dp.PartitionID, len(dp.Replicas))
+		return
+	}
+	leader, follower := dp.Replicas[0], dp.Replicas[1]
+	isLeaders := make([]bool, len(dp.Replicas))
+	applyIDs := make([]uint64, len(dp.Replicas))
+	for i, replica := range dp.Replicas {
+		isLeaders[i], applyIDs[i] = replica.IsLeader, replica.ApplyID
+		replica.IsLeader, replica.ApplyID = false, 1<<40
+	}
+	leader.IsLeader = true
+	follower.ApplyID -= 1 << 20
+	dp.Unlock()
+	defer func() {
+		dp.Lock()
+		for i, replica := range dp.Replicas {
+			replica.IsLeader, replica.ApplyID = isLeaders[i], applyIDs[i]
+		}
+		dp.Unlock()
+	}()
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	lags, err := mc.AdminAPI().GetReplicaLag(dp.PartitionID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, lag := range lags {
+		expect := uint64(0)
+		if lag.Addr == follower.Addr {
+			expect = 1 << 20
+		}
+		if lag.Lag != expect || lag.IsLeader != (lag.Addr == leader.Addr) {
+			t.Errorf("expect the lag %v of replica %v of data partition[%v], but got %v", expect, lag.Addr, dp.PartitionID, *lag)
+		}
+	}
+	lags, err = mc.AdminAPI().GetLaggingReplicas(1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(lags) != 1 || lags[0].PartitionID != dp.PartitionID || lags[0].Addr != follower.Addr || lags[0].Lag != 1<<20 {
+		t.Errorf("expect replica %v of data partition[%v] lagging most, but got %v", follower.Addr, dp.PartitionID, lags)
+	}
+}
+
+func TestLiveReplicaNum(t *testing.T) {
+	dp := commonVol.dataPartitions.partitions[0]
+	dp.RLock()
+	host := dp.Hosts[0]
+	dp.RUnlock()
+	dataNode, err := server.cluster.dataNode(host)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	info, err := mc.AdminAPI().GetDataPartition(commonVol.Name, dp.PartitionID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	liveNum := info.LiveReplicaNum
+	if liveNum == 0 {
+		t.Errorf("expect the live replicas of data partition[%v] counted, but got %v", dp.PartitionID, liveNum)
+		return
+	}
+	dataNode.Lock()
+	dataNode.isActive = false
+	dataNode.Unlock()
+	defer func() {
+		dataNode.Lock()
+		dataNode.isActive = true
+		dataNode.Unlock()
+	}()
+	if info, err = mc.AdminAPI().GetDataPartition(commonVol.Name, dp.PartitionID); err != nil {
+		t.Error(err)
+		return
+	}
+	if info.LiveReplicaNum != liveNum-1 {
+		t.Errorf("expect %v live replicas after %v is inactive, but got %v", liveNum-1, host, info.LiveReplicaNum)
+	}
+	if dpr := dp.convertToDataPartitionResponse(); dpr.LiveReplicaNum != liveNum-1 {
+		t.Errorf("expect %v live replicas responded to the clients, but got %v", liveNum-1, dpr.LiveReplicaNum)
+	}
+}
+
+func TestGetDataNodeDisks(t *testing.T) {
+	server.cluster.checkDataNodeHeartbeat()
+	time.Sleep(5 * time.Second)
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	disks, err := mc.NodeAPI().GetDataNodeDisks(mds3Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	partitionCount := 0
+	for _, disk := range disks {
+		partitionCount += disk.PartitionCount
+	}
+	if len(disks) == 0 || partitionCount == 0 {
+		t.Errorf("expect the disks holding the partitions of %v, but got %v", mds3Addr, disks)
+	}
+	resp, err := http.Get(fmt.Sprintf("%v%v?addr=%v", hostAddr, proto.GetDataNodeDisks, "127.0.0.1:9999"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expect status %v for an unknown data node, but got %v", http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestRecommissionDisk(t *testing.T) {
+	addr, diskPath := mds3Addr, "/cfs"
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if _, err := mc.NodeAPI().RecommissionDisk(addr, diskPath); err == nil {
+		t.Errorf("expect the disk not decommissioned rejected")
+	}
+	dataNode, err := server.cluster.dataNode(addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = server.cluster.markDiskDecommissioned(dataNode, diskPath); err != nil {
+		t.Error(err)
+		return
+	}
+	task := dataNode.createHeartbeatTask(server.cluster.masterAddr(), false)
+	if disks := task.Request.(*proto.HeartBeatRequest).DecommissionedDisks; !contains(disks, diskPath) {
+		t.Errorf("expect the heartbeat to carry the decommissioned disk %v, but got %v", diskPath, disks)
+	}
+	disk, err := mc.NodeAPI().RecommissionDisk(addr, diskPath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if disk.Path != diskPath || disk.Decommissioned {
+		t.Errorf("expect the disk %v recommissioned, but got %v", diskPath, disk)
+	}
+	if dataNode.isDiskDecommissioned(diskPath) {
+		t.Errorf("expect the disk %v of %v recommissioned", diskPath, addr)
+	}
+	if _, err = mc.NodeAPI().RecommissionDisk(addr, diskPath); err == nil {
+		t.Errorf("expect the disk recommissioned twice rejected")
+	}
+}
+
+func TestDataPartitionReplicas(t *testing.T) {
+	reply := process(fmt.Sprintf("%v%v?name=%v", hostAddr, proto.ClientDataPartitions, commonVolName), t)
+	data, _ := json.Marshal(reply.Data)
+	view := &proto.DataPartitionsView{}
+	if err := json.Unmarshal(data, view); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(view.DataPartitions) == 0 {
+		t.Errorf("no data partitions of vol %v", commonVolName)
+		return
+	}
+	var readWrite, readOnly int
+	for _, dp := range view.DataPartitions {
+		switch dp.Status {
+		case proto.ReadWrite:
+			readWrite++
+		case proto.ReadOnly:
+			readOnly++
+		}
+	}
+	if view.Total != len(view.DataPartitions) || view.ReadWriteCount != readWrite || view.ReadOnlyCount != readOnly {
+		t.Errorf("expect total %v readWrite %v readOnly %v, but got %v %v %v", len(view.DataPartitions), readWrite, readOnly,
+			view.Total, view.ReadWriteCount, view.ReadOnlyCount)
+	}
+	for _, dp := range view.DataPartitions {
+		if len(dp.Replicas) == 0 {
+			t.Errorf("no replicas of data partition[%v]", dp.PartitionID)
+		}
+		for _, replica := range dp.Replicas {
+			if !contains(dp.Hosts, replica.Addr) {
+				t.Errorf("unexpected replica %v of data partition[%v] with hosts %v", replica, dp.PartitionID, dp.Hosts)
+			}
+		}
+	}
+}
+
+func TestVolNameRule(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"abc":                   true,
+		"a.b-c_1":               true,
+		"ab":                    false,
+		"-abc":                  false,
+		"abc.":                  false,
+		strings.Repeat("a", 63): true,
+		strings.Repeat("a", 64): false,
+	} {
+		if err := checkVolName(name); (err == nil) != valid {
+			t.Errorf("name[%v] expect valid[%v], err[%v]", name, valid, err)
+		}
+	}
+	rule, err := newNameRule(1, 255, "^[a-z0-9.]+$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rule.check(strings.Repeat("a.", 100)); err != nil {
+		t.Errorf("expect long name with dots valid, err[%v]", err)
+	}
+	if err = rule.check("A"); err == nil {
+		t.Errorf("expect name not matching the pattern invalid")
+	}
+	if _, err = newNameRule(10, 5, defaultVolNamePattern); err == nil {
+		t.Errorf("expect invalid length range rejected")
+	}
+	if _, err = newNameRule(3, 63, "[a-z"); err == nil {
+		t.Errorf("expect invalid pattern rejected")
+	}
+}
+
+func BenchmarkCheckVolName(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := checkVolName(commonVolName); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// The way the names were checked before the pattern was compiled once, as the baseline of BenchmarkCheckVolName.
+func BenchmarkCheckVolNameCompileEachTime(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if match, _ := regexp.MatchString(defaultVolNamePattern, commonVolName); !match {
+			b.Fatal("name not matched")
+		}
+	}
+}
+
+func BenchmarkCheckIp(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !checkIp(mds1Addr) {
+			b.Fatal("ip not matched")
+		}
+	}
+}
+
+func TestGetIpAndClusterName(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v", hostAddr, proto.AdminGetIP)
+	fmt.Println(reqURL)
+	process(reqURL, t)
+}
+
+func process(reqURL string, t *testing.T) (reply *proto.HTTPReply) {
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Errorf("err is %v", err)
+		return
+	}
+	fmt.Println(resp.StatusCode)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Errorf("err is %v", err)
+		return
+	}
+	fmt.Println(string(body))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		t.Errorf("status code[%v]", resp.StatusCode)
+		return
+	}
+	reply = &proto.HTTPReply{}
+	if err = json.Unmarshal(body, reply); err != nil {
+		t.Error(err)
+		return
+	}
+	if reply.Code != 0 {
+		t.Errorf("failed,msg[%v],data[%v]", reply.Msg, reply.Data)
+		return
+	}
+	return
+}
+
+func TestDisk(t *testing.T) {
+	addr := mds5Addr
+	disk := "/cfs"
+	decommissionDisk(addr, disk, t)
+}
+
+func TestCancelDecommissionDisk(t *testing.T) {
+	addr, disk := mds1Addr, "/cfs"
+	reqURL := fmt.Sprintf("%v%v?addr=%v&disk=%v", hostAddr, proto.CancelDecommissionDisk, addr, disk)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code == proto.ErrCodeSuccess {
+		t.Errorf("expect the cancel to fail without decommission, reply[%v] err[%v]", reply, err)
+	}
+
+	key := diskDecommissionKey(addr, disk)
+	decommission := &diskDecommission{pending: 3, job: server.cluster.jobs.register(proto.JobDecommissionDisk, key, 3)}
+	server.cluster.diskDecommissions.Store(key, decommission)
+	defer server.cluster.diskDecommissions.Delete(key)
+	// a second decommission of the disk is refused without registering a job
+	node, err := server.cluster.dataNode(addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	jobs := len(server.cluster.jobs.list())
+	if jobID, err := server.cluster.decommissionDisk(node, disk, nil); err == nil || jobID != "" || len(server.cluster.jobs.list()) != jobs {
+		t.Errorf("expect the second decommission of %v to be refused without a job, job[%v] err[%v]", key, jobID, err)
+	}
+	reply = process(reqURL, t)
+	data := reply.Data.(map[string]interface{})
+	if pending := data["PendingPartitions"]; pending != float64(3) {
+		t.Errorf("expect 3 pending partitions, but got %v", pending)
+	}
+	if jobID := data["JobID"]; jobID != decommission.job.id {
+		t.Errorf("expect job %v, but got %v", decommission.job.id, jobID)
+	}
+	if decommission.canceled != 1 {
+		t.Errorf("expect the decommission of %v to be canceled", key)
+	}
+	if state := decommission.job.toView().State; state != proto.JobStateCanceled {
+		t.Errorf("expect the job of %v to be %v, but got %v", key, proto.JobStateCanceled, state)
+	}
+}
+
+func TestDecommissionDryRun(t *testing.T) {
+	// the disks of the replicas are reported by the heartbeats
+	server.cluster.checkDataNodeHeartbeat()
+	time.Sleep(5 * time.Second)
+	partition := commonVol.dataPartitions.partitions[1]
+	partition.RLock()
+	addr := partition.Hosts[0]
+	hosts := append([]string(nil), partition.Hosts...)
+	partition.RUnlock()
+	checkResult := func(reqURL string) {
+		reply := process(reqURL, t)
+		if reply == nil {
+			return
+		}
+		data, _ := json.Marshal(reply.Data)
+		result := &proto.DecommissionDryRun{}
+		if err := json.Unmarshal(data, result); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(result.Moves) == 0 {
+			t.Errorf("expect the moves of [%v], but got none", addr)
+		}
+		for _, move := range result.Moves {
+			if move.Err == "" && (move.TargetAddr == "" || move.TargetAddr == addr) {
+				t.Errorf("expect partition[%v] to move off [%v], but got target[%v]", move.PartitionID, addr, move.TargetAddr)
+			}
+		}
+		partition.RLock()
+		defer partition.RUnlock()
+		if strings.Join(partition.Hosts, ",") != strings.Join(hosts, ",") {
+			t.Errorf("expect the hosts %v unchanged by the dry run, but got %v", hosts, partition.Hosts)
+		}
+	}
+	checkResult(fmt.Sprintf("%v%v?id=%v&addr=%v&dryRun=true", hostAddr, proto.AdminDecommissionDataPartition, partition.PartitionID, addr))
+	checkResult(fmt.Sprintf("%v%v?addr=%v&disk=/cfs&dryRun=true", hostAddr, proto.DecommissionDisk, addr))
+}
+
+func decommissionDisk(addr, path string, t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?addr=%v&disk=%v",
+		hostAddr, proto.DecommissionDisk, addr, path)
+	fmt.Println(reqURL)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Errorf("err is %v", err)
+		return
+	}
+	fmt.Println(resp.StatusCode)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Errorf("err is %v", err)
+		return
+	}
+	fmt.Println(string(body))
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status code[%v]", resp.StatusCode)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	if err = json.Unmarshal(body, reply); err != nil {
+		t.Error(err)
+		return
+	}
+	server.cluster.checkDataNodeHeartbeat()
+	time.Sleep(5 * time.Second)
+	server.cluster.checkDiskRecoveryProgress()
+}
+
+func TestMarkDeleteVol(t *testing.T) {
+	name := "delVol"
+	createVol(name, t)
+	reqURL := fmt.Sprintf("%v%v?name=%v&authKey=%v", hostAddr, proto.AdminDeleteVol, name, buildAuthKey("cfs"))
+	process(reqURL, t)
+	userInfo, err := server.user.getUserInfo("cfs")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if contains(userInfo.Policy.OwnVols, name) {
+		t.Errorf("expect no vol %v in own vols, but is exist", name)
+		return
+	}
+}
+
+func TestForceDeleteVol(t *testing.T) {
+	name := "forceDelVol"
+	createVol(name, t)
+	reqURL := fmt.Sprintf("%v%v?name=%v&authKey=%v&force=true", hostAddr, proto.AdminDeleteVol, name, buildAuthKey("cfs"))
+	// refused without the admin token configured
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect force deletion refused without the admin token, reply[%v] err[%v]", reply, err)
+		return
+	}
+
+	server.config.adminToken = "test_token"
+	defer func() { server.config.adminToken = "" }()
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	mc.SetAdminToken(server.config.adminToken)
+	count, err := mc.AdminAPI().ForceDeleteVolume(name, buildAuthKey("cfs"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if count == 0 {
+		t.Errorf("expect the partitions of vol %v scheduled for deletion", name)
+	}
+	if err = mc.AdminAPI().UndeleteVolume(name, buildAuthKey("cfs")); err == nil {
+		t.Errorf("expect vol %v reclaimed not to be undeleted", name)
+	}
+}
+
+func TestUndeleteVol(t *testing.T) {
+	name := "undelVol"
+	server.config.volDeletionGraceSec = 300
+	defer func() { server.config.volDeletionGraceSec = 0 }()
+	createVol(name, t)
+	markDeleteVol(name, t)
+	vol, err := server.cluster.getVol(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// the partitions are kept within the grace period
+	vol.checkStatus(server.cluster)
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err = mc.AdminAPI().UndeleteVolume(name, buildAuthKey("cfs")); err != nil {
+		t.Error(err)
+		return
+	}
+	if vol.status() != normal {
+		t.Errorf("expect vol %v status %v, but got %v", name, normal, vol.status())
+	}
+	userInfo, err := server.user.getUserInfo("cfs")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !contains(userInfo.Policy.OwnVols, name) {
+		t.Errorf("expect vol %v in own vols, but is not", name)
+	}
+	if err = mc.AdminAPI().UndeleteVolume(name, buildAuthKey("cfs")); err == nil {
+		t.Errorf("expect vol %v not marked deleted to be rejected", name)
+	}
+}
+
+func TestRenameVol(t *testing.T) {
+	name, newName := "renameVol", "renamedVol"
+	createVol(name, t)
+	vol, err := server.cluster.getVol(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err = mc.AdminAPI().RenameVolume(name, commonVolName, buildAuthKey("cfs")); err == nil {
+		t.Errorf("expect the rename of vol %v to an existing name to be rejected", name)
+	}
+	if err = mc.AdminAPI().RenameVolume(name, "renamed vol", buildAuthKey("cfs")); err == nil {
+		t.Errorf("expect the rename of vol %v to an invalid name to be rejected", name)
+	}
+	if err = mc.AdminAPI().RenameVolume(name, newName, buildAuthKey("cfs")); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = server.cluster.getVol(name); err == nil {
+		t.Errorf("expect vol %v to be gone", name)
+	}
+	if renamed, err := server.cluster.getVol(newName); err != nil || renamed != vol {
+		t.Errorf("expect vol %v to be found by %v, err %v", name, newName, err)
+	}
+	for _, dp := range vol.cloneDataPartitionMap() {
+		if dp.VolName != newName {
+			t.Errorf("expect vol name %v of data partition %v, but got %v", newName, dp.PartitionID, dp.VolName)
+		}
+		// the nodes still report the former name
+		if _, found, err := server.cluster.getReportedDataPartition(name, dp.PartitionID); err != nil || found != dp {
+			t.Errorf("expect data partition %v reported by %v to be found, err %v", dp.PartitionID, name, err)
+		}
+	}
+	for _, mp := range vol.cloneMetaPartitionMap() {
+		if mp.volName != newName {
+			t.Errorf("expect vol name %v of meta partition %v, but got %v", newName, mp.PartitionID, mp.volName)
+		}
+	}
+	userInfo, err := server.user.getUserInfo("cfs")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !contains(userInfo.Policy.OwnVols, newName) || contains(userInfo.Policy.OwnVols, name) {
+		t.Errorf("expect vol %v instead of %v in own vols, but got %v", newName, name, userInfo.Policy.OwnVols)
+	}
+	markDeleteVol(newName, t)
+	if err = mc.AdminAPI().RenameVolume(newName, name, buildAuthKey("cfs")); err == nil {
+		t.Errorf("expect the rename of vol %v marked deleted to be rejected", newName)
+	}
+}
+
+func TestSetVolAllocationPriority(t *testing.T) {
+	name, newName := "allocPriorityVol", "allocPriorityRenamedVol"
+	createVol(name, t)
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err := mc.AdminAPI().SetVolAllocationPriority(name, 5, maxVolAllocPriorityTTL+1); err == nil {
+		t.Errorf("expect the ttl above %v rejected", maxVolAllocPriorityTTL)
+	}
+	if err := mc.AdminAPI().SetVolAllocationPriority(name, 5, 600); err != nil {
+		t.Error(err)
+		return
+	}
+	checkPriority := func(volName string, expect int) {
+		cv, err := mc.AdminAPI().GetCluster()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		priority := 0
+		for _, view := range cv.VolAllocPriorities {
+			if view.VolName == volName {
+				priority = view.Priority
+			}
+		}
+		if priority != expect {
+			t.Errorf("expect the allocation priority %v of vol[%v], but got %v", expect, volName, priority)
+		}
+	}
+	checkPriority(name, 5)
+	// the priority follows the volume renamed
+	if err := mc.AdminAPI().RenameVolume(name, newName, buildAuthKey("cfs")); err != nil {
+		t.Error(err)
+		return
+	}
+	checkPriority(name, 0)
+	checkPriority(newName, 5)
+	if err := mc.AdminAPI().SetVolAllocationPriority(newName, 0, 600); err != nil {
+		t.Error(err)
+		return
+	}
+	checkPriority(newName, 0)
+}
+
+func TestSetVolCapacity(t *testing.T) {
+	setVolCapacity(600, proto.AdminVolExpand, t)
+	setVolCapacity(300, proto.AdminVolShrink, t)
+}
+
+func TestUpdateVol(t *testing.T) {
+	capacity := 2000
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v",
+		hostAddr, proto.AdminUpdateVol, commonVol.Name, capacity, buildAuthKey("cfs"))
+	process(reqURL, t)
+	vol, err := server.cluster.getVol(commonVolName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if vol.FollowerRead != false {
+		t.Errorf("expect FollowerRead is false, but is %v", vol.FollowerRead)
+		return
+	}
+
+	reqURL = fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v&followerRead=true&zoneName=%v",
+		hostAddr, proto.AdminUpdateVol, commonVol.Name, capacity, buildAuthKey("cfs"), commonVol.zoneName)
+	process(reqURL, t)
+	if vol.FollowerRead != true {
+		t.Errorf("expect FollowerRead is true, but is %v", vol.FollowerRead)
+		return
+	}
+
+}
+
+func TestUpdateVolCapacityBelowUsedSpace(t *testing.T) {
+	volName := "shrinkBelowUsedVol"
+	createVol(volName, t)
+	defer markDeleteVol(volName, t)
+	vol, err := server.cluster.getVol(volName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// wait for the heartbeats to report the used space of every data partition
+	server.cluster.checkDataNodeHeartbeat()
+	for i := 0; i < 50 && !allDataPartitionsUsed(vol); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	used := vol.totalUsedSpace()
+	if used == 0 || used%util.GB != 0 {
+		t.Errorf("expect the used space of vol %v reported in GB, but got %v", volName, used)
+		return
+	}
+	usedGB := used / util.GB
+	update := func(capacity uint64, force bool) *proto.HTTPReply {
+		reqURL := fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v&force=%v",
+			hostAddr, proto.AdminUpdateVol, volName, capacity, buildAuthKey("cfs"), force)
+		resp, err := http.Get(reqURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		reply := &proto.HTTPReply{}
+		if err = json.NewDecoder(resp.Body).Decode(reply); err != nil {
+			t.Fatal(err)
+		}
+		return reply
+	}
+	if reply := update(usedGB*2, false); reply.Code != proto.ErrCodeSuccess {
+		t.Errorf("expect the capacity expanded to %v, but got %v", usedGB*2, reply)
+		return
+	}
+	if reply := update(usedGB-1, false); reply.Code == proto.ErrCodeSuccess || vol.Capacity != usedGB*2 {
+		t.Errorf("expect the capacity %v below the used space %vGB rejected, reply[%v] capacity[%v]",
+			usedGB-1, usedGB, reply, vol.Capacity)
+	}
+	reply := update(usedGB, false)
+	if reply.Code != proto.ErrCodeSuccess || vol.Capacity != usedGB {
+		t.Errorf("expect the capacity shrunk to the used space %vGB, reply[%v] capacity[%v]", usedGB, reply, vol.Capacity)
+	}
+	if msg := fmt.Sprintf("capacity from %vGB to %vGB", usedGB*2, usedGB); !strings.Contains(reply.Data.(string), msg) {
+		t.Errorf("expect the message to report %v, but got %v", msg, reply.Data)
+	}
+	if reply = update(usedGB-1, true); reply.Code != proto.ErrCodeSuccess || vol.Capacity != usedGB-1 {
+		t.Errorf("expect force to shrink the capacity below the used space, reply[%v] capacity[%v]", reply, vol.Capacity)
+	}
+}
+
+func allDataPartitionsUsed(vol *Vol) bool {
+	for _, dp := range vol.cloneDataPartitionMap() {
+		if dp.getMaxUsedSpace() == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func setVolCapacity(capacity uint64, url string, t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v",
+		hostAddr, url, commonVol.Name, capacity, buildAuthKey("cfs"))
+	process(reqURL, t)
+	vol, err := server.cluster.getVol(commonVolName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if vol.Capacity != capacity {
+		t.Errorf("expect capacity is %v, but is %v", capacity, vol.Capacity)
+		return
+	}
+}
+
+func buildAuthKey(owner string) string {
+	h := md5.New()
+	h.Write([]byte(owner))
+	cipherStr := h.Sum(nil)
+	return hex.EncodeToString(cipherStr)
+}
+
+func TestSetVolIOPriority(t *testing.T) {
+	oldClass := commonVol.ioPriorityClass
+	defer func() {
+		commonVol.volLock.Lock()
+		commonVol.setIOPriorityClass(oldClass)
+		commonVol.volLock.Unlock()
+		commonVol.dataPartitions.updateResponseCache(true, 0)
+	}()
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err := mc.AdminAPI().SetVolIOPriority(commonVol.Name, proto.IOPriorityHigh); err != nil {
+		t.Error(err)
+		return
+	}
+	vv, err := mc.AdminAPI().GetVolumeSimpleInfo(commonVol.Name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if vv.IOPriorityClass != proto.IOPriorityHigh {
+		t.Errorf("expect IO priority class %v, but got %v", proto.IOPriorityHigh, vv.IOPriorityClass)
+	}
+	// the class is carried with the data partitions to the clients
+	view, err := mc.ClientAPI().GetDataPartitions(commonVol.Name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, dp := range view.DataPartitions {
+		if dp.IOPriorityClass != proto.IOPriorityHigh {
+			t.Errorf("expect IO priority class %v of data partition[%v], but got %v", proto.IOPriorityHigh, dp.PartitionID, dp.IOPriorityClass)
+		}
+	}
+	cases := []string{
+		fmt.Sprintf("?name=%v&class=urgent", commonVol.Name),
+		fmt.Sprintf("?name=%v", commonVol.Name),
+		"?class=low",
+	}
+	for _, query := range cases {
+		resp, err := http.Get(hostAddr + proto.AdminSetVolIOPriority + query)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil || reply.Code != proto.ErrCodeParamError {
+			t.Errorf("expect [%v] rejected, reply[%v] err[%v]", query, reply, err)
+		}
+	}
+	if err = mc.AdminAPI().SetVolIOPriority("not_exists_vol", proto.IOPriorityLow); err == nil {
+		t.Errorf("expect the IO priority of an unknown vol rejected")
+	}
+	if commonVol.ioPriorityClass != proto.IOPriorityHigh {
+		t.Errorf("expect the rejected requests to keep the class %v, but got %v", proto.IOPriorityHigh, commonVol.ioPriorityClass)
+	}
+}
+
+func TestSetVolReadOnly(t *testing.T) {
+	getStatus := func() float64 {
+		reply := process(fmt.Sprintf("%v%v?name=%v", hostAddr, proto.AdminGetVol, commonVol.Name), t)
+		return reply.Data.(map[string]interface{})["Status"].(float64)
+	}
+	process(fmt.Sprintf("%v%v?name=%v&enable=true", hostAddr, proto.AdminSetVolReadOnly, commonVol.Name), t)
+	defer func() {
+		commonVol.checkDataPartitions(server.cluster)
+		commonVol.checkMetaPartitions(server.cluster)
+	}()
+	if !commonVol.isReadOnly() || getStatus() != float64(proto.VolStatusReadOnly) {
+		t.Errorf("expect vol %v to be read only", commonVol.Name)
+	}
+	if cnt := commonVol.checkDataPartitions(server.cluster); cnt != 0 {
+		t.Errorf("expect no writable data partitions of the read only vol, but got %v", cnt)
+	}
+	commonVol.checkMetaPartitions(server.cluster)
+	for _, mp := range commonVol.cloneMetaPartitionMap() {
+		if mp.Status == proto.ReadWrite {
+			t.Errorf("expect meta partition[%v] of the read only vol not to be writable", mp.PartitionID)
+		}
+	}
+
+	process(fmt.Sprintf("%v%v?name=%v&enable=false", hostAddr, proto.AdminSetVolReadOnly, commonVol.Name), t)
+	if commonVol.isReadOnly() || getStatus() != float64(normal) {
+		t.Errorf("expect vol %v to be writable", commonVol.Name)
+	}
+}
+
+func TestGetVolSkew(t *testing.T) {
+	// a replica on a host out of the zone of the vol takes no fair share
+	extraAddr := "127.0.0.1:9999"
+	dp := commonVol.dataPartitions.partitions[0]
+	dp.Lock()
+	dp.Hosts = append(dp.Hosts, extraAddr)
+	dp.Unlock()
+	defer func() {
+		dp.Lock()
+		dp.Hosts = dp.Hosts[:len(dp.Hosts)-1]
+		dp.Unlock()
+	}()
+	counts := make(map[string]int)
+	replicaCount := 0
+	for _, partition := range commonVol.cloneDataPartitionMap() {
+		partition.RLock()
+		for _, host := range partition.Hosts {
+			counts[host]++
+			replicaCount++
+		}
+		partition.RUnlock()
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	skew, err := mc.AdminAPI().GetVolSkew(commonVol.Name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if skew.ReplicaCount != replicaCount || skew.NodeCount == 0 {
+		t.Errorf("expect %v replicas on the data nodes of the zone, but got %v", replicaCount, *skew)
+		return
+	}
+	if fairShare := fixedPoint(float64(replicaCount)/float64(skew.NodeCount), 2); skew.FairShare != fairShare {
+		t.Errorf("expect the fair share %v, but got %v", fairShare, skew.FairShare)
+	}
+	maxCount, foundExtra := 0, false
+	for i, node := range skew.Nodes {
+		if node.PartitionCount != counts[node.Addr] {
+			t.Errorf("expect %v replicas on %v, but got %v", counts[node.Addr], node.Addr, node.PartitionCount)
+		}
+		if node.PartitionCount > maxCount {
+			maxCount = node.PartitionCount
+		}
+		if i > 0 && node.Deviation > skew.Nodes[i-1].Deviation {
+			t.Errorf("expect the nodes sorted by the deviation, but got %v before %v", *skew.Nodes[i-1], *node)
+		}
+		if node.Addr != extraAddr {
+			continue
+		}
+		foundExtra = true
+		if node.Deviation != float64(node.PartitionCount) {
+			t.Errorf("expect the deviation of %v to be its replica count, but got %v", extraAddr, *node)
+		}
+	}
+	if !foundExtra {
+		t.Errorf("expect the replica on %v reported, but got %v", extraAddr, skew.Nodes)
+	}
+	if ratio := fixedPoint(float64(maxCount)/skew.FairShare, 2); skew.SkewRatio != ratio {
+		t.Errorf("expect the skew ratio %v, but got %v", ratio, skew.SkewRatio)
+	}
+	if _, err = mc.AdminAPI().GetVolSkew("not_exists_vol"); err == nil {
+		t.Errorf("expect the skew of an unknown vol rejected")
+	}
+}
+
+func TestGetVolSimpleInfo(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?name=%v", hostAddr, proto.AdminGetVol, commonVol.Name)
+	process(reqURL, t)
+}
+
+func TestHeadVol(t *testing.T) {
+	cases := map[string]int{
+		fmt.Sprintf("?name=%v", commonVol.Name): http.StatusOK,
+		"?name=not_exists_vol":                  http.StatusNotFound,
+		"":                                      http.StatusBadRequest,
+	}
+	for query, status := range cases {
+		resp, err := http.Head(hostAddr + proto.AdminGetVol + query)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != status || len(body) != 0 {
+			t.Errorf("expect status %v without body for [%v], but got %v and %v bytes", status, query, resp.StatusCode, len(body))
+		}
+	}
+}
+
+func TestPreviewVolDeletion(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?name=%v", hostAddr, proto.AdminPreviewVolDeletion, commonVol.Name)
+	fmt.Println(reqURL)
+	reply := process(reqURL, t)
+	if reply == nil {
+		return
+	}
+	preview := reply.Data.(map[string]interface{})
+	if int(preview["DataPartitions"].(float64)) != len(commonVol.dataPartitions.partitions) {
+		t.Errorf("expect %v data partitions, but got %v", len(commonVol.dataPartitions.partitions), preview["DataPartitions"])
+	}
+}
+
+func TestSetAutoRebalancePolicy(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?enable=true&minScore=0.7&windowStart=22&windowEnd=6&maxMoves=3", hostAddr, proto.AdminSetAutoRebalancePolicy)
+	fmt.Println(reqURL)
+	process(reqURL, t)
+	policy := server.cluster.autoRebalancer.getPolicy()
+	if !policy.Enable || policy.MinBalanceScore != 0.7 || policy.MaxConcurrentMoves != 3 {
+		t.Errorf("unexpected policy %v", *policy)
+	}
+	if inRebalanceWindow(policy, time.Date(2021, 1, 1, 12, 0, 0, 0, time.Local)) {
+		t.Errorf("expect 12:00 out of the window [%v, %v)", policy.WindowStartHour, policy.WindowEndHour)
+	}
+	if !inRebalanceWindow(policy, time.Date(2021, 1, 1, 23, 0, 0, 0, time.Local)) {
+		t.Errorf("expect 23:00 in the window [%v, %v)", policy.WindowStartHour, policy.WindowEndHour)
+	}
+	reqURL = fmt.Sprintf("%v%v?enable=false", hostAddr, proto.AdminSetAutoRebalancePolicy)
+	process(reqURL, t)
+}
+
+func TestSetHeartbeatVerbosity(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	defer mc.AdminAPI().SetHeartbeatVerbosity(proto.HeartbeatVerbosityMinimal)
+	// wait for the heartbeat of mds1 to report the disks or not
+	waitDiskReports := func(expectReported bool) bool {
+		server.cluster.checkDataNodeHeartbeat()
+		for i := 0; i < 50; i++ {
+			node, err := mc.NodeAPI().GetDataNode(mds1Addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (len(node.DiskReports) > 0) == expectReported {
+				return true
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		return false
+	}
+	if err := mc.AdminAPI().SetHeartbeatVerbosity(proto.HeartbeatVerbosityVerbose); err != nil {
+		t.Error(err)
+		return
+	}
+	cv, err := mc.AdminAPI().GetCluster()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if cv.HeartbeatVerbosity != proto.HeartbeatVerbosityVerbose {
+		t.Errorf("expect the verbosity %v, but got %v", proto.HeartbeatVerbosityVerbose, cv.HeartbeatVerbosity)
+	}
+	if !waitDiskReports(true) {
+		t.Errorf("expect the disks of %v reported by the verbose heartbeat", mds1Addr)
+	}
+	if err = mc.AdminAPI().SetHeartbeatVerbosity(proto.HeartbeatVerbosityMinimal); err != nil {
+		t.Error(err)
+		return
+	}
+	if !waitDiskReports(false) {
+		t.Errorf("expect no disk of %v reported by the minimal heartbeat", mds1Addr)
+	}
+	if err = mc.AdminAPI().SetHeartbeatVerbosity("debug"); err == nil {
+		t.Errorf("expect the unknown verbosity rejected")
+	}
+}
+
+func TestSetAutoDecommission(t *testing.T) {
+	resp, err := http.Get(fmt.Sprintf("%v%v?enable=true&timeout=1", hostAddr, proto.AdminSetAutoDecommission))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the timeout shorter than the heartbeat timeout rejected, reply[%v] err[%v]", reply, err)
+	}
+	reqURL := fmt.Sprintf("%v%v?enable=true&timeout=600", hostAddr, proto.AdminSetAutoDecommission)
+	fmt.Println(reqURL)
+	if process(reqURL, t) == nil {
+		return
+	}
+	defer process(fmt.Sprintf("%v%v?enable=false", hostAddr, proto.AdminSetAutoDecommission), t)
+	if policy := server.cluster.autoDecommissioner.getPolicy(); !policy.Enable || policy.TimeoutSec != 600 {
+		t.Errorf("unexpected policy %v", *policy)
+		return
+	}
+
+	addr := "127.0.0.1:9999"
+	dataNode := newDataNode(addr, "", server.cluster.Name)
+	dataNode.ReportTime = time.Now().Add(-700 * time.Second)
+	server.cluster.dataNodes.Store(addr, dataNode)
+	defer server.cluster.dataNodes.Delete(addr)
+
+	// too many inactive data nodes, as in a network partition
+	if process(fmt.Sprintf("%v%v?enable=true&timeout=600&maxInactiveRatio=0.01", hostAddr, proto.AdminSetAutoDecommission), t) == nil {
+		return
+	}
+	server.cluster.checkAutoDecommission(time.Now().Add(-time.Hour))
+	if events := server.cluster.getRecentAuditEvents(1, "autoDecommissionDataNode"); len(events) != 0 {
+		t.Errorf("expect nothing decommissioned with too many inactive data nodes, but got %v", events)
+	}
+
+	// another data node being decommissioned takes the only slot
+	if process(fmt.Sprintf("%v%v?enable=true&timeout=600&maxNodes=1", hostAddr, proto.AdminSetAutoDecommission), t) == nil {
+		return
+	}
+	busyAddr := "127.0.0.1:9998"
+	server.cluster.nodeDecommissions.Store(busyAddr, &nodeDecommission{})
+	server.cluster.checkAutoDecommission(time.Now().Add(-time.Hour))
+	server.cluster.nodeDecommissions.Delete(busyAddr)
+	if events := server.cluster.getRecentAuditEvents(1, "autoDecommissionDataNode"); len(events) != 0 {
+		t.Errorf("expect nothing decommissioned beyond maxNodes, but got %v", events)
+	}
+
+	server.cluster.checkAutoDecommission(time.Now().Add(-time.Hour))
+	events := server.cluster.getRecentAuditEvents(1, "autoDecommissionDataNode")
+	if len(events) != 1 || events[0].Target != addr || events[0].Result != auditResultSuccess {
+		t.Errorf("expect data node %v decommissioned automatically, but got %v", addr, events)
+	}
+	for _, event := range server.cluster.getRecentAuditEvents(100, "autoDecommissionDataNode") {
+		if event.Target != addr {
+			t.Errorf("expect the active data node %v not decommissioned", event.Target)
+		}
+	}
+}
+
+func TestGetDataPartitionsGroupByStatus(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?name=%v&groupByStatus=true", hostAddr, proto.ClientDataPartitions, commonVol.Name)
+	fmt.Println(reqURL)
+	reply := process(reqURL, t)
+	if reply == nil {
+		return
+	}
+	grouped := reply.Data.(map[string]interface{})
+	count := 0
+	for _, status := range []string{"readWrite", "readOnly", "unavailable"} {
+		count += len(grouped[status].([]interface{}))
+	}
+	if count != len(commonVol.dataPartitions.partitions) {
+		t.Errorf("expect %v data partitions, but got %v", len(commonVol.dataPartitions.partitions), count)
+	}
+}
+
+func TestGetDataPartitionsPage(t *testing.T) {
+	total := len(commonVol.dataPartitions.partitions)
+	for _, c := range []struct {
+		start, count, expect int
+	}{
+		{0, 1, 1},
+		{total - 1, 10, 1},
+		{total + 10, 10, 0},
+	} {
+		reqURL := fmt.Sprintf("%v%v?name=%v&start=%v&count=%v", hostAddr, proto.ClientDataPartitions, commonVol.Name, c.start, c.count)
+		fmt.Println(reqURL)
+		reply := process(reqURL, t)
+		if reply == nil {
+			return
+		}
+		page := reply.Data.(map[string]interface{})
+		if int(page["Total"].(float64)) != total || len(page["DataPartitions"].([]interface{})) != c.expect {
+			t.Errorf("expect %v of %v data partitions from %v, but got %v", c.expect, total, c.start, page)
+		}
+	}
+}
+
+func TestGetAllVols(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?keyword=%v", hostAddr, proto.AdminGetAllVols, commonVol.Name)
+	fmt.Println(reqURL)
+	reply := process(reqURL, t)
+	if reply == nil {
+		return
+	}
+	vols := reply.Data.([]interface{})
+	if len(vols) == 0 || vols[0].(map[string]interface{})["Name"] != commonVol.Name {
+		t.Errorf("expect vol %v listed, but got %v", commonVol.Name, vols)
+	}
+	reply = process(fmt.Sprintf("%v&owner=%v", reqURL, "nobody"), t)
+	if vols = reply.Data.([]interface{}); len(vols) != 0 {
+		t.Errorf("expect no vol owned by nobody, but got %v", vols)
+	}
+}
+
+func TestListVolsByOwner(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	volsInfo, err := mc.AdminAPI().ListVolsByOwner("", commonVol.Owner)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	names := make([]string, 0, len(volsInfo))
+	for _, info := range volsInfo {
+		if info.Owner != commonVol.Owner {
+			t.Errorf("expect vols owned by %v, but got %v", commonVol.Owner, info)
+		}
+		names = append(names, info.Name)
+	}
+	if !contains(names, commonVol.Name) {
+		t.Errorf("expect vol %v listed, but got %v", commonVol.Name, names)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	name := "test_vol_events"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, hostAddr+proto.AdminEvents, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("expect content type text/event-stream, but got %v", contentType)
+		return
+	}
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfs&zoneName=%v", hostAddr, proto.AdminCreateVol, name, testZone2)
+	if reply := process(reqURL, t); reply == nil {
+		return
+	}
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Errorf("expect event %v of %v, but got err %v", proto.EventVolCreated, name, err)
+			return
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		event := &proto.ClusterEvent{}
+		if err = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), event); err != nil {
+			t.Error(err)
+			return
+		}
+		if event.Type == proto.EventVolCreated && event.Target == name {
+			break
+		}
+	}
+	cancel()
+	for i := 0; i < 50; i++ {
+		server.cluster.events.RLock()
+		count := len(server.cluster.events.subscribers)
+		server.cluster.events.RUnlock()
+		if count == 0 {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Errorf("expect the subscriber removed after the client went away")
+}
+
+func TestBatchSetNodeTags(t *testing.T) {
+	dataNode, err := server.cluster.dataNode(mds1Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	metaNode, err := server.cluster.metaNode(mms1Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	oldDataTags, oldMetaTags := dataNode.Tags, metaNode.Tags
+	defer func() {
+		server.cluster.setNodeTags(mds1Addr, oldDataTags)
+		server.cluster.setNodeTags(mms1Addr, oldMetaTags)
+	}()
+	unknownAddr := "127.0.0.1:9999"
+	items := []*proto.NodeTags{
+		{Addr: mds1Addr, Tags: []string{"rack1"}},
+		{Addr: unknownAddr, Tags: []string{"rack2"}},
+		{Addr: mms1Addr, Tags: []string{"rack3"}},
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	results, err := mc.AdminAPI().BatchSetNodeTags(items)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(results) != len(items) {
+		t.Errorf("expect a result for each of the %v nodes, but got %v", len(items), len(results))
+		return
+	}
+	// the unknown node fails alone, the nodes before and after it are set
+	for i, result := range results {
+		if result.Addr != items[i].Addr {
+			t.Errorf("expect the result of %v at %v, but got %v", items[i].Addr, i, *result)
+		}
+		if expectSuccess := items[i].Addr != unknownAddr; result.Success != expectSuccess {
+			t.Errorf("expect the success of %v to be %v, but got %v", items[i].Addr, expectSuccess, *result)
+		}
+	}
+	if !strings.Contains(results[1].Msg, unknownAddr) {
+		t.Errorf("expect the failure of %v reported, but got %v", unknownAddr, results[1].Msg)
+	}
+	if len(dataNode.Tags) != 1 || dataNode.Tags[0] != "rack1" {
+		t.Errorf("expect the tags [rack1] of data node %v, but got %v", mds1Addr, dataNode.Tags)
+	}
+	if len(metaNode.Tags) != 1 || metaNode.Tags[0] != "rack3" {
+		t.Errorf("expect the tags [rack3] of meta node %v, but got %v", mms1Addr, metaNode.Tags)
+	}
+}
+
+func TestVolTags(t *testing.T) {
+	name := "test_vol_tags"
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfs&zoneName=%v&tags=env=prod,team=search",
+		hostAddr, proto.AdminCreateVol, name, testZone2)
+	fmt.Println(reqURL)
+	if reply := process(reqURL, t); reply == nil {
+		return
+	}
+	listVols := func(tag string) (names []string) {
+		reply := process(fmt.Sprintf("%v%v?keywords=%v&tag=%v", hostAddr, proto.AdminListVols, name, tag), t)
+		if reply == nil {
+			return
+		}
+		for _, vol := range reply.Data.([]interface{}) {
+			names = append(names, vol.(map[string]interface{})["Name"].(string))
+		}
+		return
+	}
+	if names := listVols("env=prod"); !contains(names, name) {
+		t.Errorf("expect vol %v listed by tag env=prod, but got %v", name, names)
+	}
+	if names := listVols("team"); !contains(names, name) {
+		t.Errorf("expect vol %v listed by tag team, but got %v", name, names)
+	}
+	if names := listVols("env=dev"); contains(names, name) {
+		t.Errorf("expect vol %v not listed by tag env=dev, but got %v", name, names)
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err := mc.AdminAPI().SetVolumeTags(name, buildAuthKey("cfs"), map[string]string{"env": "dev"}); err != nil {
+		t.Error(err)
+		return
+	}
+	vol, err := server.cluster.getVol(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(vol.tags) != 1 || vol.tags["env"] != "dev" {
+		t.Errorf("expect tags env=dev, but got %v", vol.tags)
+	}
+	if names := listVols("env=dev"); !contains(names, name) {
+		t.Errorf("expect vol %v listed by tag env=dev, but got %v", name, names)
+	}
+	reqURL = fmt.Sprintf("%v%v?name=%v&authKey=%v&tags=%v=x", hostAddr, proto.AdminUpdateVol, name, buildAuthKey("cfs"),
+		strings.Repeat("k", maxVolTagKeyLen+1))
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	reply := &proto.HTTPReply{}
+	if err = json.NewDecoder(resp.Body).Decode(reply); err != nil {
+		t.Error(err)
+		return
+	}
+	if reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the too long tag key rejected, but got %v", reply)
+	}
+}
+
+func TestVolQos(t *testing.T) {
+	name := "test_vol_qos"
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfs&zoneName=%v&bandwidthLimit=-1",
+		hostAddr, proto.AdminCreateVol, name, testZone2)
+	reply := &proto.HTTPReply{}
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the negative bandwidthLimit rejected, but got reply[%v] err[%v]", reply, err)
+	}
+	reqURL = fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfs&zoneName=%v&bandwidthLimit=%v&iopsLimit=1000",
+		hostAddr, proto.AdminCreateVol, name, testZone2, 100<<20)
+	if reply = process(reqURL, t); reply == nil {
+		return
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	checkQos := func(bandwidthLimit, iopsLimit uint64) {
+		view, err := mc.AdminAPI().GetVolumeSimpleInfo(name)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if view.BandwidthLimit != bandwidthLimit || view.IopsLimit != iopsLimit {
+			t.Errorf("expect bandwidthLimit[%v] iopsLimit[%v], but got [%v] [%v]",
+				bandwidthLimit, iopsLimit, view.BandwidthLimit, view.IopsLimit)
+		}
+	}
+	checkQos(100<<20, 1000)
+	// the limits not given are kept by the other updates
+	if err = mc.AdminAPI().SetVolumeTags(name, buildAuthKey("cfs"), map[string]string{"tier": "gold"}); err != nil {
+		t.Error(err)
+		return
+	}
+	checkQos(100<<20, 1000)
+	if err = mc.AdminAPI().SetVolumeQos(name, buildAuthKey("cfs"), 0, 500); err != nil {
+		t.Error(err)
+		return
+	}
+	checkQos(0, 500)
+	reqURL = fmt.Sprintf("%v%v?name=%v&authKey=%v&iopsLimit=-5", hostAddr, proto.AdminUpdateVol, name, buildAuthKey("cfs"))
+	if resp, err = http.Get(reqURL); err != nil {
+		t.Error(err)
+		return
+	}
+	reply = &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the negative iopsLimit rejected, but got reply[%v] err[%v]", reply, err)
+	}
+	checkQos(0, 500)
+	// the limits are carried by the exported spec to the imported volume
+	spec, err := mc.AdminAPI().ExportVolume(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if spec.BandwidthLimit != 0 || spec.IopsLimit != 500 {
+		t.Errorf("expect the spec of vol[%v] with bandwidthLimit[0] iopsLimit[500], but got [%v] [%v]",
+			name, spec.BandwidthLimit, spec.IopsLimit)
+	}
+}
+
+func TestExportImportVol(t *testing.T) {
+	name, copyName := "test_vol_export", "test_vol_import"
+	createVol(name, t)
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err := mc.AdminAPI().SetVolReadOnly(name, true); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := mc.AdminAPI().SetVolumeTags(name, buildAuthKey("cfs"), map[string]string{"env": "prod"}); err != nil {
+		t.Error(err)
+		return
+	}
+	spec, err := mc.AdminAPI().ExportVolume(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !spec.ReadOnly || spec.Tags["env"] != "prod" {
+		t.Errorf("expect the read only flag and the tags of vol %v exported, but got %v", name, *spec)
+	}
+	spec.Name = copyName
+	spec.MpReplicaNum = defaultReplicaNum + 2
+	if err = mc.AdminAPI().ImportVolume(spec); err == nil {
+		t.Errorf("expect the spec with MpReplicaNum[%v] rejected", spec.MpReplicaNum)
+	}
+	spec.MpReplicaNum = defaultReplicaNum
+	if err = mc.AdminAPI().ImportVolume(spec); err != nil {
+		t.Error(err)
+		return
+	}
+	imported, err := mc.AdminAPI().ExportVolume(copyName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expect, _ := json.Marshal(spec)
+	got, _ := json.Marshal(imported)
+	if string(expect) != string(got) {
+		t.Errorf("expect the imported vol with the spec %s, but got %s", expect, got)
+	}
+	if vol, err := server.cluster.getVol(copyName); err != nil || !vol.isReadOnly() || vol.tags["env"] != "prod" {
+		t.Errorf("expect the imported vol %v to be read only and tagged, err[%v]", copyName, err)
+	}
+}
+
+func TestVolHistory(t *testing.T) {
+	name := "test_vol_history"
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfs&zoneName=%v", hostAddr, proto.AdminCreateVol, name, testZone2)
+	if reply := process(reqURL, t); reply == nil {
+		return
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if err := mc.AdminAPI().VolExpand(name, 200, buildAuthKey("cfs")); err != nil {
+		t.Error(err)
+		return
+	}
+	// the updates keeping the capacity are not recorded
+	if err := mc.AdminAPI().SetVolumeTags(name, buildAuthKey("cfs"), map[string]string{"env": "test"}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := mc.AdminAPI().VolShrink(name, 150, buildAuthKey("cfs")); err != nil {
+		t.Error(err)
+		return
+	}
+	history, err := mc.AdminAPI().GetVolumeHistory(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(history) != 2 {
+		t.Errorf("expect 2 capacity changes, but got %v", len(history))
+		return
+	}
+	if history[0].OldCapacity != 100 || history[0].NewCapacity != 200 ||
+		history[1].OldCapacity != 200 || history[1].NewCapacity != 150 {
+		t.Errorf("expect the changes 100->200->150, but got %v->%v, %v->%v", history[0].OldCapacity,
+			history[0].NewCapacity, history[1].OldCapacity, history[1].NewCapacity)
+	}
+	if history[0].Caller == "" || history[0].Time == 0 {
+		t.Errorf("expect the caller and the time recorded, but got %v", history[0])
+	}
+
+	vol, err := server.cluster.getVol(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for i := 0; i < maxVolCapacityHistory; i++ {
+		vol.recordCapacityChange(uint64(i), uint64(i+1), "test")
+	}
+	if history = vol.getCapacityHistory(); len(history) != maxVolCapacityHistory {
+		t.Errorf("expect %v capacity changes kept, but got %v", maxVolCapacityHistory, len(history))
+	} else if last := history[len(history)-1]; last.NewCapacity != maxVolCapacityHistory {
+		t.Errorf("expect the latest change kept, but got %v", last)
+	}
+}
+
+func TestGetNodeSetStats(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	stats, err := mc.AdminAPI().GetNodeSetStats()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var zone1DataNodes, zone1MetaNodes int
+	for _, stat := range stats {
+		members, err := server.cluster.getNodeSetMembers(stat.ID)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if stat.DataNodeCount != len(members.DataNodes) || stat.MetaNodeCount != len(members.MetaNodes) {
+			t.Errorf("expect %v data nodes and %v meta nodes in nodeSet[%v], but got %v and %v", len(members.DataNodes),
+				len(members.MetaNodes), stat.ID, stat.DataNodeCount, stat.MetaNodeCount)
+		}
+		var used, total uint64
+		for _, addr := range members.DataNodes {
+			dataNode, err := server.cluster.dataNode(addr)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			used += dataNode.Used
+			total += dataNode.Total
+		}
+		if total != 0 && stat.UsedRatio != float64(used)/float64(total) {
+			t.Errorf("expect used ratio %v of nodeSet[%v], but got %v", float64(used)/float64(total), stat.ID, stat.UsedRatio)
+		}
+		if stat.ZoneName == testZone1 {
+			zone1DataNodes += stat.DataNodeCount
+			zone1MetaNodes += stat.MetaNodeCount
+		}
+	}
+	if zone1DataNodes != 2 || zone1MetaNodes != 2 {
+		t.Errorf("expect 2 data nodes and 2 meta nodes in %v, but got %v and %v", testZone1, zone1DataNodes, zone1MetaNodes)
+	}
+}
+
+func TestSimulateNodeSetFailure(t *testing.T) {
+	dataNode, err := server.cluster.dataNode(mds1Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reqURL := fmt.Sprintf("%v%v?id=%v", hostAddr, proto.AdminSimulateNodeSetFailure, dataNode.NodeSetID)
+	fmt.Println(reqURL)
+	reply := process(reqURL, t)
+	if reply == nil {
+		return
+	}
+	result := reply.Data.(map[string]interface{})
+	if !contains(toStrings(result["DataNodes"]), mds1Addr) {
+		t.Errorf("expect data node %v in node set %v, but got %v", mds1Addr, dataNode.NodeSetID, result["DataNodes"])
+	}
+}
+
+func TestMoveNodeToNodeSet(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	created, err := mc.AdminAPI().CreateNodeSet(testZone2, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if created.ZoneName != testZone2 || created.Capacity != server.config.nodeSetCapacity || len(created.DataNodes) != 0 {
+		t.Errorf("expect an empty node set in %v, but got %v", testZone2, created)
+	}
+	metaNode, err := server.cluster.metaNode(mms5Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	srcID := metaNode.NodeSetID
+	if err = mc.AdminAPI().MoveNodeToNodeSet(mms5Addr, topologyTypeMeta, created.ID); err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		if err := mc.AdminAPI().MoveNodeToNodeSet(mms5Addr, topologyTypeMeta, srcID); err != nil {
+			t.Error(err)
+		}
+	}()
+	members, err := mc.AdminAPI().GetNodeSet(created.ID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !contains(members.MetaNodes, mms5Addr) || metaNode.NodeSetID != created.ID {
+		t.Errorf("expect %v moved into node set %v, but got %v", mms5Addr, created.ID, members)
+	}
+	if members, err = mc.AdminAPI().GetNodeSet(srcID); err != nil {
+		t.Error(err)
+		return
+	}
+	if contains(members.MetaNodes, mms5Addr) {
+		t.Errorf("expect %v removed from node set %v, but got %v", mms5Addr, srcID, members)
+	}
+	// a node already in the set, a node of another zone and an unknown set are refused
+	if err = mc.AdminAPI().MoveNodeToNodeSet(mms5Addr, topologyTypeMeta, created.ID); err == nil {
+		t.Errorf("expect moving %v into its own node set to fail", mms5Addr)
+	}
+	if err = mc.AdminAPI().MoveNodeToNodeSet(mds1Addr, topologyTypeData, created.ID); err == nil {
+		t.Errorf("expect moving %v of %v into a node set of %v to fail", mds1Addr, testZone1, testZone2)
+	}
+	if err = mc.AdminAPI().MoveNodeToNodeSet(mms5Addr, topologyTypeMeta, created.ID+1000); err == nil {
+		t.Errorf("expect moving %v into an unknown node set to fail", mms5Addr)
+	}
+}
+
+func toStrings(value interface{}) (strs []string) {
+	for _, v := range value.([]interface{}) {
+		strs = append(strs, v.(string))
+	}
+	return
+}
+
+func TestCreateVol(t *testing.T) {
+	name := "test_create_vol"
+	reqURL := fmt.Sprintf("%v%v?name=%v&replicas=3&type=extent&capacity=100&owner=cfstest&zoneName=%v", hostAddr, proto.AdminCreateVol, name, testZone2)
+	fmt.Println(reqURL)
+	process(reqURL, t)
+	userInfo, err := server.user.getUserInfo("cfstest")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !contains(userInfo.Policy.OwnVols, name) {
+		t.Errorf("expect vol %v in own vols, but is not", name)
+		return
+	}
+}
+
+func TestCreateVolFailIfExists(t *testing.T) {
+	name := "test_create_vol_retry"
+	reqURL := fmt.Sprintf("%v%v?name=%v&replicaNum=3&capacity=100&owner=cfstest&zoneName=%v", hostAddr, proto.AdminCreateVol, name, testZone2)
+	process(reqURL, t)
+	// retrying with the same parameters succeeds
+	process(reqURL, t)
+	for _, retryURL := range []string{
+		reqURL + "&failIfExists=true",
+		strings.Replace(reqURL, "capacity=100", "capacity=200", 1),
+	} {
+		resp, err := http.Get(retryURL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil || reply.Code != proto.ErrCodeDuplicateVol {
+			t.Errorf("expect %v to be rejected as duplicate, reply[%v] err[%v]", retryURL, reply, err)
+		}
+	}
+}
+
+func TestCreateVolStrictParamCheck(t *testing.T) {
+	name := "test_create_vol_strict"
+	reqURL := fmt.Sprintf("%v%v?name=%v&replicaNun=3&capacity=100&owner=cfstest&zoneName=%v", hostAddr, proto.AdminCreateVol, name, testZone2)
+	fmt.Println(reqURL)
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	req.Header.Set(proto.HeadStrictParamCheck, "true")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	if err = json.Unmarshal(body, reply); err != nil {
+		t.Error(err)
+		return
+	}
+	if reply.Code != proto.ErrCodeParamError || !strings.Contains(reply.Msg, "replicaNun") {
+		t.Errorf("expect the unknown parameter replicaNun to be rejected, reply[%v]", string(body))
+		return
+	}
+	if _, err = server.cluster.getVol(name); err == nil {
+		t.Errorf("vol[%v] should not be created", name)
+	}
+}
+
+func TestCreateVolWithZone(t *testing.T) {
+	name := "test_zone_vol"
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfstest&zone=%v", hostAddr, proto.AdminCreateVol, name, testZone2)
+	fmt.Println(reqURL)
+	process(reqURL, t)
+	vol, err := server.cluster.getVol(name)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if vol.zoneName != testZone2 {
+		t.Errorf("expect zone %v, but got %v", testZone2, vol.zoneName)
+	}
+	for _, dp := range vol.dataPartitions.partitions {
+		for _, host := range dp.Hosts {
+			dataNode, err := server.cluster.dataNode(host)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if dataNode.ZoneName != testZone2 {
+				t.Errorf("data partition[%v] has a replica on %v in zone %v", dp.PartitionID, host, dataNode.ZoneName)
+			}
+		}
+	}
 }
 
-func TestGetIpAndClusterName(t *testing.T) {
-	reqURL := fmt.Sprintf("%v%v", hostAddr, proto.AdminGetIP)
-	fmt.Println(reqURL)
-	process(reqURL, t)
+func TestCreateVolByJSON(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v", hostAddr, proto.AdminCreateVol)
+	for _, req := range []*proto.CreateVolRequest{
+		{Name: "test_json_vol", Owner: "cfstest", Capacity: 100, ReplicaNum: 3, ZoneName: testZone2},
+		{Name: "test json vol", Owner: "cfstest", Capacity: 100},
+		{Name: "test_json_vol2", Owner: "cfstest", Capacity: 100, ReplicaNum: 4},
+	} {
+		data, _ := json.Marshal(req)
+		resp, err := http.Post(reqURL, "application/json", bytes.NewReader(data))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, getErr := server.cluster.getVol(req.Name)
+		if req.Name == "test_json_vol" {
+			if reply.Code != proto.ErrCodeSuccess || getErr != nil {
+				t.Errorf("expect vol[%v] to be created, reply[%v] err[%v]", req.Name, reply, getErr)
+			}
+			continue
+		}
+		if reply.Code != proto.ErrCodeParamError || getErr == nil {
+			t.Errorf("expect vol[%v] to be rejected, reply[%v]", req.Name, reply)
+		}
+	}
 }
 
-func process(reqURL string, t *testing.T) (reply *proto.HTTPReply) {
-	resp, err := http.Get(reqURL)
+func TestBatchCreateVol(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	results, err := mc.AdminAPI().BatchCreateVolumes([]*proto.CreateVolRequest{
+		{Name: "test_batch_vol1", Owner: "cfstest", Capacity: 100, ZoneName: testZone2},
+		{Name: "test batch vol", Owner: "cfstest", Capacity: 100},
+		{Name: "test_batch_vol2", Owner: "cfstest", Capacity: 100, ReplicaNum: 4},
+		{Name: "test_batch_vol3", Owner: "cfstest", Capacity: 100, ZoneName: testZone2},
+	})
 	if err != nil {
-		t.Errorf("err is %v", err)
+		t.Error(err)
 		return
 	}
-	fmt.Println(resp.StatusCode)
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		t.Errorf("err is %v", err)
+	if len(results) != 4 {
+		t.Errorf("expect 4 results, but got %v", len(results))
 		return
 	}
-	fmt.Println(string(body))
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("status code[%v]", resp.StatusCode)
-		return
+	for i, result := range results {
+		_, getErr := server.cluster.getVol(result.Name)
+		if i == 0 || i == 3 {
+			if !result.Success || result.DataPartitionCount == 0 || getErr != nil {
+				t.Errorf("expect vol[%v] to be created, result[%v] err[%v]", result.Name, result, getErr)
+			}
+			continue
+		}
+		if result.Success || result.Msg == "" || getErr == nil {
+			t.Errorf("expect vol[%v] to be rejected, result[%v]", result.Name, result)
+		}
 	}
-	reply = &proto.HTTPReply{}
-	if err = json.Unmarshal(body, reply); err != nil {
+}
+
+func TestCreateVolStatusCreated(t *testing.T) {
+	name := "test_vol_created"
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfs&zoneName=%v", hostAddr, proto.AdminCreateVol, name, testZone2)
+	for _, expect := range []int{http.StatusCreated, http.StatusOK} {
+		resp, err := http.Get(reqURL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expect {
+			t.Errorf("expect status code %v of %v, but got %v", expect, reqURL, resp.StatusCode)
+		}
+		if location := resp.Header.Get("Location"); expect == http.StatusCreated && location != proto.AdminGetVol+"?name="+name {
+			t.Errorf("expect the location of vol %v, but got %v", name, location)
+		}
+	}
+	reqURL = fmt.Sprintf("%v%v?count=1&name=%v", hostAddr, proto.AdminCreateDataPartition, name)
+	resp, err := http.Get(reqURL)
+	if err != nil {
 		t.Error(err)
 		return
 	}
-	if reply.Code != 0 {
-		t.Errorf("failed,msg[%v],data[%v]", reply.Msg, reply.Data)
-		return
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || !strings.HasPrefix(resp.Header.Get("Location"), proto.AdminGetDataPartition+"?id=") {
+		t.Errorf("expect status code %v with the location of the data partition, but got %v %v",
+			http.StatusCreated, resp.StatusCode, resp.Header.Get("Location"))
 	}
-	return
 }
 
-func TestDisk(t *testing.T) {
-	addr := mds5Addr
-	disk := "/cfs"
-	decommissionDisk(addr, disk, t)
+func TestCreateVolReplicaNum(t *testing.T) {
+	name := "test_replica_num_vol"
+	for _, replicaNum := range []int{1, 4, 5} {
+		reqURL := fmt.Sprintf("%v%v?name=%v&replicaNum=%v&capacity=100&owner=cfstest", hostAddr, proto.AdminCreateVol, name, replicaNum)
+		fmt.Println(reqURL)
+		resp, err := http.Get(reqURL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil || reply.Code != proto.ErrCodeParamError || !strings.Contains(reply.Msg, replicaNumKey) {
+			t.Errorf("expect replicaNum %v to be rejected, reply[%v] err[%v]", replicaNum, reply, err)
+		}
+	}
+	if _, err := server.cluster.getVol(name); err == nil {
+		t.Errorf("vol[%v] should not be created", name)
+	}
 }
 
-func decommissionDisk(addr, path string, t *testing.T) {
-	reqURL := fmt.Sprintf("%v%v?addr=%v&disk=%v",
-		hostAddr, proto.DecommissionDisk, addr, path)
+func TestVolCapacityBounds(t *testing.T) {
+	server.config.minVolCapacity, server.config.maxVolCapacity = 10, 5000
+	defer func() { server.config.minVolCapacity, server.config.maxVolCapacity = 0, 0 }()
+	expectRejected := func(reqURL string) {
+		fmt.Println(reqURL)
+		resp, err := http.Get(reqURL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil || reply.Code != proto.ErrCodeParamError || !strings.Contains(reply.Msg, volCapacityKey) {
+			t.Errorf("expect the capacity rejected, reply[%v] err[%v]", reply, err)
+		}
+	}
+	name := "test_capacity_bounds_vol"
+	for _, capacity := range []int{5, 6000} {
+		expectRejected(fmt.Sprintf("%v%v?name=%v&capacity=%v&owner=cfstest", hostAddr, proto.AdminCreateVol, name, capacity))
+	}
+	if _, err := server.cluster.getVol(name); err == nil {
+		t.Errorf("vol[%v] should not be created", name)
+	}
+	expectRejected(fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v",
+		hostAddr, proto.AdminUpdateVol, commonVol.Name, 6000, buildAuthKey("cfs")))
+	expectRejected(fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v",
+		hostAddr, proto.AdminVolExpand, commonVol.Name, 6000, buildAuthKey("cfs")))
+	expectRejected(fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v",
+		hostAddr, proto.AdminVolShrink, commonVol.Name, 5, buildAuthKey("cfs")))
+}
+
+func TestSendErrReply(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?capacity=100&owner=cfstest", hostAddr, proto.AdminCreateVol)
 	fmt.Println(reqURL)
 	resp, err := http.Get(reqURL)
 	if err != nil {
-		t.Errorf("err is %v", err)
+		t.Error(err)
 		return
 	}
-	fmt.Println(resp.StatusCode)
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected status code[%v] content type[%v]", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		t.Errorf("err is %v", err)
-		return
-	}
-	fmt.Println(string(body))
-	if resp.StatusCode != http.StatusOK {
-		t.Errorf("status code[%v]", resp.StatusCode)
+		t.Error(err)
 		return
 	}
 	reply := &proto.HTTPReply{}
@@ -287,97 +3042,76 @@ func decommissionDisk(addr, path string, t *testing.T) {
 		t.Error(err)
 		return
 	}
-	server.cluster.checkDataNodeHeartbeat()
-	time.Sleep(5 * time.Second)
-	server.cluster.checkDiskRecoveryProgress()
+	if reply.Code != proto.ErrCodeParamError || reply.Op != "createVol" {
+		t.Errorf("expect the param error of createVol, reply[%v]", string(body))
+	}
 }
 
-func TestMarkDeleteVol(t *testing.T) {
-	name := "delVol"
-	createVol(name, t)
-	reqURL := fmt.Sprintf("%v%v?name=%v&authKey=%v", hostAddr, proto.AdminDeleteVol, name, buildAuthKey("cfs"))
-	process(reqURL, t)
-	userInfo, err := server.user.getUserInfo("cfs")
+func TestAdminToken(t *testing.T) {
+	server.config.adminToken = "test_token"
+	defer func() { server.config.adminToken = "" }()
+	reqURL := fmt.Sprintf("%v%v?enable=false", hostAddr, proto.AdminClusterFreeze)
+	fmt.Println(reqURL)
+	resp, err := http.Get(reqURL)
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	if contains(userInfo.Policy.OwnVols, name) {
-		t.Errorf("expect no vol %v in own vols, but is exist", name)
-		return
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expect status code %v without the token, but got %v", http.StatusUnauthorized, resp.StatusCode)
 	}
-}
-
-func TestSetVolCapacity(t *testing.T) {
-	setVolCapacity(600, proto.AdminVolExpand, t)
-	setVolCapacity(300, proto.AdminVolShrink, t)
-}
-
-func TestUpdateVol(t *testing.T) {
-	capacity := 2000
-	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v",
-		hostAddr, proto.AdminUpdateVol, commonVol.Name, capacity, buildAuthKey("cfs"))
-	process(reqURL, t)
-	vol, err := server.cluster.getVol(commonVolName)
+	process(fmt.Sprintf("%v&token=%v", reqURL, server.config.adminToken), t)
+	process(fmt.Sprintf("%v%v", hostAddr, proto.AdminGetCluster), t)
+	// the token in the form body
+	resp, err = http.Post(reqURL, "application/x-www-form-urlencoded", strings.NewReader("token="+server.config.adminToken))
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	if vol.FollowerRead != false {
-		t.Errorf("expect FollowerRead is false, but is %v", vol.FollowerRead)
-		return
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expect the token of the form body accepted, but got status code %v", resp.StatusCode)
 	}
-
-	reqURL = fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v&followerRead=true&zoneName=%v",
-		hostAddr, proto.AdminUpdateVol, commonVol.Name, capacity, buildAuthKey("cfs"), commonVol.zoneName)
-	process(reqURL, t)
-	if vol.FollowerRead != true {
-		t.Errorf("expect FollowerRead is true, but is %v", vol.FollowerRead)
+	// the token is a known parameter of createVol under the strict check
+	name := "test_token_vol"
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v%v?name=%v&capacity=100&owner=cfs&zoneName=%v&token=%v",
+		hostAddr, proto.AdminCreateVol, name, testZone2, server.config.adminToken), nil)
+	if err != nil {
+		t.Error(err)
 		return
 	}
-
-}
-
-func setVolCapacity(capacity uint64, url string, t *testing.T) {
-	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=%v&authKey=%v",
-		hostAddr, url, commonVol.Name, capacity, buildAuthKey("cfs"))
-	process(reqURL, t)
-	vol, err := server.cluster.getVol(commonVolName)
-	if err != nil {
+	req.Header.Set(proto.HeadStrictParamCheck, "true")
+	if resp, err = http.DefaultClient.Do(req); err != nil {
 		t.Error(err)
 		return
 	}
-	if vol.Capacity != capacity {
-		t.Errorf("expect capacity is %v, but is %v", capacity, vol.Capacity)
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeSuccess {
+		t.Errorf("expect vol[%v] created with the token under the strict check, but got %v, err %v", name, reply, err)
 		return
 	}
+	server.config.adminToken = ""
+	markDeleteVol(name, t)
 }
 
-func buildAuthKey(owner string) string {
-	h := md5.New()
-	h.Write([]byte(owner))
-	cipherStr := h.Sum(nil)
-	return hex.EncodeToString(cipherStr)
-}
-
-func TestGetVolSimpleInfo(t *testing.T) {
-	reqURL := fmt.Sprintf("%v%v?name=%v", hostAddr, proto.AdminGetVol, commonVol.Name)
-	process(reqURL, t)
-}
-
-func TestCreateVol(t *testing.T) {
-	name := "test_create_vol"
-	reqURL := fmt.Sprintf("%v%v?name=%v&replicas=3&type=extent&capacity=100&owner=cfstest&zoneName=%v", hostAddr, proto.AdminCreateVol, name, testZone2)
-	fmt.Println(reqURL)
-	process(reqURL, t)
-	userInfo, err := server.user.getUserInfo("cfstest")
+func TestRedirectToLeader(t *testing.T) {
+	reqURL := fmt.Sprintf("%v%v?name=%v&capacity=100", hostAddr, proto.AdminUpdateVol, commonVol.Name)
+	r, err := http.NewRequest(http.MethodGet, reqURL, nil)
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	if !contains(userInfo.Policy.OwnVols, name) {
-		t.Errorf("expect vol %v in own vols, but is not", name)
-		return
+	w := httptest.NewRecorder()
+	server.redirectToLeader(w, r)
+	if w.Code != http.StatusTemporaryRedirect {
+		t.Errorf("expect status code %v, but got %v", http.StatusTemporaryRedirect, w.Code)
+	}
+	expect := fmt.Sprintf("http://%v%v", server.leaderInfo.addr, r.URL.RequestURI())
+	if location := w.Header().Get("Location"); location != expect {
+		t.Errorf("expect location %v, but got %v", expect, location)
 	}
 }
 
@@ -391,7 +3125,90 @@ func TestCreateMetaPartition(t *testing.T) {
 func TestCreateDataPartition(t *testing.T) {
 	reqURL := fmt.Sprintf("%v%v?count=2&name=%v&type=extent",
 		hostAddr, proto.AdminCreateDataPartition, commonVol.Name)
-	process(reqURL, t)
+	reply := process(reqURL, t)
+	ids := reply.Data.(map[string]interface{})["PartitionIDs"].([]interface{})
+	if len(ids) != 2 {
+		t.Errorf("expect 2 created data partitions, but got %v", ids)
+		return
+	}
+	for _, id := range ids {
+		if _, err := commonVol.getDataPartitionByID(uint64(id.(float64))); err != nil {
+			t.Errorf("created data partition[%v] not found, err[%v]", id, err)
+		}
+	}
+}
+
+func TestReservePartitionIDs(t *testing.T) {
+	volName := "reserveIdsVol"
+	createVol(volName, t)
+	defer markDeleteVol(volName, t)
+	vol, err := server.cluster.getVol(volName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	idRange, err := mc.AdminAPI().ReservePartitionIDs(volName, 2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if idRange.VolName != volName || idRange.End != idRange.Start+1 {
+		t.Errorf("expect 2 ids reserved for vol %v, but got %v", volName, *idRange)
+		return
+	}
+	// the next data partition of the vol takes the smallest reserved id
+	reqURL := fmt.Sprintf("%v%v?count=1&name=%v&type=extent", hostAddr, proto.AdminCreateDataPartition, volName)
+	reply := process(reqURL, t)
+	ids := reply.Data.(map[string]interface{})["PartitionIDs"].([]interface{})
+	if len(ids) != 1 || uint64(ids[0].(float64)) != idRange.Start {
+		t.Errorf("expect the data partition created with the reserved id %v, but got %v", idRange.Start, ids)
+	}
+	vol.reservedDpIDsLock.Lock()
+	reserved := append([]uint64(nil), vol.reservedDpIDs...)
+	vol.reservedDpIDsLock.Unlock()
+	if len(reserved) != 1 || reserved[0] != idRange.End {
+		t.Errorf("expect the id %v left reserved, but got %v", idRange.End, reserved)
+	}
+	for _, count := range []int{0, maxReservedPartitionIDCount + 1} {
+		if _, err = mc.AdminAPI().ReservePartitionIDs(volName, count); err == nil {
+			t.Errorf("expect the count %v rejected", count)
+		}
+	}
+	if _, err = mc.AdminAPI().ReservePartitionIDs("not_exists_vol", 1); err == nil {
+		t.Errorf("expect the ids of an unknown vol rejected")
+	}
+}
+
+func TestCreateDataPartitionFailure(t *testing.T) {
+	// no node set of the zone has as many data nodes as the replicas of the volume
+	commonVol.volLock.Lock()
+	zoneName := commonVol.zoneName
+	commonVol.zoneName = testZone1
+	commonVol.volLock.Unlock()
+	defer func() {
+		commonVol.volLock.Lock()
+		commonVol.zoneName = zoneName
+		commonVol.volLock.Unlock()
+	}()
+	reqURL := fmt.Sprintf("%v%v?count=2&name=%v&type=extent", hostAddr, proto.AdminCreateDataPartition, commonVol.Name)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{Data: &proto.DataPartitionCreateFailure{}}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code == proto.ErrCodeSuccess {
+		t.Errorf("expect the creation to fail, but got reply[%v] err[%v]", reply, err)
+		return
+	}
+	failure := reply.Data.(*proto.DataPartitionCreateFailure)
+	if failure.RequestCount != 2 || failure.CreatedCount != 0 || failure.Reason != proto.DpCreateFailedPlacementViolation ||
+		!strings.Contains(reply.Msg, failure.Reason) {
+		t.Errorf("expect the failure of the placement violation, but got %v, msg[%v]", *failure, reply.Msg)
+	}
 }
 
 func TestGetDataPartition(t *testing.T) {
@@ -418,6 +3235,35 @@ func TestLoadDataPartition(t *testing.T) {
 	process(reqURL, t)
 }
 
+func TestVerifyDataPartition(t *testing.T) {
+	if len(commonVol.dataPartitions.partitions) == 0 {
+		t.Errorf("no data partitions")
+		return
+	}
+	partition := commonVol.dataPartitions.partitions[0]
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	result, err := mc.AdminAPI().VerifyDataPartition(partition.PartitionID, 10)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !result.Consistent || len(result.Replicas) != len(partition.Hosts) || result.CheckedFiles == 0 {
+		t.Errorf("expect the replicas %v to agree, but got %v", partition.Hosts, result)
+	}
+
+	v := newDpVerification([]string{"a", "b", "c"})
+	modified := time.Now().Unix() - defaultIntervalToCheckCrc - 1
+	for addr, crc := range map[string]uint32{"a": 1, "b": 1, "c": 2} {
+		v.collect(addr, &proto.LoadDataPartitionResponse{Status: proto.TaskSucceeds,
+			PartitionSnapshot: []*proto.File{{Name: "1", Crc: crc, Modified: modified}}})
+	}
+	result = &proto.DataPartitionVerification{}
+	v.compare(result)
+	if result.Consistent || len(result.DivergentAddrs) != 1 || result.DivergentAddrs[0] != "c" {
+		t.Errorf("expect the replica c to diverge, but got %v", result.DivergentAddrs)
+	}
+}
+
 func TestDataPartitionDecommission(t *testing.T) {
 	if len(commonVol.dataPartitions.partitions) == 0 {
 		t.Errorf("no data partitions")
@@ -437,6 +3283,35 @@ func TestDataPartitionDecommission(t *testing.T) {
 	partition.isRecover = false
 }
 
+func TestBatchDecommissionDataPartition(t *testing.T) {
+	if len(commonVol.dataPartitions.partitions) < 2 {
+		t.Errorf("no enough data partitions")
+		return
+	}
+	server.cluster.checkDataNodeHeartbeat()
+	time.Sleep(5 * time.Second)
+	partition := commonVol.dataPartitions.partitions[1]
+	offlineAddr := partition.Hosts[0]
+	reqURL := fmt.Sprintf("%v%v?addr=%v&ids=%v,%v",
+		hostAddr, proto.AdminBatchDecommissionDps, offlineAddr, partition.PartitionID, math.MaxUint32)
+	reply := process(reqURL, t)
+	results := reply.Data.([]interface{})
+	if len(results) != 2 {
+		t.Errorf("expect 2 results, but got %v", results)
+		return
+	}
+	if success := results[0].(map[string]interface{})["Success"]; success != true {
+		t.Errorf("expect partition[%v] to be decommissioned, result %v", partition.PartitionID, results[0])
+	}
+	if success := results[1].(map[string]interface{})["Success"]; success != false {
+		t.Errorf("expect the missing partition to fail, result %v", results[1])
+	}
+	if contains(partition.Hosts, offlineAddr) {
+		t.Errorf("offlineAddr[%v],hosts[%v]", offlineAddr, partition.Hosts)
+	}
+	partition.isRecover = false
+}
+
 //func TestGetAllVols(t *testing.T) {
 //	reqURL := fmt.Sprintf("%v%v", hostAddr, proto.GetALLVols)
 //	process(reqURL, t)
@@ -457,6 +3332,141 @@ func TestGetTopo(t *testing.T) {
 	process(reqURL, t)
 }
 
+func TestGetTopoWithFilter(t *testing.T) {
+	count := func(reply *proto.HTTPReply, kind string) (n int) {
+		for _, zone := range reply.Data.(map[string]interface{})["Zones"].([]interface{}) {
+			for _, ns := range zone.(map[string]interface{})["NodeSet"].(map[string]interface{}) {
+				n += len(ns.(map[string]interface{})[kind].([]interface{}))
+			}
+		}
+		return
+	}
+	reply := process(fmt.Sprintf("%v%v?type=data&status=active", hostAddr, proto.GetTopologyView), t)
+	if n := count(reply, "MetaNodes"); n != 0 {
+		t.Errorf("expect no meta nodes of type data, but got %v", n)
+	}
+	if n := count(reply, "DataNodes"); n == 0 {
+		t.Errorf("expect the active data nodes, but got none")
+	}
+	reply = process(fmt.Sprintf("%v%v?type=meta&status=inactive", hostAddr, proto.GetTopologyView), t)
+	if n := count(reply, "DataNodes"); n != 0 {
+		t.Errorf("expect no data nodes of type meta, but got %v", n)
+	}
+	for _, query := range []string{"type=disk", "status=down", "start=-1", "count=0"} {
+		resp, err := http.Get(fmt.Sprintf("%v%v?%v", hostAddr, proto.GetTopologyView, query))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		reply := &proto.HTTPReply{}
+		err = json.NewDecoder(resp.Body).Decode(reply)
+		resp.Body.Close()
+		if err != nil || reply.Code != proto.ErrCodeParamError {
+			t.Errorf("expect %v to be rejected, reply[%v] err[%v]", query, reply, err)
+		}
+	}
+}
+
+func TestGetTopoPage(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	all, err := mc.AdminAPI().Topo()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	addrs := func(topo *proto.TopologyView) (dataAddrs, metaAddrs []string) {
+		for _, zone := range topo.Zones {
+			ids := make([]uint64, 0, len(zone.NodeSet))
+			for id := range zone.NodeSet {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			for _, id := range ids {
+				for _, node := range zone.NodeSet[id].DataNodes {
+					dataAddrs = append(dataAddrs, node.Addr)
+				}
+				for _, node := range zone.NodeSet[id].MetaNodes {
+					metaAddrs = append(metaAddrs, node.Addr)
+				}
+			}
+		}
+		return
+	}
+	allData, allMeta := addrs(all)
+	if all.DataNodeTotal != len(allData) || all.MetaNodeTotal != len(allMeta) || len(allData) < 3 {
+		t.Errorf("expect the totals %v and %v listed in full, but got %v and %v", len(allData), len(allMeta), all.DataNodeTotal, all.MetaNodeTotal)
+		return
+	}
+	var pagedData []string
+	for start := 0; start < all.DataNodeTotal; start += 2 {
+		page, err := mc.AdminAPI().TopoPage(start, 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		dataAddrs, metaAddrs := addrs(page)
+		if page.DataNodeTotal != all.DataNodeTotal || len(dataAddrs) > 2 || len(metaAddrs) > 2 {
+			t.Errorf("expect at most 2 nodes of each type from %v of %v, but got %v and %v", start, all.DataNodeTotal, dataAddrs, metaAddrs)
+		}
+		pagedData = append(pagedData, dataAddrs...)
+	}
+	if strings.Join(pagedData, ",") != strings.Join(allData, ",") {
+		t.Errorf("expect the pages to list %v, but got %v", allData, pagedData)
+	}
+}
+
+func TestGetDataNodePartitions(t *testing.T) {
+	partition := commonVol.dataPartitions.partitions[0]
+	addr := partition.Hosts[0]
+	reqURL := fmt.Sprintf("%v%v?addr=%v", hostAddr, proto.GetDataNodePartitions, addr)
+	reply := process(reqURL, t)
+	found := false
+	for _, item := range reply.Data.([]interface{}) {
+		view := item.(map[string]interface{})
+		if !contains(toStrings(view["Hosts"]), addr) {
+			t.Errorf("data partition %v has no replica on %v", view["PartitionID"], addr)
+		}
+		if uint64(view["PartitionID"].(float64)) == partition.PartitionID && view["VolName"] == commonVol.Name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expect data partition[%v] of vol[%v] on %v", partition.PartitionID, commonVol.Name, addr)
+	}
+}
+
+func TestGetMetaNodePartitions(t *testing.T) {
+	mp := commonVol.MetaPartitions[commonVol.maxPartitionID()]
+	addr := mp.Hosts[0]
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	partitions, err := mc.NodeAPI().GetMetaNodePartitions(addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	found := false
+	for _, view := range partitions {
+		if !contains(view.Members, addr) {
+			t.Errorf("meta partition %v has no replica on %v", view.PartitionID, addr)
+		}
+		if view.PartitionID == mp.PartitionID && view.VolName == commonVol.Name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expect meta partition[%v] of vol[%v] on %v", mp.PartitionID, commonVol.Name, addr)
+	}
+	resp, err := http.Get(fmt.Sprintf("%v%v?addr=%v", hostAddr, proto.GetMetaNodePartitions, "127.0.0.1:19999"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expect 404 for an unknown meta node, but got %v", resp.StatusCode)
+	}
+}
+
 func TestGetMetaNode(t *testing.T) {
 	reqURL := fmt.Sprintf("%v%v?addr=%v", hostAddr, proto.GetMetaNode, mms1Addr)
 	process(reqURL, t)
@@ -467,6 +3477,20 @@ func TestAddDataReplica(t *testing.T) {
 	dsAddr := "127.0.0.1:9106"
 	addDataServer(dsAddr, "zone2")
 	reqURL := fmt.Sprintf("%v%v?id=%v&addr=%v", hostAddr, proto.AdminAddDataReplica, partition.PartitionID, dsAddr)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the replica exceeding %v rejected, reply[%v] err[%v]", cfgMaxReplicaNum, reply, err)
+		return
+	}
+	server.config.maxReplicaNum = defaultMaxReplicaNum + 1
+	defer func() { server.config.maxReplicaNum = defaultMaxReplicaNum }()
 	process(reqURL, t)
 	partition.RLock()
 	if !contains(partition.Hosts, dsAddr) {
@@ -488,6 +3512,103 @@ func TestAddDataReplica(t *testing.T) {
 		})
 }
 
+func TestAddDataReplicaAuto(t *testing.T) {
+	partition := commonVol.dataPartitions.partitions[1]
+	server.config.maxReplicaNum = defaultMaxReplicaNum + 1
+	defer func() { server.config.maxReplicaNum = defaultMaxReplicaNum }()
+	reqURL := fmt.Sprintf("%v%v?id=%v&addr=%v", hostAddr, proto.AdminAddDataReplica, partition.PartitionID, autoReplicaAddr)
+	fmt.Println(reqURL)
+	reply := process(reqURL, t)
+	if reply == nil {
+		return
+	}
+	addition := reply.Data.(map[string]interface{})
+	addr := addition["Addr"].(string)
+	hosts := toStrings(addition["Hosts"])
+	if len(hosts) != int(partition.ReplicaNum)+1 || !contains(hosts, addr) {
+		t.Errorf("expect the new replica %v in hosts, but got %v", addr, hosts)
+	}
+	server.cluster.BadDataPartitionIds.Range(func(key, value interface{}) bool {
+		if strings.HasPrefix(key.(string), addr) {
+			server.cluster.BadDataPartitionIds.Delete(key)
+		}
+		return true
+	})
+	partition.isRecover = false
+	if err := server.cluster.removeDataReplica(partition, addr, false); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigrateDataPartition(t *testing.T) {
+	server.cluster.checkDataNodeHeartbeat()
+	time.Sleep(5 * time.Second)
+	partition := commonVol.dataPartitions.partitions[2]
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	// the earlier tests may have moved the replicas, pick the nodes by the current hosts
+	partition.RLock()
+	srcAddr := partition.Hosts[0]
+	var freeAddrs []string
+	for _, addr := range []string{mds1Addr, mds2Addr, mds3Addr, mds4Addr, mds5Addr} {
+		if !partition.hasHost(addr) {
+			freeAddrs = append(freeAddrs, addr)
+		}
+	}
+	partition.RUnlock()
+	if len(freeAddrs) < 2 {
+		t.Errorf("expect 2 data nodes not hosting partition[%v] at least, but got %v", partition.PartitionID, freeAddrs)
+		return
+	}
+	if _, err := mc.AdminAPI().MigrateDataPartition(partition.PartitionID, freeAddrs[0], freeAddrs[1]); err == nil {
+		t.Errorf("expect the source not hosting the partition rejected")
+	}
+	move := func(srcAddr, targetAddr string) {
+		hosts, err := mc.AdminAPI().MigrateDataPartition(partition.PartitionID, srcAddr, targetAddr)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !contains(hosts, targetAddr) || contains(hosts, srcAddr) {
+			t.Errorf("expect the replica moved from %v to %v, but got hosts %v", srcAddr, targetAddr, hosts)
+		}
+		if _, ok := server.cluster.BadDataPartitionIds.Load(srcAddr + ":"); ok {
+			t.Errorf("expect the source %v not taken as bad", srcAddr)
+		}
+		server.cluster.BadDataPartitionIds.Delete(targetAddr + ":")
+		partition.isRecover = false
+	}
+	move(srcAddr, freeAddrs[0])
+	move(freeAddrs[0], srcAddr)
+}
+
+func TestRebalanceDataPartitions(t *testing.T) {
+	mc := masterSDK.NewMasterClient([]string{strings.TrimPrefix(hostAddr, "http://")}, false)
+	if _, err := mc.AdminAPI().RebalanceDataPartitions(server.config.rebalanceMaxConcurrentMoves+1, true); err == nil {
+		t.Errorf("expect maxMoves above %v rejected", server.config.rebalanceMaxConcurrentMoves)
+	}
+	plan, err := mc.AdminAPI().RebalanceDataPartitions(2, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !plan.DryRun || len(plan.Moves) > 2 {
+		t.Errorf("expect a dry run of 2 moves at most, but got %v moves, dryRun[%v]", len(plan.Moves), plan.DryRun)
+	}
+	for _, move := range plan.Moves {
+		dp, err := server.cluster.getDataPartitionByID(move.PartitionID)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		dp.RLock()
+		if !dp.hasHost(move.SrcAddr) || dp.hasHost(move.TargetAddr) {
+			t.Errorf("expect partition[%v] still on %v and not on %v, but got hosts %v",
+				move.PartitionID, move.SrcAddr, move.TargetAddr, dp.Hosts)
+		}
+		dp.RUnlock()
+	}
+}
+
 func TestRemoveDataReplica(t *testing.T) {
 	partition := commonVol.dataPartitions.partitions[0]
 	partition.isRecover = false
@@ -0,0 +1,128 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+const (
+	auditResultSuccess = "success"
+)
+
+// auditLog keeps the most recent admin actions in a ring buffer.
+// The events are kept in memory on the master that handled the request, they are not replicated by raft.
+type auditLog struct {
+	sync.RWMutex
+	events []*proto.AuditEvent
+	next   int
+	full   bool
+}
+
+func newAuditLog(capacity int) *auditLog {
+	if capacity <= 0 {
+		capacity = defaultAuditLogCapacity
+	}
+	return &auditLog{events: make([]*proto.AuditEvent, capacity)}
+}
+
+func (l *auditLog) append(event *proto.AuditEvent) {
+	l.Lock()
+	defer l.Unlock()
+	l.events[l.next] = event
+	l.next++
+	if l.next == len(l.events) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// the events from the oldest to the newest which match the filter
+func (l *auditLog) filter(match func(event *proto.AuditEvent) bool) (events []*proto.AuditEvent) {
+	l.RLock()
+	defer l.RUnlock()
+	events = make([]*proto.AuditEvent, 0)
+	start, count := 0, l.next
+	if l.full {
+		start, count = l.next, len(l.events)
+	}
+	for i := 0; i < count; i++ {
+		event := l.events[(start+i)%len(l.events)]
+		if match(event) {
+			events = append(events, event)
+		}
+	}
+	return
+}
+
+// at most limit of the newest events which match the filter, from the oldest to the newest
+func (l *auditLog) recent(limit int, match func(event *proto.AuditEvent) bool) (events []*proto.AuditEvent) {
+	l.RLock()
+	defer l.RUnlock()
+	events = make([]*proto.AuditEvent, 0)
+	count := l.next
+	if l.full {
+		count = len(l.events)
+	}
+	for i := 1; i <= count && len(events) < limit; i++ {
+		event := l.events[(l.next-i+len(l.events))%len(l.events)]
+		if match(event) {
+			events = append(events, event)
+		}
+	}
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return
+}
+
+func (c *Cluster) addAuditEvent(action, callerAddr, target string, err error) {
+	result := auditResultSuccess
+	if err != nil {
+		result = err.Error()
+	}
+	c.auditLog.append(&proto.AuditEvent{
+		Time:       time.Now().Unix(),
+		Action:     action,
+		CallerAddr: callerAddr,
+		Target:     target,
+		Result:     result,
+	})
+}
+
+// the newest events of the action, or of all the actions if it is empty
+func (c *Cluster) getRecentAuditEvents(count int, action string) []*proto.AuditEvent {
+	return c.auditLog.recent(count, func(event *proto.AuditEvent) bool {
+		return action == "" || event.Action == action
+	})
+}
+
+// the addr matches the caller either exactly or by the host without the port
+func (c *Cluster) getAuditEventsByAddr(addr string, startTime, endTime int64) []*proto.AuditEvent {
+	return c.auditLog.filter(func(event *proto.AuditEvent) bool {
+		if event.Time < startTime || event.Time > endTime {
+			return false
+		}
+		if event.CallerAddr == addr {
+			return true
+		}
+		host, _, err := net.SplitHostPort(event.CallerAddr)
+		return err == nil && host == addr
+	})
+}
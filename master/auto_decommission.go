@@ -0,0 +1,176 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// autoDecommissioner keeps the policy deciding when a data node without heartbeat is decommissioned automatically.
+type autoDecommissioner struct {
+	sync.RWMutex
+	policy *proto.AutoDecommissionPolicy
+}
+
+func newAutoDecommissioner() *autoDecommissioner {
+	return &autoDecommissioner{policy: &proto.AutoDecommissionPolicy{}}
+}
+
+func (ad *autoDecommissioner) getPolicy() (policy *proto.AutoDecommissionPolicy) {
+	ad.RLock()
+	defer ad.RUnlock()
+	policy = new(proto.AutoDecommissionPolicy)
+	*policy = *ad.policy
+	return
+}
+
+func (ad *autoDecommissioner) setPolicy(policy *proto.AutoDecommissionPolicy) {
+	ad.Lock()
+	defer ad.Unlock()
+	ad.policy = policy
+}
+
+// The timeout should be longer than the one taking a data node inactive, or the nodes missing a heartbeat or two go away.
+func checkAutoDecommissionPolicy(policy *proto.AutoDecommissionPolicy) (err error) {
+	if policy.Enable && policy.TimeoutSec < defaultNodeTimeOutSec {
+		return fmt.Errorf("timeout[%v] should be at least %v seconds", policy.TimeoutSec, defaultNodeTimeOutSec)
+	}
+	if policy.MaxConcurrentNodes < 0 {
+		return fmt.Errorf("maxNodes[%v] should not be negative", policy.MaxConcurrentNodes)
+	}
+	if policy.MaxInactiveRatio < 0 || policy.MaxInactiveRatio > 1 {
+		return fmt.Errorf("maxInactiveRatio[%v] should be in [0, 1]", policy.MaxInactiveRatio)
+	}
+	return
+}
+
+// A data node to be decommissioned automatically, along with the time it was seen last.
+type autoDecommissionCandidate struct {
+	dataNode *DataNode
+	lastSeen time.Time
+}
+
+// Decommission the data nodes without heartbeat for longer than the timeout of the policy. The nodes loaded by a new leader
+// have not reported to it yet, so the time without heartbeat is counted from leaderSince at most.
+// Nothing is decommissioned if too many data nodes are inactive, as it is more likely that the master is cut off from them,
+// and at most MaxConcurrentNodes data nodes are decommissioned in the background at the same time, the ones started by hand included.
+func (c *Cluster) checkAutoDecommission(leaderSince time.Time) {
+	policy := c.autoDecommissioner.getPolicy()
+	if !policy.Enable {
+		return
+	}
+	timeout := time.Duration(policy.TimeoutSec) * time.Second
+	rate := c.cfg.decommissionRate
+	if rate <= 0 {
+		rate = defaultAutoDecommissionRate
+	}
+	maxNodes := policy.MaxConcurrentNodes
+	if maxNodes <= 0 {
+		maxNodes = defaultAutoDecommissionMaxNodes
+	}
+	maxInactiveRatio := policy.MaxInactiveRatio
+	if maxInactiveRatio <= 0 {
+		maxInactiveRatio = defaultAutoDecommissionInactiveRatio
+	}
+	var total, inactive int
+	candidates := make([]*autoDecommissionCandidate, 0)
+	c.dataNodes.Range(func(key, value interface{}) bool {
+		dataNode := value.(*DataNode)
+		dataNode.RLock()
+		lastSeen, isActive, toBeOffline := dataNode.ReportTime, dataNode.isActive, dataNode.ToBeOffline
+		dataNode.RUnlock()
+		total++
+		if !isActive {
+			inactive++
+		}
+		if lastSeen.Before(leaderSince) {
+			lastSeen = leaderSince
+		}
+		if isActive || toBeOffline || time.Since(lastSeen) <= timeout {
+			return true
+		}
+		if _, ok := c.nodeDecommissions.Load(dataNode.Addr); ok {
+			return true
+		}
+		candidates = append(candidates, &autoDecommissionCandidate{dataNode: dataNode, lastSeen: lastSeen})
+		return true
+	})
+	if len(candidates) == 0 {
+		return
+	}
+	if float64(inactive) > float64(total)*maxInactiveRatio {
+		Warn(c.Name, fmt.Sprintf("action[checkAutoDecommission] clusterID[%v] %v of %v data nodes are inactive, "+
+			"more than the ratio %v, skip the automatic decommission", c.Name, inactive, total, maxInactiveRatio))
+		return
+	}
+	running := 0
+	c.nodeDecommissions.Range(func(key, value interface{}) bool {
+		running++
+		return true
+	})
+	for _, candidate := range candidates {
+		if running >= maxNodes {
+			log.LogWarnf("action[checkAutoDecommission] clusterID[%v] %v data nodes are being decommissioned, "+
+				"node[%v] waits for the next check", c.Name, running, candidate.dataNode.Addr)
+			continue
+		}
+		jobID, err := c.startDataNodeDecommission(candidate.dataNode.Addr, 0, rate)
+		c.addAuditEvent("autoDecommissionDataNode", c.masterAddr(), candidate.dataNode.Addr, err)
+		if err != nil {
+			log.LogErrorf("action[checkAutoDecommission] clusterID[%v] node[%v] err[%v]", c.Name, candidate.dataNode.Addr, err)
+			continue
+		}
+		running++
+		Warn(c.Name, fmt.Sprintf("action[checkAutoDecommission] clusterID[%v] node[%v] has no heartbeat since %v, "+
+			"decommission it automatically, job[%v]", c.Name, candidate.dataNode.Addr, candidate.lastSeen.Format(time.RFC3339), jobID))
+	}
+}
+
+func (c *Cluster) scheduleToCheckAutoDecommission() {
+	go func() {
+		var leaderSince time.Time
+		for {
+			if c.partition != nil && c.partition.IsRaftLeader() {
+				if leaderSince.IsZero() {
+					leaderSince = time.Now()
+				}
+				c.checkAutoDecommission(leaderSince)
+			} else {
+				leaderSince = time.Time{}
+			}
+			time.Sleep(time.Second * time.Duration(c.cfg.IntervalToCheckDataPartition))
+		}
+	}()
+}
+
+func (c *Cluster) setAutoDecommissionPolicy(policy *proto.AutoDecommissionPolicy) (err error) {
+	if err = checkAutoDecommissionPolicy(policy); err != nil {
+		return
+	}
+	oldPolicy := c.autoDecommissioner.getPolicy()
+	c.autoDecommissioner.setPolicy(policy)
+	if err = c.syncPutCluster(); err != nil {
+		log.LogErrorf("action[setAutoDecommissionPolicy] err[%v]", err)
+		c.autoDecommissioner.setPolicy(oldPolicy)
+		err = proto.ErrPersistenceByRaft
+		return
+	}
+	return
+}
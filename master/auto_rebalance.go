@@ -0,0 +1,288 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// autoRebalancer keeps the policy deciding when the data partitions are moved automatically
+// from the most loaded data node to the least loaded one of the same node set.
+type autoRebalancer struct {
+	sync.RWMutex
+	policy *proto.AutoRebalancePolicy
+	last   *proto.AutoRebalanceRecord
+}
+
+func newAutoRebalancer() *autoRebalancer {
+	return &autoRebalancer{policy: &proto.AutoRebalancePolicy{}}
+}
+
+func (ar *autoRebalancer) getPolicy() (policy *proto.AutoRebalancePolicy) {
+	ar.RLock()
+	defer ar.RUnlock()
+	policy = new(proto.AutoRebalancePolicy)
+	*policy = *ar.policy
+	return
+}
+
+func (ar *autoRebalancer) setPolicy(policy *proto.AutoRebalancePolicy) {
+	ar.Lock()
+	defer ar.Unlock()
+	ar.policy = policy
+}
+
+func (ar *autoRebalancer) getLast() (last *proto.AutoRebalanceRecord) {
+	ar.RLock()
+	defer ar.RUnlock()
+	return ar.last
+}
+
+func (ar *autoRebalancer) setLast(last *proto.AutoRebalanceRecord) {
+	ar.Lock()
+	defer ar.Unlock()
+	ar.last = last
+}
+
+func checkAutoRebalancePolicy(policy *proto.AutoRebalancePolicy) (err error) {
+	if policy.MinBalanceScore < 0 || policy.MinBalanceScore > 1 {
+		return fmt.Errorf("minBalanceScore[%v] should be in [0, 1]", policy.MinBalanceScore)
+	}
+	if policy.WindowStartHour < 0 || policy.WindowStartHour > 23 || policy.WindowEndHour < 0 || policy.WindowEndHour > 23 {
+		return fmt.Errorf("the window hours [%v, %v) should be in [0, 23]", policy.WindowStartHour, policy.WindowEndHour)
+	}
+	if policy.Enable && (policy.MaxConcurrentMoves <= 0 || policy.MaxConcurrentMoves > defaultMigrateDpCnt) {
+		return fmt.Errorf("maxConcurrentMoves[%v] should be in [1, %v]", policy.MaxConcurrentMoves, defaultMigrateDpCnt)
+	}
+	return
+}
+
+// The window [WindowStartHour, WindowEndHour) may cross the midnight, and the same start and end hour means all day.
+func inRebalanceWindow(policy *proto.AutoRebalancePolicy, now time.Time) bool {
+	start, end, hour := policy.WindowStartHour, policy.WindowEndHour, now.Hour()
+	if start == end {
+		return true
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// The data partition counts of the writable data nodes, grouped by their node sets.
+// The data partitions are moved within a node set only, so the balance is measured per node set.
+func (c *Cluster) nodeSetDataPartitionCounts() (counts map[uint64]map[string]uint32) {
+	counts = make(map[uint64]map[string]uint32)
+	c.dataNodes.Range(func(key, value interface{}) bool {
+		dataNode := value.(*DataNode)
+		if !dataNode.isWriteAble() {
+			return true
+		}
+		if _, ok := counts[dataNode.NodeSetID]; !ok {
+			counts[dataNode.NodeSetID] = make(map[string]uint32)
+		}
+		counts[dataNode.NodeSetID][dataNode.Addr] = dataNode.DataPartitionCount
+		return true
+	})
+	return
+}
+
+// The balance score of a node set is the ratio of the min and the max data partition count of its writable
+// data nodes, the score of the cluster is the one of the worst node set. 1 means the data partitions are
+// evenly distributed in every node set.
+func (c *Cluster) dataNodeBalanceScore() (score float64) {
+	score = 1
+	for _, nodes := range c.nodeSetDataPartitionCounts() {
+		if nodeSetScore := balanceScore(nodes); nodeSetScore < score {
+			score = nodeSetScore
+		}
+	}
+	return
+}
+
+func balanceScore(nodes map[string]uint32) float64 {
+	var minCount, maxCount uint32
+	first := true
+	for _, count := range nodes {
+		if first || count < minCount {
+			minCount = count
+		}
+		if first || count > maxCount {
+			maxCount = count
+		}
+		first = false
+	}
+	if maxCount == 0 {
+		return 1
+	}
+	return float64(minCount) / float64(maxCount)
+}
+
+func (c *Cluster) recoveringDataPartitionCount() (count int) {
+	for _, vol := range c.allVols() {
+		for _, dp := range vol.cloneDataPartitionMap() {
+			if dp.isRecover {
+				count++
+			}
+		}
+	}
+	return
+}
+
+// Plan at most limit moves, each from the most loaded data node of a node set to the least loaded one,
+// until the data partition counts of the nodes in every node set differ by 1 at most.
+func (c *Cluster) planRebalance(limit int) (moves []*proto.DataPartitionMove) {
+	counts := c.nodeSetDataPartitionCounts()
+	planned := make(map[uint64]bool)
+	for len(moves) < limit {
+		var src, dst string
+		var gap uint32
+		for _, nodes := range counts {
+			maxAddr, minAddr := "", ""
+			for addr, count := range nodes {
+				if maxAddr == "" || count > nodes[maxAddr] {
+					maxAddr = addr
+				}
+				if minAddr == "" || count < nodes[minAddr] {
+					minAddr = addr
+				}
+			}
+			if maxAddr != "" && nodes[maxAddr]-nodes[minAddr] > gap {
+				src, dst, gap = maxAddr, minAddr, nodes[maxAddr]-nodes[minAddr]
+			}
+		}
+		if gap <= 1 {
+			return
+		}
+		var move *proto.DataPartitionMove
+		for _, dp := range c.getAllDataPartitionByDataNode(src) {
+			if planned[dp.PartitionID] || dp.hasHost(dst) || c.validateDecommissionDataPartition(dp, src) != nil {
+				continue
+			}
+			move = &proto.DataPartitionMove{PartitionID: dp.PartitionID, VolName: dp.VolName, SrcAddr: src, TargetAddr: dst}
+			break
+		}
+		for _, nodes := range counts {
+			if _, ok := nodes[src]; !ok {
+				continue
+			}
+			if move == nil {
+				// nothing on the node can be moved, leave the node set alone
+				delete(nodes, src)
+				break
+			}
+			nodes[src]--
+			nodes[dst]++
+			planned[move.PartitionID] = true
+			moves = append(moves, move)
+			break
+		}
+	}
+	return
+}
+
+// Execute the moves one by one, the error of a failed move is recorded on it and does not stop the others.
+// The source replicas are not taken as bad, balancing is not decommissioning.
+func (c *Cluster) executeRebalancePlan(moves []*proto.DataPartitionMove) (done int) {
+	for _, move := range moves {
+		dp, err := c.getDataPartitionByID(move.PartitionID)
+		if err == nil {
+			err = c.moveDataPartition(move.SrcAddr, move.TargetAddr, dp)
+		}
+		if err != nil {
+			log.LogWarnf("action[executeRebalancePlan] move partition[%v] from [%v] to [%v] err[%v]",
+				move.PartitionID, move.SrcAddr, move.TargetAddr, err)
+			move.Err = err.Error()
+			continue
+		}
+		done++
+	}
+	return
+}
+
+// Plan the moves to balance the data nodes and execute them unless dryRun is set. The moves are bounded by
+// maxConcurrentMoves minus the data partitions recovering already, and by limit if it is positive.
+func (c *Cluster) rebalanceDataPartitions(limit, maxConcurrentMoves int, dryRun bool) (plan *proto.RebalancePlan, err error) {
+	plan = &proto.RebalancePlan{
+		DryRun:       dryRun,
+		BalanceScore: c.dataNodeBalanceScore(),
+		Recovering:   c.recoveringDataPartitionCount(),
+	}
+	allowed := maxConcurrentMoves - plan.Recovering
+	if allowed <= 0 {
+		return nil, fmt.Errorf("%v data partitions are recovering, no more than %v are allowed, wait for them", plan.Recovering, maxConcurrentMoves)
+	}
+	if limit > 0 && limit < allowed {
+		allowed = limit
+	}
+	plan.Moves = c.planRebalance(allowed)
+	if !dryRun {
+		c.executeRebalancePlan(plan.Moves)
+	}
+	log.LogInfof("action[rebalanceDataPartitions] balance score[%v] recovering[%v] moves[%v] dryRun[%v]",
+		plan.BalanceScore, plan.Recovering, len(plan.Moves), dryRun)
+	return
+}
+
+func (c *Cluster) checkAutoRebalance() {
+	policy := c.autoRebalancer.getPolicy()
+	if !policy.Enable || !inRebalanceWindow(policy, time.Now()) {
+		return
+	}
+	score := c.dataNodeBalanceScore()
+	if score >= policy.MinBalanceScore {
+		return
+	}
+	record := &proto.AutoRebalanceRecord{Time: time.Now().Unix(), BalanceScore: score}
+	inflight := c.recoveringDataPartitionCount()
+	if inflight >= policy.MaxConcurrentMoves {
+		record.Msg = fmt.Sprintf("%v data partitions are recovering, wait for them", inflight)
+	} else {
+		record.Moves = c.executeRebalancePlan(c.planRebalance(policy.MaxConcurrentMoves - inflight))
+	}
+	log.LogInfof("action[checkAutoRebalance] balance score[%v] moves[%v] msg[%v]", score, record.Moves, record.Msg)
+	c.autoRebalancer.setLast(record)
+}
+
+func (c *Cluster) scheduleToCheckAutoRebalance() {
+	go func() {
+		for {
+			if c.partition != nil && c.partition.IsRaftLeader() {
+				c.checkAutoRebalance()
+			}
+			time.Sleep(time.Second * time.Duration(c.cfg.IntervalToCheckDataPartition))
+		}
+	}()
+}
+
+func (c *Cluster) setAutoRebalancePolicy(policy *proto.AutoRebalancePolicy) (err error) {
+	if err = checkAutoRebalancePolicy(policy); err != nil {
+		return
+	}
+	oldPolicy := c.autoRebalancer.getPolicy()
+	c.autoRebalancer.setPolicy(policy)
+	if err = c.syncPutCluster(); err != nil {
+		log.LogErrorf("action[setAutoRebalancePolicy] err[%v]", err)
+		c.autoRebalancer.setPolicy(oldPolicy)
+		err = proto.ErrPersistenceByRaft
+		return
+	}
+	return
+}
@@ -16,6 +16,8 @@ package master
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -38,6 +40,7 @@ type Cluster struct {
 	createVolMutex            sync.RWMutex // create volume mutex
 	mnMutex                   sync.RWMutex // meta node mutex
 	dnMutex                   sync.RWMutex // data node mutex
+	zoneMutex                 sync.RWMutex // DefaultZone mutex
 	badPartitionMutex         sync.RWMutex // BadDataPartitionIds and BadMetaPartitionIds operate mutex
 	leaderInfo                *LeaderInfo
 	cfg                       *clusterConfig
@@ -51,7 +54,18 @@ type Cluster struct {
 	nodeSetGrpManager         *nodeSetGrpManager
 	BadDataPartitionIds       *sync.Map
 	BadMetaPartitionIds       *sync.Map
+	diskDecommissions         sync.Map // the disks being decommissioned, keyed by addr:diskPath
+	nodeDecommissions         sync.Map // the data nodes being decommissioned in the background, keyed by addr
+	dpVerifications           sync.Map // the data partitions being verified, keyed by partition id
+	jobs                      *jobRegistry
 	DisableAutoAllocate       bool
+	MaintenanceMode           bool // the mutating APIs are rejected while it is true
+	AllocationStrategy        string
+	MaxVolumes                uint64  // the max number of volumes in the cluster, 0 means unlimited
+	DefaultZone               string  // the zone of the volumes without zone constraint, DefaultZoneName if empty
+	HeartbeatVerbosity        string  // the detail level asked of the node heartbeats, minimal if empty
+	MinRWDataPartitions       int     // the vols with fewer r&w data partitions get new ones, minNumOfRWDataPartitions if 0
+	AutoAllocUsedRatio        float64 // the vols with more used space than the ratio of the capacity get none, defaultAutoAllocUsedRatio if 0
 	FaultDomain               bool
 	needFaultDomain           bool // FaultDomain is true and normal zone aleady used up
 	fsm                       *MetadataFsm
@@ -61,6 +75,13 @@ type Cluster struct {
 	lastMasterZoneForMetaNode string
 	zoneList                  []string
 	followerReadManager       *followerReadManager
+	volAllocPriorities        sync.Map // key: vol name, value: *volAllocPriority
+	auditLog                  *auditLog
+	badPartitionTrend         *badPartitionTrend
+	spaceTrend                *spaceTrend
+	autoRebalancer            *autoRebalancer
+	autoDecommissioner        *autoDecommissioner
+	events                    *eventHub
 }
 
 type followerReadManager struct {
@@ -140,6 +161,14 @@ func newCluster(name string, leaderInfo *LeaderInfo, fsm *MetadataFsm, partition
 	c.partition = partition
 	c.idAlloc = newIDAllocator(c.fsm.store, c.partition)
 	c.nodeSetGrpManager = newNodeSetGrpManager(c)
+	c.auditLog = newAuditLog(cfg.auditLogCapacity)
+	c.badPartitionTrend = newBadPartitionTrend()
+	c.spaceTrend = newSpaceTrend()
+	c.autoRebalancer = newAutoRebalancer()
+	c.autoDecommissioner = newAutoDecommissioner()
+	c.jobs = newJobRegistry(time.Duration(cfg.jobRetentionSec) * time.Second)
+	c.events = newEventHub()
+	setDataNodeAllocStrategy(cfg.dataNodeAllocStrategy)
 	return
 }
 
@@ -158,6 +187,8 @@ func (c *Cluster) scheduleTask() {
 	c.scheduleToReduceReplicaNum()
 	c.scheduleToCheckNodeSetGrpManagerStatus()
 	c.scheduleToCheckFollowerReadCache()
+	c.scheduleToCheckAutoRebalance()
+	c.scheduleToCheckAutoDecommission()
 }
 
 func (c *Cluster) masterAddr() (addr string) {
@@ -201,7 +232,7 @@ func (c *Cluster) scheduleToCheckAutoDataPartitionCreation() {
 		time.Sleep(2 * time.Minute)
 		for {
 			if c.partition != nil && c.partition.IsRaftLeader() {
-				vols := c.copyVols()
+				vols := c.getVolsOrderByAllocPriority()
 				for _, vol := range vols {
 					vol.checkAutoDataPartitionCreation(c)
 				}
@@ -368,8 +399,10 @@ func (c *Cluster) checkDataNodeHeartbeat() {
 	tasks := make([]*proto.AdminTask, 0)
 	c.dataNodes.Range(func(addr, dataNode interface{}) bool {
 		node := dataNode.(*DataNode)
-		node.checkLiveness()
-		task := node.createHeartbeatTask(c.masterAddr())
+		if node.checkLiveness() {
+			c.publishEvent(proto.EventDataNodeInactive, node.Addr, "heartbeat timeout")
+		}
+		task := node.createHeartbeatTask(c.masterAddr(), c.isHeartbeatVerbose())
 		tasks = append(tasks, task)
 		return true
 	})
@@ -380,8 +413,10 @@ func (c *Cluster) checkMetaNodeHeartbeat() {
 	tasks := make([]*proto.AdminTask, 0)
 	c.metaNodes.Range(func(addr, metaNode interface{}) bool {
 		node := metaNode.(*MetaNode)
-		node.checkHeartbeat()
-		task := node.createHeartbeatTask(c.masterAddr())
+		if node.checkHeartbeat() {
+			c.publishEvent(proto.EventMetaNodeInactive, node.Addr, "heartbeat timeout")
+		}
+		task := node.createHeartbeatTask(c.masterAddr(), c.isHeartbeatVerbose())
 		tasks = append(tasks, task)
 		return true
 	})
@@ -557,7 +592,7 @@ func (c *Cluster) addMetaNode(nodeAddr, zoneName string, nodesetId uint64) (id u
 		if nodesetId > 0 && nodesetId != metaNode.ID {
 			return metaNode.ID, fmt.Errorf("addr already in nodeset [%v]", nodeAddr)
 		}
-		return metaNode.ID, nil
+		return metaNode.ID, proto.ErrDuplicateNode
 	}
 	metaNode = newMetaNode(nodeAddr, zoneName, c.Name)
 	zone, err := c.t.getZone(zoneName)
@@ -615,7 +650,7 @@ func (c *Cluster) addDataNode(nodeAddr, zoneName string, nodesetId uint64) (id u
 		if nodesetId > 0 && nodesetId != dataNode.NodeSetID {
 			return dataNode.ID, fmt.Errorf("addr already in nodeset [%v]", nodeAddr)
 		}
-		return dataNode.ID, nil
+		return dataNode.ID, proto.ErrDuplicateNode
 	}
 
 	dataNode = newDataNode(nodeAddr, zoneName, c.Name)
@@ -716,6 +751,299 @@ func (c *Cluster) checkLackReplicaDataPartitions() (lackReplicaDataPartitions []
 	return
 }
 
+func (c *Cluster) checkOverReplicaDataPartitions() (overReplicaDataPartitions []*DataPartition) {
+	overReplicaDataPartitions = make([]*DataPartition, 0)
+	vols := c.copyVols()
+	for _, vol := range vols {
+		vol.dataPartitions.RLock()
+		for _, dp := range vol.dataPartitions.partitions {
+			if int(dp.ReplicaNum) < len(dp.Hosts) {
+				overReplicaDataPartitions = append(overReplicaDataPartitions, dp)
+			}
+		}
+		vol.dataPartitions.RUnlock()
+	}
+	log.LogInfof("clusterID[%v] overReplicaDataPartitions count:[%v]", c.Name, len(overReplicaDataPartitions))
+	return
+}
+
+// The status of the raft group of the masters. The leader knows the replication progress of every peer,
+// a follower knows only itself.
+func (c *Cluster) getRaftStatus() (rs *proto.RaftStatus, err error) {
+	status := c.partition.Status()
+	if status == nil {
+		return nil, fmt.Errorf("the raft partition of the master is stopped")
+	}
+	rs = &proto.RaftStatus{
+		ID:         status.ID,
+		NodeID:     status.NodeID,
+		LeaderID:   status.Leader,
+		LeaderAddr: c.leaderInfo.addr,
+		Term:       status.Term,
+		Index:      status.Index,
+		Commit:     status.Commit,
+		Applied:    status.Applied,
+		State:      status.State,
+		Peers:      make([]*proto.RaftPeerStatus, 0, len(status.Replicas)),
+	}
+	for id, replica := range status.Replicas {
+		peer := &proto.RaftPeerStatus{
+			ID:       id,
+			Addr:     AddrDatabase[id],
+			IsLeader: id == status.Leader,
+			Match:    replica.Match,
+			Commit:   replica.Commit,
+			Next:     replica.Next,
+			State:    replica.State,
+			Active:   replica.Active,
+		}
+		if !replica.LastActive.IsZero() {
+			peer.LastActive = replica.LastActive.Format(time.RFC3339)
+		}
+		rs.Peers = append(rs.Peers, peer)
+	}
+	if len(rs.Peers) == 0 {
+		rs.Peers = append(rs.Peers, &proto.RaftPeerStatus{
+			ID:       status.NodeID,
+			Addr:     AddrDatabase[status.NodeID],
+			IsLeader: status.NodeID == status.Leader,
+			Match:    status.Index,
+			Commit:   status.Commit,
+			State:    status.State,
+			Active:   true,
+		})
+	}
+	sort.Slice(rs.Peers, func(i, j int) bool { return rs.Peers[i].ID < rs.Peers[j].ID })
+	return
+}
+
+// Compact the raft log of this master up to the applied index. The state machine is synced to the store on every
+// apply, so the applied log is not needed to recover, and a follower falling behind is sent a snapshot instead.
+func (c *Cluster) compactRaftLog() (view *proto.RaftSnapshotView, err error) {
+	status := c.partition.Status()
+	if status == nil {
+		return nil, fmt.Errorf("the raft partition of the master is stopped")
+	}
+	if status.RestoringSnapshot {
+		return nil, fmt.Errorf("master[%v] is restoring a snapshot", status.NodeID)
+	}
+	if status.Applied == 0 {
+		return nil, fmt.Errorf("master[%v] has applied no raft log", status.NodeID)
+	}
+	c.partition.Truncate(status.Applied)
+	log.LogWarnf("action[compactRaftLog] master[%v] truncates the raft log, index[%v]", status.NodeID, status.Applied)
+	return &proto.RaftSnapshotView{NodeID: status.NodeID, Index: status.Applied}, nil
+}
+
+// Count the data and the meta partitions of all the volumes by their status.
+func (c *Cluster) getPartitionSummary() (summary *proto.PartitionSummary) {
+	summary = new(proto.PartitionSummary)
+	for _, vol := range c.copyVols() {
+		vol.dataPartitions.RLock()
+		for _, dp := range vol.dataPartitions.partitions {
+			countPartitionStatus(&summary.DataPartitions, dp.Status)
+		}
+		vol.dataPartitions.RUnlock()
+		for _, mp := range vol.cloneMetaPartitionMap() {
+			countPartitionStatus(&summary.MetaPartitions, mp.Status)
+		}
+	}
+	return
+}
+
+func countPartitionStatus(count *proto.PartitionStatusCount, status int8) {
+	switch status {
+	case proto.ReadWrite:
+		count.ReadWrite++
+	case proto.ReadOnly:
+		count.ReadOnly++
+	case proto.Unavailable:
+		count.Unavailable++
+	}
+}
+
+// Get the data and the meta partitions whose live replicas among the hosts are fewer than the replica number,
+// they should be repaired before another failure loses the data.
+func (c *Cluster) getUnderReplicatedPartitions() (report *proto.UnderReplicatedPartitions) {
+	report = &proto.UnderReplicatedPartitions{
+		DataPartitions: make([]*proto.UnderReplicatedPartitionView, 0),
+		MetaPartitions: make([]*proto.UnderReplicatedPartitionView, 0),
+	}
+	for _, vol := range c.copyVols() {
+		vol.dataPartitions.RLock()
+		for _, dp := range vol.dataPartitions.partitions {
+			dp.RLock()
+			live := dp.getLiveReplicasFromHosts(c.cfg.DataPartitionTimeOutSec)
+			if len(live) < int(dp.ReplicaNum) {
+				view := &proto.UnderReplicatedPartitionView{
+					PartitionID:    dp.PartitionID,
+					VolName:        dp.VolName,
+					ReplicaNum:     int(dp.ReplicaNum),
+					LiveReplicaNum: len(live),
+					Hosts:          make([]string, len(dp.Hosts)),
+					LiveHosts:      make([]string, 0, len(live)),
+				}
+				copy(view.Hosts, dp.Hosts)
+				for _, replica := range live {
+					view.LiveHosts = append(view.LiveHosts, replica.Addr)
+				}
+				report.DataPartitions = append(report.DataPartitions, view)
+			}
+			dp.RUnlock()
+		}
+		vol.dataPartitions.RUnlock()
+
+		for _, mp := range vol.cloneMetaPartitionMap() {
+			mp.RLock()
+			liveHosts := make([]string, 0)
+			for _, replica := range mp.getLiveReplicas() {
+				if contains(mp.Hosts, replica.Addr) {
+					liveHosts = append(liveHosts, replica.Addr)
+				}
+			}
+			if len(liveHosts) < int(mp.ReplicaNum) {
+				view := &proto.UnderReplicatedPartitionView{
+					PartitionID:    mp.PartitionID,
+					VolName:        mp.volName,
+					ReplicaNum:     int(mp.ReplicaNum),
+					LiveReplicaNum: len(liveHosts),
+					Hosts:          make([]string, len(mp.Hosts)),
+					LiveHosts:      liveHosts,
+				}
+				copy(view.Hosts, mp.Hosts)
+				report.MetaPartitions = append(report.MetaPartitions, view)
+			}
+			mp.RUnlock()
+		}
+	}
+	sort.Slice(report.DataPartitions, func(i, j int) bool {
+		return report.DataPartitions[i].PartitionID < report.DataPartitions[j].PartitionID
+	})
+	sort.Slice(report.MetaPartitions, func(i, j int) bool {
+		return report.MetaPartitions[i].PartitionID < report.MetaPartitions[j].PartitionID
+	})
+	log.LogInfof("clusterID[%v] underReplicated dataPartitions count:[%v] metaPartitions count:[%v]",
+		c.Name, len(report.DataPartitions), len(report.MetaPartitions))
+	return
+}
+
+// Get the followers that fall behind their leaders most, at most count replicas are returned.
+func (c *Cluster) getLaggingReplicas(count int) (lags []*proto.ReplicaLagView) {
+	lags = make([]*proto.ReplicaLagView, 0)
+	vols := c.copyVols()
+	for _, vol := range vols {
+		for _, dp := range vol.cloneDataPartitionMap() {
+			dpLags, err := dp.getReplicaLags()
+			if err != nil {
+				continue
+			}
+			for _, lag := range dpLags {
+				if !lag.IsLeader && lag.Lag > 0 {
+					lags = append(lags, lag)
+				}
+			}
+		}
+	}
+	sort.Slice(lags, func(i, j int) bool {
+		return lags[i].Lag > lags[j].Lag
+	})
+	if len(lags) > count {
+		lags = lags[:count]
+	}
+	return
+}
+
+// Compare the data partition replicas of the volume on each data node with the fair share,
+// which is the replicas evenly distributed over the active data nodes of the volume's zones.
+// The fair share of the data nodes out of the volume's zones or inactive is 0.
+func (c *Cluster) getVolSkew(vol *Vol) (skew *proto.VolSkewView) {
+	zones := make(map[string]bool)
+	if vol.zoneName != "" {
+		for _, zone := range strings.Split(vol.zoneName, ",") {
+			zones[zone] = true
+		}
+	}
+	counts := make(map[string]int)
+	eligible := make(map[string]bool)
+	c.dataNodes.Range(func(key, value interface{}) bool {
+		dataNode := value.(*DataNode)
+		if dataNode.isActive && (len(zones) == 0 || zones[dataNode.ZoneName]) {
+			counts[dataNode.Addr] = 0
+			eligible[dataNode.Addr] = true
+		}
+		return true
+	})
+	skew = &proto.VolSkewView{VolName: vol.Name, NodeCount: len(eligible), Nodes: make([]*proto.VolSkewNode, 0)}
+	for _, dp := range vol.cloneDataPartitionMap() {
+		dp.RLock()
+		for _, host := range dp.Hosts {
+			counts[host]++
+			skew.ReplicaCount++
+		}
+		dp.RUnlock()
+	}
+	if skew.NodeCount == 0 {
+		return
+	}
+	skew.FairShare = fixedPoint(float64(skew.ReplicaCount)/float64(skew.NodeCount), 2)
+	maxCount := 0
+	for addr, count := range counts {
+		fairShare := 0.0
+		if eligible[addr] {
+			fairShare = skew.FairShare
+		}
+		deviation := fixedPoint(float64(count)-fairShare, 2)
+		skew.Nodes = append(skew.Nodes, &proto.VolSkewNode{Addr: addr, PartitionCount: count, Deviation: deviation})
+		if count > maxCount {
+			maxCount = count
+		}
+		if deviation < 0 {
+			deviation = -deviation
+		}
+		if deviation > skew.MaxDeviation {
+			skew.MaxDeviation = deviation
+		}
+	}
+	if skew.FairShare > 0 {
+		skew.SkewRatio = fixedPoint(float64(maxCount)/skew.FairShare, 2)
+	}
+	sort.Slice(skew.Nodes, func(i, j int) bool {
+		return skew.Nodes[i].Deviation > skew.Nodes[j].Deviation
+	})
+	return
+}
+
+// Summarize the partitions and the space which would be released by deleting the volume, nothing is changed.
+func (c *Cluster) previewVolDeletion(vol *Vol) (preview *proto.VolDeletionPreview) {
+	nodes := make(map[string]bool)
+	preview = &proto.VolDeletionPreview{VolName: vol.Name}
+	for _, dp := range vol.cloneDataPartitionMap() {
+		dp.RLock()
+		preview.DataPartitions++
+		for _, replica := range dp.Replicas {
+			preview.ReclaimableBytes += replica.Used
+		}
+		for _, host := range dp.Hosts {
+			nodes[host] = true
+		}
+		dp.RUnlock()
+	}
+	for _, mp := range vol.cloneMetaPartitionMap() {
+		mp.RLock()
+		preview.MetaPartitions++
+		for _, host := range mp.Hosts {
+			nodes[host] = true
+		}
+		mp.RUnlock()
+	}
+	preview.AffectedNodes = make([]string, 0, len(nodes))
+	for addr := range nodes {
+		preview.AffectedNodes = append(preview.AffectedNodes, addr)
+	}
+	sort.Strings(preview.AffectedNodes)
+	return
+}
+
 func (c *Cluster) getDataPartitionByID(partitionID uint64) (dp *DataPartition, err error) {
 	vols := c.copyVols()
 	for _, vol := range vols {
@@ -782,10 +1110,177 @@ func (c *Cluster) markDeleteVol(name, authKey string) (err error) {
 		vol.Status = normal
 		return proto.ErrPersistenceByRaft
 	}
+	vol.volLock.Lock()
+	vol.markDeleteTime = time.Now()
+	vol.volLock.Unlock()
+	c.publishEvent(proto.EventVolDeleted, name, "")
+	return
+}
+
+// Restore the volume marked deleted before its partitions are reclaimed.
+func (c *Cluster) undeleteVol(name, authKey string) (vol *Vol, err error) {
+	if vol, err = c.getVol(name); err != nil {
+		log.LogErrorf("action[undeleteVol] err[%v]", err)
+		return nil, proto.ErrVolNotExists
+	}
+	if !matchKey(vol.Owner, authKey) {
+		return nil, proto.ErrVolAuthKeyNotMatch
+	}
+	vol.volLock.Lock()
+	defer vol.volLock.Unlock()
+	if vol.Status != markDelete {
+		return nil, fmt.Errorf("vol[%v] is not marked deleted", name)
+	}
+	if vol.reclaiming {
+		return nil, fmt.Errorf("the partitions of vol[%v] have been reclaimed", name)
+	}
+	vol.Status = normal
+	if err = c.syncUpdateVol(vol); err != nil {
+		vol.Status = markDelete
+		return nil, proto.ErrPersistenceByRaft
+	}
+	vol.markDeleteTime = time.Time{}
+	log.LogWarnf("action[undeleteVol] vol[%v] is restored", name)
+	return
+}
+
+// Rename the volume. The volume and all its partitions are persisted with the new name by one raft command,
+// so that the partitions are still loaded into the volume by a new leader.
+func (c *Cluster) renameVol(name, newName, authKey string) (vol *Vol, err error) {
+	c.createVolMutex.Lock()
+	defer c.createVolMutex.Unlock()
+	if vol, err = c.getVol(name); err != nil {
+		log.LogErrorf("action[renameVol] err[%v]", err)
+		return nil, proto.ErrVolNotExists
+	}
+	if !matchKey(vol.Owner, authKey) {
+		return nil, proto.ErrVolAuthKeyNotMatch
+	}
+	if _, err = c.getVol(newName); err == nil {
+		return nil, proto.ErrDuplicateVol
+	}
+	vol.volLock.Lock()
+	defer vol.volLock.Unlock()
+	if vol.Status == markDelete {
+		return nil, fmt.Errorf("vol[%v] is marked deleted", name)
+	}
+	dps := vol.cloneDataPartitionMap()
+	mps := vol.cloneMetaPartitionMap()
+	if err = checkPartitionsToRename(dps, mps); err != nil {
+		return nil, fmt.Errorf("vol[%v] can not be renamed, %v", name, err)
+	}
+	if err = c.syncRenameVol(vol, dps, mps, newName); err != nil {
+		log.LogErrorf("action[renameVol] vol[%v] newName[%v] err[%v]", name, newName, err)
+		return nil, proto.ErrPersistenceByRaft
+	}
+	c.volMutex.Lock()
+	delete(c.vols, name)
+	c.vols[newName] = vol
+	c.volMutex.Unlock()
+	c.volStatInfo.Delete(name)
+	if value, ok := c.volAllocPriorities.Load(name); ok {
+		c.volAllocPriorities.Delete(name)
+		c.volAllocPriorities.Store(newName, value)
+	}
+	log.LogWarnf("action[renameVol] vol[%v] is renamed to [%v]", name, newName)
 	return
 }
 
-func (c *Cluster) batchCreateDataPartition(vol *Vol, reqCount int) (err error) {
+// The partitions being recovered or decommissioned have their views changing, they are renamed once settled.
+func checkPartitionsToRename(dps map[uint64]*DataPartition, mps map[uint64]*MetaPartition) (err error) {
+	for _, dp := range dps {
+		dp.RLock()
+		isRecover := dp.isRecover
+		dp.RUnlock()
+		if isRecover {
+			return fmt.Errorf("data partition[%v] is being recovered", dp.PartitionID)
+		}
+	}
+	for _, mp := range mps {
+		mp.RLock()
+		isRecover := mp.IsRecover
+		mp.RUnlock()
+		if isRecover {
+			return fmt.Errorf("meta partition[%v] is being recovered", mp.PartitionID)
+		}
+	}
+	return
+}
+
+// The names are changed in memory to build the commands, and restored if the commands are not committed.
+func (c *Cluster) syncRenameVol(vol *Vol, dps map[uint64]*DataPartition, mps map[uint64]*MetaPartition, newName string) (err error) {
+	var metadata *RaftCmd
+	oldName := vol.Name
+	setNames := func(name string) {
+		vol.Name = name
+		for _, dp := range dps {
+			dp.Lock()
+			dp.VolName = name
+			dp.Unlock()
+		}
+		for _, mp := range mps {
+			mp.Lock()
+			mp.volName = name
+			mp.Unlock()
+		}
+	}
+	setNames(newName)
+	defer func() {
+		if err != nil {
+			setNames(oldName)
+		}
+	}()
+	cmdMap := make(map[string]*RaftCmd, len(dps)+len(mps)+1)
+	if metadata, err = c.buildVolRaftCmd(opSyncUpdateVol, vol); err != nil {
+		return
+	}
+	cmdMap[metadata.K] = metadata
+	for _, dp := range dps {
+		if metadata, err = c.buildDataPartitionRaftCmd(opSyncUpdateDataPartition, dp); err != nil {
+			return
+		}
+		cmdMap[metadata.K] = metadata
+	}
+	for _, mp := range mps {
+		if metadata, err = c.buildMetaPartitionRaftCmd(opSyncUpdateMetaPartition, mp); err != nil {
+			return
+		}
+		cmdMap[metadata.K] = metadata
+	}
+	return c.syncBatchCommitCmd(cmdMap)
+}
+
+// The nodes keep reporting the partitions of a renamed volume by its former name, such partitions are found by id.
+func (c *Cluster) getReportedDataPartition(volName string, partitionID uint64) (vol *Vol, dp *DataPartition, err error) {
+	if vol, err = c.getVol(volName); err == nil {
+		if dp, err = vol.getDataPartitionByID(partitionID); err == nil {
+			return
+		}
+	}
+	for _, vol = range c.copyVols() {
+		if dp, err = vol.getDataPartitionByID(partitionID); err == nil {
+			return
+		}
+	}
+	return nil, nil, dataPartitionNotFound(partitionID)
+}
+
+func (c *Cluster) getReportedMetaPartition(volName string, partitionID uint64) (vol *Vol, mp *MetaPartition, err error) {
+	if vol, err = c.getVol(volName); err == nil {
+		if mp, err = vol.metaPartition(partitionID); err == nil {
+			return
+		}
+	}
+	for _, vol = range c.copyVols() {
+		if mp, err = vol.metaPartition(partitionID); err == nil {
+			return
+		}
+	}
+	return nil, nil, metaPartitionNotFound(partitionID)
+}
+
+func (c *Cluster) batchCreateDataPartition(vol *Vol, reqCount int) (partitionIDs []uint64, err error) {
+	partitionIDs = make([]uint64, 0, reqCount)
 	for i := 0; i < reqCount; i++ {
 		if c.DisableAutoAllocate {
 			return
@@ -795,13 +1290,51 @@ func (c *Cluster) batchCreateDataPartition(vol *Vol, reqCount int) (err error) {
 		if vol.crossZone && i%5 == 0 {
 			zoneNum = 2
 		}
-		if _, err = c.createDataPartition(vol.Name, zoneNum); err != nil {
+		var dp *DataPartition
+		if dp, err = c.createDataPartition(vol.Name, zoneNum); err != nil {
 			log.LogErrorf("action[batchCreateDataPartition] after create [%v] data partition,occurred error,err[%v]", i, err)
 			break
 		}
+		partitionIDs = append(partitionIDs, dp.PartitionID)
+	}
+	return
+}
+
+// Classify the error returned by createDataPartition so that the operator knows
+// whether to add nodes, free space or relax the placement constraints.
+func (c *Cluster) getDataPartitionCreateFailureReason(err error) (reason string) {
+	if err == nil {
+		return
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, proto.ErrNoDataNodeToCreateDataPartition.Error()),
+		strings.Contains(msg, proto.ErrNoDataNodeToWrite.Error()):
+		reason = proto.DpCreateFailedNoEligibleHosts
+	case strings.Contains(msg, proto.ErrNoZoneToCreateDataPartition.Error()),
+		strings.Contains(msg, proto.ErrNoNodeSetToCreateDataPartition.Error()):
+		reason = proto.DpCreateFailedPlacementViolation
+	default:
+		return proto.DpCreateFailedUnknown
+	}
+	var activeCnt, writableCnt int
+	c.dataNodes.Range(func(addr, node interface{}) bool {
+		dataNode := node.(*DataNode)
+		if dataNode.isActive {
+			activeCnt++
+		}
+		if dataNode.isWriteAble() {
+			writableCnt++
+		}
+		return true
+	})
+	// the nodes are alive but none of them has enough space
+	if activeCnt > 0 && writableCnt == 0 {
+		reason = proto.DpCreateFailedNoCapacity
 	}
 	return
 }
+
 func (c *Cluster) isFaultDomain(vol *Vol) bool {
 	var specifyZoneNeedDomain bool
 	if c.FaultDomain && !vol.crossZone && !c.needFaultDomain {
@@ -840,6 +1373,7 @@ func (c *Cluster) createDataPartition(volName string, zoneNum int) (dp *DataPart
 		targetHosts []string
 		targetPeers []proto.Peer
 		wg          sync.WaitGroup
+		reserved    bool
 	)
 
 	if vol, err = c.getVol(volName); err != nil {
@@ -858,8 +1392,10 @@ func (c *Cluster) createDataPartition(volName string, zoneNum int) (dp *DataPart
 			goto errHandler
 		}
 	}
-	if partitionID, err = c.idAlloc.allocateDataPartitionID(); err != nil {
-		goto errHandler
+	if partitionID, reserved = vol.popReservedDataPartitionID(); !reserved {
+		if partitionID, err = c.idAlloc.allocateDataPartitionID(); err != nil {
+			goto errHandler
+		}
 	}
 	dp = newDataPartition(partitionID, vol.dpReplicaNum, volName, vol.ID)
 	dp.Hosts = targetHosts
@@ -914,6 +1450,9 @@ func (c *Cluster) createDataPartition(volName string, zoneNum int) (dp *DataPart
 	log.LogInfof("action[createDataPartition] success,volName[%v],partitionId[%v]", volName, partitionID)
 	return
 errHandler:
+	if reserved {
+		vol.returnReservedDataPartitionID(partitionID)
+	}
 	err = fmt.Errorf("action[createDataPartition],clusterID[%v] vol[%v] Err:%v ", c.Name, volName, err.Error())
 	log.LogError(errors.Stack(err))
 	Warn(c.Name, err.Error())
@@ -1168,79 +1707,425 @@ func (c *Cluster) getAllMetaPartitionsByMetaNode(addr string) (partitions []*Met
 	return
 }
 
-func (c *Cluster) migrateDataNode(srcAddr, targetAddr string, limit int) (err error) {
-	msg := fmt.Sprintf("action[migrateDataNode], src(%s) migrate to target(%s) cnt(%d)", srcAddr, targetAddr, limit)
-	log.LogWarn(msg)
-
-	src, err := c.dataNode(srcAddr)
-	if err != nil {
+// Simulate the decommission of the data node, or of one disk of it if the diskPath is not empty.
+// The target of every affected partition is chosen as its migration would choose it, the partitions
+// which can not be validated or placed are reported as violations. The choices advance the carry of the nodes.
+func (c *Cluster) preCheckDecommission(addr, diskPath string) (result *proto.DecommissionPreCheck, err error) {
+	var (
+		node       *DataNode
+		partitions []*DataPartition
+	)
+	if node, err = c.dataNode(addr); err != nil {
 		return
 	}
-
-	src.MigrateLock.Lock()
-	defer src.MigrateLock.Unlock()
-
-	partitions := c.getAllDataPartitionByDataNode(src.Addr)
-	toBeOffLinePartitions := make([]*DataPartition, 0)
+	if diskPath != "" {
+		partitions = node.badPartitions(diskPath, c)
+	} else {
+		partitions = c.getAllDataPartitionByDataNode(addr)
+	}
+	result = &proto.DecommissionPreCheck{
+		Addr:           addr,
+		DiskPath:       diskPath,
+		PartitionCount: len(partitions),
+		Violations:     make([]*proto.DecommissionViolation, 0),
+	}
 	for _, dp := range partitions {
-		// two replica can't exist on same node
-		if targetAddr != "" && dp.hasHost(targetAddr) {
+		if _, err = c.getVol(dp.VolName); err != nil {
+			err = nil
 			continue
 		}
-
-		toBeOffLinePartitions = append(toBeOffLinePartitions, dp)
+		violation := &proto.DecommissionViolation{
+			PartitionID: dp.PartitionID,
+			VolName:     dp.VolName,
+			ReplicaNum:  dp.ReplicaNum,
+		}
+		if e := c.validateDecommissionDataPartition(dp, addr); e != nil {
+			violation.Reason = e.Error()
+			result.Violations = append(result.Violations, violation)
+			continue
+		}
+		// the same choice as the migration of the data partition makes
+		if _, e := c.chooseDataPartitionTarget(addr, dp); e != nil {
+			violation.Reason = fmt.Sprintf("no data node for the new replica: %v", e)
+			result.Violations = append(result.Violations, violation)
+		}
 	}
+	result.Passed = len(result.Violations) == 0
+	return
+}
 
-	if len(toBeOffLinePartitions) <= 0 && len(partitions) != 0 {
-		return fmt.Errorf("migrateDataNode no partition can migrate from [%s] to [%s]", srcAddr, targetAddr)
+// Create an empty node set in the zone, the nodes are moved into it by moveNodeToNodeSet.
+func (c *Cluster) createNodeSet(zoneName string, capacity int) (ns *nodeSet, err error) {
+	var (
+		zone *Zone
+		id   uint64
+	)
+	if zone, err = c.t.getZone(zoneName); err != nil {
+		return
 	}
-
-	if limit <= 0 && targetAddr == "" {
-		limit = len(toBeOffLinePartitions)
-	} else if limit <= 0 {
-		limit = defaultMigrateDpCnt
+	if id, err = c.idAlloc.allocateCommonID(); err != nil {
+		return
 	}
-
-	if limit > len(toBeOffLinePartitions) {
-		limit = len(toBeOffLinePartitions)
+	ns = newNodeSet(id, capacity, zone.name)
+	if err = c.syncAddNodeSet(ns); err != nil {
+		log.LogErrorf("action[createNodeSet] zone[%v] nodeSet[%v] err[%v]", zone.name, ns.ID, err)
+		return nil, proto.ErrPersistenceByRaft
 	}
-
-	var wg sync.WaitGroup
-	errChannel := make(chan error, limit)
-	src.ToBeOffline = true
-	src.AvailableSpace = 1
-
-	defer func() {
-		src.ToBeOffline = false
-		close(errChannel)
-	}()
-
-	for i := 0; i < limit; i++ {
-		wg.Add(1)
-		go func(dp *DataPartition) {
-			defer wg.Done()
-			if err1 := c.migrateDataPartition(src.Addr, targetAddr, dp, dataNodeOfflineErr); err1 != nil {
-				errChannel <- err1
-			}
-		}(toBeOffLinePartitions[i])
+	if err = zone.putNodeSet(ns); err != nil {
+		return nil, err
 	}
+	c.addNodeSetGrp(ns, false)
+	log.LogWarnf("action[createNodeSet] zone[%v] nodeSet[%v] capacity[%v]", zone.name, ns.ID, capacity)
+	return
+}
 
-	wg.Wait()
-
-	select {
-	case err = <-errChannel:
-		log.LogErrorf("action[migrateDataNode] clusterID[%v] migrate Node[%s] to [%s] faild, err(%s)",
-			c.Name, src.Addr, targetAddr, err.Error())
+func (c *Cluster) getNodeSetMembers(id uint64) (members *proto.NodeSetMembers, err error) {
+	var ns *nodeSet
+	if ns, err = c.t.getNodeSetByID(id); err != nil {
 		return
-	default:
 	}
+	members = &proto.NodeSetMembers{
+		ID:        ns.ID,
+		ZoneName:  ns.zoneName,
+		Capacity:  ns.Capacity,
+		DataNodes: make([]string, 0),
+		MetaNodes: make([]string, 0),
+	}
+	ns.dataNodes.Range(func(key, value interface{}) bool {
+		members.DataNodes = append(members.DataNodes, key.(string))
+		return true
+	})
+	ns.metaNodes.Range(func(key, value interface{}) bool {
+		members.MetaNodes = append(members.MetaNodes, key.(string))
+		return true
+	})
+	sort.Strings(members.DataNodes)
+	sort.Strings(members.MetaNodes)
+	return
+}
 
-	if limit < len(partitions) {
-		log.LogWarnf("action[migrateDataNode] clusterID[%v] migrate from [%s] to [%s] cnt[%d] success", c.Name, srcAddr, targetAddr, limit)
+// Move the data node or the meta node into another node set of its zone, the membership is persisted with the node.
+// The capacity of the node set is not enlarged, it can be adjusted by updateNodeSetCapacityHandler.
+// The node set id is changed under the lock of the node and persisted out of the locks of the node sets,
+// which are held only to swap the membership once persisted.
+func (c *Cluster) moveNodeToNodeSet(addr, nodeType string, id uint64) (err error) {
+	var src, dst *nodeSet
+	if dst, err = c.t.getNodeSetByID(id); err != nil {
 		return
 	}
-
-	if err = c.syncDeleteDataNode(src); err != nil {
+	switch nodeType {
+	case topologyTypeData:
+		var dataNode *DataNode
+		if dataNode, err = c.dataNode(addr); err != nil {
+			return
+		}
+		dataNode.RLock()
+		zoneName, nodeSetID := dataNode.ZoneName, dataNode.NodeSetID
+		dataNode.RUnlock()
+		if src, err = c.checkNodeSetToMove(addr, zoneName, nodeSetID, dst); err != nil {
+			return
+		}
+		if err = dataNode.setNodeSetIDToMove(src.ID, dst.ID); err != nil {
+			return
+		}
+		if err = c.syncUpdateDataNode(dataNode); err != nil {
+			dataNode.Lock()
+			dataNode.NodeSetID = src.ID
+			dataNode.Unlock()
+			break
+		}
+		unlock := lockNodeSets(src, dst)
+		dst.putDataNode(dataNode)
+		src.deleteDataNode(dataNode)
+		unlock()
+	case topologyTypeMeta:
+		var metaNode *MetaNode
+		if metaNode, err = c.metaNode(addr); err != nil {
+			return
+		}
+		metaNode.RLock()
+		zoneName, nodeSetID := metaNode.ZoneName, metaNode.NodeSetID
+		metaNode.RUnlock()
+		if src, err = c.checkNodeSetToMove(addr, zoneName, nodeSetID, dst); err != nil {
+			return
+		}
+		if err = metaNode.setNodeSetIDToMove(src.ID, dst.ID); err != nil {
+			return
+		}
+		if err = c.syncUpdateMetaNode(metaNode); err != nil {
+			metaNode.Lock()
+			metaNode.NodeSetID = src.ID
+			metaNode.Unlock()
+			break
+		}
+		unlock := lockNodeSets(src, dst)
+		dst.putMetaNode(metaNode)
+		src.deleteMetaNode(metaNode)
+		unlock()
+	default:
+		return fmt.Errorf("node type should be %v or %v, received %v", topologyTypeData, topologyTypeMeta, nodeType)
+	}
+	if err != nil {
+		log.LogErrorf("action[moveNodeToNodeSet] addr[%v] nodeSet[%v] err[%v]", addr, id, err)
+		return proto.ErrPersistenceByRaft
+	}
+	log.LogWarnf("action[moveNodeToNodeSet] %v node[%v] moved from nodeSet[%v] to nodeSet[%v]", nodeType, addr, src.ID, dst.ID)
+	return
+}
+
+func (c *Cluster) checkNodeSetToMove(addr, zoneName string, nodeSetID uint64, dst *nodeSet) (src *nodeSet, err error) {
+	if nodeSetID == dst.ID {
+		return nil, fmt.Errorf("%v is a member of nodeSet[%v] already", addr, dst.ID)
+	}
+	if zoneName != dst.zoneName {
+		return nil, fmt.Errorf("nodeSet[%v] is in zone[%v], not in the zone[%v] of %v", dst.ID, dst.zoneName, zoneName, addr)
+	}
+	return c.t.getNodeSetByID(nodeSetID)
+}
+
+// Set the node set id of the data node unless it is moved by another request meanwhile.
+func (dataNode *DataNode) setNodeSetIDToMove(srcID, dstID uint64) (err error) {
+	dataNode.Lock()
+	defer dataNode.Unlock()
+	if dataNode.NodeSetID != srcID {
+		return fmt.Errorf("%v is moved to nodeSet[%v] meanwhile", dataNode.Addr, dataNode.NodeSetID)
+	}
+	dataNode.NodeSetID = dstID
+	return
+}
+
+// Set the node set id of the meta node unless it is moved by another request meanwhile.
+func (metaNode *MetaNode) setNodeSetIDToMove(srcID, dstID uint64) (err error) {
+	metaNode.Lock()
+	defer metaNode.Unlock()
+	if metaNode.NodeSetID != srcID {
+		return fmt.Errorf("%v is moved to nodeSet[%v] meanwhile", metaNode.Addr, metaNode.NodeSetID)
+	}
+	metaNode.NodeSetID = dstID
+	return
+}
+
+// Lock the node sets in the order of their IDs, so that the moves in the opposite directions do not deadlock.
+func lockNodeSets(a, b *nodeSet) (unlock func()) {
+	if a.ID > b.ID {
+		a, b = b, a
+	}
+	a.Lock()
+	b.Lock()
+	return func() {
+		b.Unlock()
+		a.Unlock()
+	}
+}
+
+// Simulate the failure of all the nodes of a node set, and report the partitions that would lose replicas,
+// the ones that would lose the raft majority, and whether the lost replicas could be rebuilt on the other nodes.
+func (c *Cluster) simulateNodeSetFailure(id uint64) (result *proto.NodeSetFailureSimulation, err error) {
+	var ns *nodeSet
+	if ns, err = c.t.getNodeSetByID(id); err != nil {
+		return
+	}
+	result = &proto.NodeSetFailureSimulation{
+		NodeSetID:          ns.ID,
+		ZoneName:           ns.zoneName,
+		DataNodes:          make([]string, 0),
+		MetaNodes:          make([]string, 0),
+		AffectedPartitions: make([]*proto.PartitionReplicaLoss, 0),
+		CanHeal:            true,
+	}
+	failed := make(map[string]bool)
+	ns.dataNodes.Range(func(key, value interface{}) bool {
+		result.DataNodes = append(result.DataNodes, key.(string))
+		failed[key.(string)] = true
+		return true
+	})
+	ns.metaNodes.Range(func(key, value interface{}) bool {
+		result.MetaNodes = append(result.MetaNodes, key.(string))
+		failed[key.(string)] = true
+		return true
+	})
+	remaining := make(map[string]uint64)
+	c.dataNodes.Range(func(key, value interface{}) bool {
+		dataNode := value.(*DataNode)
+		if !failed[dataNode.Addr] && dataNode.isWriteAble() {
+			remaining[dataNode.Addr] = dataNode.AvailableSpace
+		}
+		return true
+	})
+	metaCandidates := make([]string, 0)
+	c.metaNodes.Range(func(key, value interface{}) bool {
+		metaNode := value.(*MetaNode)
+		if !failed[metaNode.Addr] && metaNode.isWritable() {
+			metaCandidates = append(metaCandidates, metaNode.Addr)
+		}
+		return true
+	})
+	lostReplicas := func(hosts []string) (lost int) {
+		for _, host := range hosts {
+			if failed[host] {
+				lost++
+			}
+		}
+		return
+	}
+	addLoss := func(loss *proto.PartitionReplicaLoss, reason string) {
+		loss.Unavailable = int(loss.ReplicaNum)-loss.LostReplicas < int(loss.ReplicaNum)/2+1
+		if loss.Unavailable {
+			result.UnavailableCount++
+		}
+		if int(loss.ReplicaNum) == loss.LostReplicas {
+			reason = "all the replicas are lost"
+		}
+		if reason != "" {
+			loss.Reason = reason
+			result.CanHeal = false
+		}
+		result.AffectedPartitions = append(result.AffectedPartitions, loss)
+	}
+	for _, vol := range c.allVols() {
+		for _, dp := range vol.cloneDataPartitionMap() {
+			dp.RLock()
+			lost := lostReplicas(dp.Hosts)
+			if lost == 0 {
+				dp.RUnlock()
+				continue
+			}
+			loss := &proto.PartitionReplicaLoss{
+				PartitionID:   dp.PartitionID,
+				PartitionType: "data",
+				VolName:       dp.VolName,
+				ReplicaNum:    dp.ReplicaNum,
+				LostReplicas:  lost,
+			}
+			var reason string
+			for i := 0; i < lost; i++ {
+				target, space := "", uint64(0)
+				for host, avail := range remaining {
+					if !dp.hasHost(host) && avail > vol.dataPartitionSize && avail > space {
+						target, space = host, avail
+					}
+				}
+				if target == "" {
+					reason = fmt.Sprintf("no data node has enough space[%v] for the new replica", vol.dataPartitionSize)
+					break
+				}
+				remaining[target] -= vol.dataPartitionSize
+			}
+			dp.RUnlock()
+			addLoss(loss, reason)
+		}
+		for _, mp := range vol.cloneMetaPartitionMap() {
+			mp.RLock()
+			lost := lostReplicas(mp.Hosts)
+			if lost == 0 {
+				mp.RUnlock()
+				continue
+			}
+			loss := &proto.PartitionReplicaLoss{
+				PartitionID:   mp.PartitionID,
+				PartitionType: "meta",
+				VolName:       mp.volName,
+				ReplicaNum:    mp.ReplicaNum,
+				LostReplicas:  lost,
+			}
+			var (
+				reason     string
+				candidates int
+			)
+			for _, addr := range metaCandidates {
+				if !contains(mp.Hosts, addr) {
+					candidates++
+				}
+			}
+			mp.RUnlock()
+			if candidates < lost {
+				reason = fmt.Sprintf("only %v meta nodes can take the %v new replicas", candidates, lost)
+			}
+			addLoss(loss, reason)
+		}
+	}
+	return
+}
+
+func (c *Cluster) migrateDataNode(srcAddr, targetAddr string, limit int) (jobID string, err error) {
+	msg := fmt.Sprintf("action[migrateDataNode], src(%s) migrate to target(%s) cnt(%d)", srcAddr, targetAddr, limit)
+	log.LogWarn(msg)
+
+	src, err := c.dataNode(srcAddr)
+	if err != nil {
+		return
+	}
+
+	src.MigrateLock.Lock()
+	defer src.MigrateLock.Unlock()
+
+	partitions := c.getAllDataPartitionByDataNode(src.Addr)
+	toBeOffLinePartitions := make([]*DataPartition, 0)
+	for _, dp := range partitions {
+		// two replica can't exist on same node
+		if targetAddr != "" && dp.hasHost(targetAddr) {
+			continue
+		}
+
+		toBeOffLinePartitions = append(toBeOffLinePartitions, dp)
+	}
+
+	if len(toBeOffLinePartitions) <= 0 && len(partitions) != 0 {
+		return "", fmt.Errorf("migrateDataNode no partition can migrate from [%s] to [%s]", srcAddr, targetAddr)
+	}
+
+	if limit <= 0 && targetAddr == "" {
+		limit = len(toBeOffLinePartitions)
+	} else if limit <= 0 {
+		limit = defaultMigrateDpCnt
+	}
+
+	if limit > len(toBeOffLinePartitions) {
+		limit = len(toBeOffLinePartitions)
+	}
+
+	var wg sync.WaitGroup
+	errChannel := make(chan error, limit)
+	src.ToBeOffline = true
+	src.AvailableSpace = 1
+	job := c.jobs.register(proto.JobMigrateDataNode, fmt.Sprintf("%s->%s", srcAddr, targetAddr), limit)
+	jobID = job.id
+
+	defer func() {
+		src.ToBeOffline = false
+		close(errChannel)
+		job.finish(err)
+	}()
+
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func(dp *DataPartition) {
+			defer wg.Done()
+			if err1 := c.migrateDataPartition(src.Addr, targetAddr, dp, dataNodeOfflineErr); err1 != nil {
+				job.fail()
+				errChannel <- err1
+				return
+			}
+			job.complete()
+		}(toBeOffLinePartitions[i])
+	}
+
+	wg.Wait()
+
+	select {
+	case err = <-errChannel:
+		log.LogErrorf("action[migrateDataNode] clusterID[%v] migrate Node[%s] to [%s] faild, err(%s)",
+			c.Name, src.Addr, targetAddr, err.Error())
+		return
+	default:
+	}
+
+	if limit < len(partitions) {
+		log.LogWarnf("action[migrateDataNode] clusterID[%v] migrate from [%s] to [%s] cnt[%d] success", c.Name, srcAddr, targetAddr, limit)
+		return
+	}
+
+	if err = c.syncDeleteDataNode(src); err != nil {
 		msg = fmt.Sprintf("action[migrateDataNode],clusterID[%v] Node[%v] OffLine syncDelNode failed,err[%s]",
 			c.Name, src.Addr, err.Error())
 		Warn(c.Name, msg)
@@ -1256,7 +2141,8 @@ func (c *Cluster) migrateDataNode(srcAddr, targetAddr string, limit int) (err er
 }
 
 func (c *Cluster) decommissionDataNode(dataNode *DataNode) (err error) {
-	return c.migrateDataNode(dataNode.Addr, "", 0)
+	_, err = c.migrateDataNode(dataNode.Addr, "", 0)
+	return
 }
 
 func (c *Cluster) delDataNodeFromCache(dataNode *DataNode) {
@@ -1266,17 +2152,34 @@ func (c *Cluster) delDataNodeFromCache(dataNode *DataNode) {
 }
 
 func (c *Cluster) migrateDataPartition(srcAddr, targetAddr string, dp *DataPartition, errMsg string) (err error) {
+	return c.doMigrateDataPartition(srcAddr, targetAddr, dp, errMsg, true)
+}
+
+// Move the replica of the data partition on srcAddr to targetAddr, the source is not taken as bad,
+// and the recovery of the new replica is tracked under targetAddr as addDataReplica does.
+func (c *Cluster) moveDataPartition(srcAddr, targetAddr string, dp *DataPartition) (err error) {
+	dp.RLock()
+	hasSrc, hasTarget := dp.hasHost(srcAddr), dp.hasHost(targetAddr)
+	dp.RUnlock()
+	if !hasSrc {
+		return fmt.Errorf("vol[%v],data partition[%v] has no replica on [%v]", dp.VolName, dp.PartitionID, srcAddr)
+	}
+	if hasTarget {
+		return fmt.Errorf("vol[%v],data partition[%v] has a replica on [%v] already", dp.VolName, dp.PartitionID, targetAddr)
+	}
+	if _, err = c.dataNode(targetAddr); err != nil {
+		return
+	}
+	return c.doMigrateDataPartition(srcAddr, targetAddr, dp, handleDataPartitionOfflineErr, false)
+}
+
+// Migrate the replica on srcAddr to targetAddr, or to a node chosen by the master if targetAddr is empty.
+// The source is taken as bad if markSrcBad is set, so that the recovery is tracked under it.
+func (c *Cluster) doMigrateDataPartition(srcAddr, targetAddr string, dp *DataPartition, errMsg string, markSrcBad bool) (err error) {
 	var (
-		targetHosts     []string
-		newAddr         string
-		msg             string
-		dataNode        *DataNode
-		zone            *Zone
-		replica         *DataReplica
-		ns              *nodeSet
-		excludeNodeSets []uint64
-		zones           []string
-		excludeZone     string
+		newAddr string
+		msg     string
+		replica *DataReplica
 	)
 
 	dp.RLock()
@@ -1291,50 +2194,9 @@ func (c *Cluster) migrateDataPartition(srcAddr, targetAddr string, dp *DataParti
 		goto errHandler
 	}
 
-	if dataNode, err = c.dataNode(srcAddr); err != nil {
-		goto errHandler
-	}
-
-	if dataNode.ZoneName == "" {
-		err = fmt.Errorf("dataNode[%v] zone is nil", dataNode.Addr)
-		goto errHandler
-	}
-
-	if zone, err = c.t.getZone(dataNode.ZoneName); err != nil {
-		goto errHandler
-	}
-
-	if ns, err = zone.getNodeSet(dataNode.NodeSetID); err != nil {
-		goto errHandler
-	}
-
-	if targetAddr != "" {
-		targetHosts = []string{targetAddr}
-	} else if targetHosts, _, err = ns.getAvailDataNodeHosts(dp.Hosts, 1); err != nil {
-		if _, ok := c.vols[dp.VolName]; !ok {
-			log.LogWarnf("clusterID[%v] partitionID:%v  on Node:%v offline failed,PersistenceHosts:[%v]",
-				c.Name, dp.PartitionID, srcAddr, dp.Hosts)
-			return
-		}
-
-		if c.isFaultDomain(c.vols[dp.VolName]) {
-			log.LogErrorf("clusterID[%v] partitionID:%v  on Node:%v is banlance zone,PersistenceHosts:[%v]",
-				c.Name, dp.PartitionID, srcAddr, dp.Hosts)
-			return
-		}
-		// select data nodes from the other node set in same zone
-		excludeNodeSets = append(excludeNodeSets, ns.ID)
-		if targetHosts, _, err = zone.getAvailDataNodeHosts(excludeNodeSets, dp.Hosts, 1); err != nil {
-			// select data nodes from the other zone
-			zones = dp.getLiveZones(srcAddr)
-			if len(zones) == 0 {
-				excludeZone = zone.name
-			} else {
-				excludeZone = zones[0]
-			}
-			if targetHosts, _, err = c.chooseTargetDataNodes(excludeZone, excludeNodeSets, dp.Hosts, 1, 1, ""); err != nil {
-				goto errHandler
-			}
+	if targetAddr == "" {
+		if targetAddr, err = c.chooseDataPartitionTarget(srcAddr, dp); err != nil {
+			goto errHandler
 		}
 	}
 
@@ -1342,14 +2204,18 @@ func (c *Cluster) migrateDataPartition(srcAddr, targetAddr string, dp *DataParti
 		goto errHandler
 	}
 
-	newAddr = targetHosts[0]
+	newAddr = targetAddr
 	if err = c.addDataReplica(dp, newAddr); err != nil {
 		goto errHandler
 	}
 
 	dp.Status = proto.ReadOnly
 	dp.isRecover = true
-	c.putBadDataPartitionIDs(replica, srcAddr, dp.PartitionID)
+	if markSrcBad {
+		c.putBadDataPartitionIDs(replica, srcAddr, dp.PartitionID)
+	} else {
+		c.putBadDataPartitionIDs(nil, newAddr, dp.PartitionID)
+	}
 
 	dp.RLock()
 	c.syncUpdateDataPartition(dp)
@@ -1386,6 +2252,122 @@ func (c *Cluster) decommissionDataPartition(offlineAddr string, dp *DataPartitio
 	return c.migrateDataPartition(offlineAddr, "", dp, errMsg)
 }
 
+// Choose the data node taking over the replica on srcAddr of the data partition as migrateDataPartition does.
+// Nothing is moved, but the choice advances the carry of the nodes as every selection does.
+func (c *Cluster) chooseDecommissionTarget(srcAddr string, dp *DataPartition) (targetAddr string, err error) {
+	dp.RLock()
+	hasSrc := dp.hasHost(srcAddr)
+	dp.RUnlock()
+	if !hasSrc {
+		return "", fmt.Errorf("vol[%v],data partition[%v] has no replica on [%v]", dp.VolName, dp.PartitionID, srcAddr)
+	}
+	if err = c.validateDecommissionDataPartition(dp, srcAddr); err != nil {
+		return
+	}
+	return c.chooseDataPartitionTarget(srcAddr, dp)
+}
+
+// Choose the data node for the replica on srcAddr of the data partition, from the node set of srcAddr first,
+// then from the other node sets of its zone, and then from the other zones unless the volume is in the fault domain.
+func (c *Cluster) chooseDataPartitionTarget(srcAddr string, dp *DataPartition) (targetAddr string, err error) {
+	var (
+		dataNode        *DataNode
+		zone            *Zone
+		ns              *nodeSet
+		vol             *Vol
+		targetHosts     []string
+		excludeNodeSets []uint64
+		excludeZone     string
+	)
+	dp.RLock()
+	hosts := make([]string, len(dp.Hosts))
+	copy(hosts, dp.Hosts)
+	dp.RUnlock()
+	if dataNode, err = c.dataNode(srcAddr); err != nil {
+		return
+	}
+	if dataNode.ZoneName == "" {
+		return "", fmt.Errorf("dataNode[%v] zone is nil", dataNode.Addr)
+	}
+	if zone, err = c.t.getZone(dataNode.ZoneName); err != nil {
+		return
+	}
+	if ns, err = zone.getNodeSet(dataNode.NodeSetID); err != nil {
+		return
+	}
+	if targetHosts, _, err = ns.getAvailDataNodeHosts(hosts, 1); err == nil {
+		return targetHosts[0], nil
+	}
+	if vol, err = c.getVol(dp.VolName); err != nil {
+		return
+	}
+	if c.isFaultDomain(vol) {
+		return "", fmt.Errorf("no available data node in node set[%v] of the fault domain volume", ns.ID)
+	}
+	// select data nodes from the other node set in same zone
+	excludeNodeSets = append(excludeNodeSets, ns.ID)
+	if targetHosts, _, err = zone.getAvailDataNodeHosts(excludeNodeSets, hosts, 1); err == nil {
+		return targetHosts[0], nil
+	}
+	// select data nodes from the other zone
+	if zones := dp.getLiveZones(srcAddr); len(zones) == 0 {
+		excludeZone = zone.name
+	} else {
+		excludeZone = zones[0]
+	}
+	if targetHosts, _, err = c.chooseTargetDataNodes(excludeZone, excludeNodeSets, hosts, 1, 1, ""); err != nil {
+		return
+	}
+	return targetHosts[0], nil
+}
+
+// Preview where the replicas on srcAddr of the data partitions would move to if they were decommissioned.
+// Each target is chosen by the current state, the moves planned before do not count.
+func (c *Cluster) dryRunDecommission(srcAddr, diskPath string, partitions []*DataPartition) (result *proto.DecommissionDryRun) {
+	result = &proto.DecommissionDryRun{Addr: srcAddr, DiskPath: diskPath, Moves: make([]*proto.DataPartitionMove, 0, len(partitions))}
+	for _, dp := range partitions {
+		move := &proto.DataPartitionMove{PartitionID: dp.PartitionID, VolName: dp.VolName, SrcAddr: srcAddr}
+		if targetAddr, err := c.chooseDecommissionTarget(srcAddr, dp); err != nil {
+			move.Err = err.Error()
+			result.Unplaceable++
+		} else {
+			move.TargetAddr = targetAddr
+		}
+		result.Moves = append(result.Moves, move)
+	}
+	return
+}
+
+// Decommission the data partitions on the offlineAddr, at most concurrency of them at the same time.
+// A failed data partition does not stop the others, the results are in the same order as the IDs.
+func (c *Cluster) batchDecommissionDataPartitions(offlineAddr string, partitionIDs []uint64, concurrency int) (results []*proto.DataPartitionDecommissionResult) {
+	results = make([]*proto.DataPartitionDecommissionResult, len(partitionIDs))
+	tokens := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, partitionID := range partitionIDs {
+		results[i] = &proto.DataPartitionDecommissionResult{PartitionID: partitionID, Success: true}
+		wg.Add(1)
+		tokens <- struct{}{}
+		go func(result *proto.DataPartitionDecommissionResult) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			dp, err := c.getDataPartitionByID(result.PartitionID)
+			if err == nil {
+				err = c.decommissionDataPartition(offlineAddr, dp, handleDataPartitionOfflineErr)
+			}
+			if err != nil {
+				log.LogErrorf("action[batchDecommissionDataPartitions] partition[%v] on [%v] err[%v]", result.PartitionID, offlineAddr, err)
+				result.Success = false
+				result.Msg = err.Error()
+			}
+		}(results[i])
+	}
+	wg.Wait()
+	return
+}
+
 func (c *Cluster) validateDecommissionDataPartition(dp *DataPartition, offlineAddr string) (err error) {
 	dp.RLock()
 	defer dp.RUnlock()
@@ -1431,6 +2413,45 @@ func (c *Cluster) addDataReplica(dp *DataPartition, addr string) (err error) {
 	return
 }
 
+// Choose a data node for a new replica of the data partition, from the node set of its first host,
+// then from the other node sets of the zone, then from the other zones, as migrateDataPartition does.
+func (c *Cluster) chooseDataReplicaAddr(dp *DataPartition) (addr string, err error) {
+	var (
+		dataNode        *DataNode
+		zone            *Zone
+		ns              *nodeSet
+		targetHosts     []string
+		excludeNodeSets []uint64
+	)
+	dp.RLock()
+	hosts := make([]string, len(dp.Hosts))
+	copy(hosts, dp.Hosts)
+	dp.RUnlock()
+	if len(hosts) == 0 {
+		return "", fmt.Errorf("vol[%v],data partition[%v] has no host", dp.VolName, dp.PartitionID)
+	}
+	if dataNode, err = c.dataNode(hosts[0]); err != nil {
+		return
+	}
+	if zone, err = c.t.getZone(dataNode.ZoneName); err != nil {
+		return
+	}
+	if ns, err = zone.getNodeSet(dataNode.NodeSetID); err != nil {
+		return
+	}
+	if targetHosts, _, err = ns.getAvailDataNodeHosts(hosts, 1); err == nil {
+		return targetHosts[0], nil
+	}
+	excludeNodeSets = append(excludeNodeSets, ns.ID)
+	if targetHosts, _, err = zone.getAvailDataNodeHosts(excludeNodeSets, hosts, 1); err == nil {
+		return targetHosts[0], nil
+	}
+	if targetHosts, _, err = c.chooseTargetDataNodes(zone.name, excludeNodeSets, hosts, 1, 1, ""); err != nil {
+		return
+	}
+	return targetHosts[0], nil
+}
+
 func (c *Cluster) buildAddDataPartitionRaftMemberTaskAndSyncSendTask(dp *DataPartition, addPeer proto.Peer, leaderAddr string) (resp *proto.Packet, err error) {
 	defer func() {
 		var resultCode uint8
@@ -1668,8 +2689,14 @@ func (c *Cluster) putBadMetaPartitions(addr string, partitionID uint64) {
 	if ok {
 		newBadPartitionIDs = badPartitionIDs.([]uint64)
 	}
+	for _, id := range newBadPartitionIDs {
+		if id == partitionID {
+			return
+		}
+	}
 	newBadPartitionIDs = append(newBadPartitionIDs, partitionID)
 	c.BadMetaPartitionIds.Store(addr, newBadPartitionIDs)
+	c.publishEvent(proto.EventBadMetaPartition, addr, fmt.Sprintf("meta partition[%v]", partitionID))
 }
 
 func (c *Cluster) getBadMetaPartitionsView() (bmpvs []badPartitionView) {
@@ -1704,6 +2731,7 @@ func (c *Cluster) putBadDataPartitionIDs(replica *DataReplica, addr string, part
 	}
 	newBadPartitionIDs = append(newBadPartitionIDs, partitionID)
 	c.BadDataPartitionIds.Store(key, newBadPartitionIDs)
+	c.publishEvent(proto.EventBadDataPartition, key, fmt.Sprintf("data partition[%v]", partitionID))
 }
 
 func (c *Cluster) getBadDataPartitionsView() (bpvs []badPartitionView) {
@@ -1721,13 +2749,13 @@ func (c *Cluster) getBadDataPartitionsView() (bpvs []badPartitionView) {
 	return
 }
 
-func (c *Cluster) migrateMetaNode(srcAddr, targetAddr string, limit int) (err error) {
+func (c *Cluster) migrateMetaNode(srcAddr, targetAddr string, limit int) (jobID string, err error) {
 	msg := fmt.Sprintf("action[migrateMetaNode],clusterID[%v] migrate from Node[%v] to [%s] begin", c.Name, srcAddr, targetAddr)
 	log.LogWarn(msg)
 
 	metaNode, err := c.metaNode(srcAddr)
 	if err != nil {
-		return err
+		return
 	}
 
 	metaNode.MigrateLock.Lock()
@@ -1744,7 +2772,7 @@ func (c *Cluster) migrateMetaNode(srcAddr, targetAddr string, limit int) (err er
 	}
 
 	if len(toBeOfflineMps) <= 0 && len(partitions) != 0 {
-		return fmt.Errorf("migrateMataNode no partition can migrate from [%s] to [%s]", srcAddr, targetAddr)
+		return "", fmt.Errorf("migrateMataNode no partition can migrate from [%s] to [%s]", srcAddr, targetAddr)
 	}
 
 	if limit <= 0 && targetAddr == "" { // default all mps
@@ -1761,10 +2789,13 @@ func (c *Cluster) migrateMetaNode(srcAddr, targetAddr string, limit int) (err er
 	metaNode.ToBeOffline = true
 	metaNode.MaxMemAvailWeight = 1
 	errChannel := make(chan error, limit)
+	job := c.jobs.register(proto.JobMigrateMetaNode, fmt.Sprintf("%s->%s", srcAddr, targetAddr), limit)
+	jobID = job.id
 
 	defer func() {
 		metaNode.ToBeOffline = false
 		close(errChannel)
+		job.finish(err)
 	}()
 
 	for idx := 0; idx < limit; idx++ {
@@ -1772,8 +2803,11 @@ func (c *Cluster) migrateMetaNode(srcAddr, targetAddr string, limit int) (err er
 		go func(mp *MetaPartition) {
 			defer wg.Done()
 			if err1 := c.migrateMetaPartition(srcAddr, targetAddr, mp); err1 != nil {
+				job.fail()
 				errChannel <- err1
+				return
 			}
+			job.complete()
 		}(toBeOfflineMps[idx])
 	}
 
@@ -1806,7 +2840,8 @@ func (c *Cluster) migrateMetaNode(srcAddr, targetAddr string, limit int) (err er
 }
 
 func (c *Cluster) decommissionMetaNode(metaNode *MetaNode) (err error) {
-	return c.migrateMetaNode(metaNode.Addr, "", 0)
+	_, err = c.migrateMetaNode(metaNode.Addr, "", 0)
+	return
 }
 
 func (c *Cluster) deleteMetaNodeFromCache(metaNode *MetaNode) {
@@ -1827,6 +2862,10 @@ func (c *Cluster) updateVol(name, authKey string, newArgs *VolVarargs) (err erro
 		oldDescription    string
 		oldDpSelectorName string
 		oldDpSelectorParm string
+		oldTags           map[string]string
+		oldBandwidthLimit uint64
+		oldIopsLimit      uint64
+		oldHistory        []*proto.VolCapacityChange
 		volUsedSpace      uint64
 		newZoneName       string
 	)
@@ -1842,9 +2881,9 @@ func (c *Cluster) updateVol(name, authKey string, newArgs *VolVarargs) (err erro
 		return proto.ErrVolAuthKeyNotMatch
 	}
 	volUsedSpace = vol.totalUsedSpace()
-	if float64(newArgs.capacity*util.GB) < float64(volUsedSpace)*1.2 {
-		err = fmt.Errorf("capacity[%v] has to be 20 percent larger than the used space[%v]", newArgs.capacity,
-			volUsedSpace/util.GB)
+	if newArgs.capacity < vol.Capacity && newArgs.capacity*util.GB < volUsedSpace && !newArgs.force {
+		err = fmt.Errorf("capacity[%v] can not be shrunk below the used space[%vGB], set force to override",
+			newArgs.capacity, volUsedSpace/util.GB)
 		goto errHandler
 	}
 	if newArgs.dpReplicaNum > vol.dpReplicaNum {
@@ -1865,7 +2904,14 @@ func (c *Cluster) updateVol(name, authKey string, newArgs *VolVarargs) (err erro
 	oldDescription = vol.description
 	oldDpSelectorName = vol.dpSelectorName
 	oldDpSelectorParm = vol.dpSelectorParm
+	oldTags = vol.tags
+	oldBandwidthLimit = vol.bandwidthLimit
+	oldIopsLimit = vol.iopsLimit
+	oldHistory = vol.capacityHistory
 
+	if newArgs.capacity != vol.Capacity {
+		vol.recordCapacityChange(vol.Capacity, newArgs.capacity, newArgs.caller)
+	}
 	vol.zoneName = newArgs.zoneName
 	vol.Capacity = newArgs.capacity
 	vol.FollowerRead = newArgs.followerRead
@@ -1879,6 +2925,9 @@ func (c *Cluster) updateVol(name, authKey string, newArgs *VolVarargs) (err erro
 	}
 	vol.dpSelectorName = newArgs.dpSelectorName
 	vol.dpSelectorParm = newArgs.dpSelectorParm
+	vol.tags = newArgs.tags
+	vol.bandwidthLimit = newArgs.bandwidthLimit
+	vol.iopsLimit = newArgs.iopsLimit
 
 	if err = c.syncUpdateVol(vol); err != nil {
 		vol.Capacity = oldCapacity
@@ -1889,6 +2938,10 @@ func (c *Cluster) updateVol(name, authKey string, newArgs *VolVarargs) (err erro
 		vol.description = oldDescription
 		vol.dpSelectorName = oldDpSelectorName
 		vol.dpSelectorParm = oldDpSelectorParm
+		vol.tags = oldTags
+		vol.bandwidthLimit = oldBandwidthLimit
+		vol.iopsLimit = oldIopsLimit
+		vol.capacityHistory = oldHistory
 
 		log.LogErrorf("action[updateVol] vol[%v] err[%v]", name, err)
 		err = proto.ErrPersistenceByRaft
@@ -1902,6 +2955,67 @@ errHandler:
 	return
 }
 
+// Reserve count data partition ids for the volume, which are consumed by the data partitions created later.
+// The id taken by a data partition failing to be created is reserved again. The reservation is kept in memory
+// on the leader only, the ids not consumed yet are lost if the leader changes, and never used by other volumes.
+func (c *Cluster) reserveDataPartitionIDs(name string, count uint64) (idRange *proto.PartitionIDRange, err error) {
+	var (
+		vol        *Vol
+		start, end uint64
+	)
+	if vol, err = c.getVol(name); err != nil {
+		return nil, proto.ErrVolNotExists
+	}
+	if start, end, err = c.idAlloc.allocateDataPartitionIDs(count); err != nil {
+		return nil, proto.ErrPersistenceByRaft
+	}
+	vol.addReservedDataPartitionIDs(start, end)
+	log.LogInfof("action[reserveDataPartitionIDs] vol[%v] reserved data partition ids[%v,%v]", name, start, end)
+	return &proto.PartitionIDRange{VolName: name, Start: start, End: end}, nil
+}
+
+func (c *Cluster) setVolIOPriorityClass(name, class string) (err error) {
+	var vol *Vol
+	if vol, err = c.getVol(name); err != nil {
+		return proto.ErrVolNotExists
+	}
+	vol.volLock.Lock()
+	defer vol.volLock.Unlock()
+	oldClass := vol.ioPriorityClass
+	vol.setIOPriorityClass(class)
+	if err = c.syncUpdateVol(vol); err != nil {
+		log.LogErrorf("action[setVolIOPriorityClass] vol[%v] err[%v]", name, err)
+		vol.setIOPriorityClass(oldClass)
+		return proto.ErrPersistenceByRaft
+	}
+	vol.dataPartitions.updateResponseCache(true, 0)
+	return
+}
+
+func (c *Cluster) setVolReadOnly(name string, readOnly bool) (err error) {
+	var vol *Vol
+	if vol, err = c.getVol(name); err != nil {
+		return proto.ErrVolNotExists
+	}
+	vol.volLock.Lock()
+	oldReadOnly := vol.readOnly
+	vol.readOnly = readOnly
+	if err = c.syncUpdateVol(vol); err != nil {
+		log.LogErrorf("action[setVolReadOnly] vol[%v] err[%v]", name, err)
+		vol.readOnly = oldReadOnly
+		vol.volLock.Unlock()
+		return proto.ErrPersistenceByRaft
+	}
+	vol.volLock.Unlock()
+	if readOnly {
+		vol.freezePartitions()
+	}
+	vol.dataPartitions.updateResponseCache(true, 0)
+	vol.updateViewCache(c)
+	log.LogWarnf("action[setVolReadOnly] vol[%v] readOnly[%v]", name, readOnly)
+	return
+}
+
 func (c *Cluster) checkVolInfo(name string, crossZone bool, zoneName string) (newZoneName string, err error) {
 	newZoneName = zoneName
 	if crossZone {
@@ -1915,11 +3029,12 @@ func (c *Cluster) checkVolInfo(name string, crossZone bool, zoneName string) (ne
 		// len(c.t.zones) is 0, or set false in check status
 		if newZoneName == "" {
 			if !c.needFaultDomain {
-				if c.t.getZone(DefaultZoneName); err != nil {
-					return newZoneName, fmt.Errorf("action[checkVolInfo] the vol is not cross zone and didn't set zone name,but there's no default zone")
+				defaultZone := c.getDefaultZone()
+				if _, err = c.t.getZone(defaultZone); err != nil {
+					return newZoneName, fmt.Errorf("action[checkVolInfo] the vol is not cross zone and didn't set zone name,but there's no default zone[%v]", defaultZone)
 				}
-				log.LogInfof("action[checkVolInfo] vol [%v] use default zone", name)
-				newZoneName = DefaultZoneName
+				log.LogInfof("action[checkVolInfo] vol [%v] use default zone[%v]", name, defaultZone)
+				newZoneName = defaultZone
 			}
 		} else {
 			if c.FaultDomain {
@@ -1946,9 +3061,11 @@ func (c *Cluster) checkVolInfo(name string, crossZone bool, zoneName string) (ne
 
 // Create a new volume.
 // By default we create 3 meta partitions and 10 data partitions during initialization.
+// The tags and the QoS limits are persisted along with the volume.
 func (c *Cluster) createVol(name, owner, zoneName, description string,
 	mpCount, dpReplicaNum, size, capacity int,
-	followerRead, authenticate, crossZone, defaultPriority bool) (vol *Vol, err error) {
+	followerRead, authenticate, crossZone, defaultPriority bool,
+	tags map[string]string, bandwidthLimit, iopsLimit uint64) (vol *Vol, err error) {
 	var (
 		dataPartitionSize       uint64
 		readWriteDataPartitions int
@@ -1972,7 +3089,10 @@ func (c *Cluster) createVol(name, owner, zoneName, description string,
 	if vol, err = c.doCreateVol(name, owner, zoneName, description,
 		dataPartitionSize, uint64(capacity), dpReplicaNum,
 		followerRead, authenticate, crossZone,
-		defaultPriority); err != nil {
+		defaultPriority, tags, bandwidthLimit, iopsLimit); err != nil {
+		if err == proto.ErrDuplicateVol {
+			return
+		}
 		goto errHandler
 	}
 	if err = vol.initMetaPartitions(c, mpCount); err != nil {
@@ -1992,6 +3112,7 @@ func (c *Cluster) createVol(name, owner, zoneName, description string,
 	vol.dataPartitions.readableAndWritableCnt = readWriteDataPartitions
 	vol.updateViewCache(c)
 	log.LogInfof("action[createVol] vol[%v],readableAndWritableCnt[%v]", name, readWriteDataPartitions)
+	c.publishEvent(proto.EventVolCreated, name, "")
 	return
 
 errHandler:
@@ -2001,16 +3122,73 @@ errHandler:
 	return
 }
 
+// importVol recreates a volume from an exported spec, the data of the volume is not included.
+func (c *Cluster) importVol(spec *proto.VolSpec) (vol *Vol, err error) {
+	if vol, err = c.createVol(spec.Name, spec.Owner, spec.ZoneName, spec.Description,
+		spec.MpCount, int(spec.DpReplicaNum), int(spec.DataPartitionSize), int(spec.Capacity),
+		spec.FollowerRead, spec.Authenticate, spec.CrossZone, spec.DefaultPriority,
+		spec.Tags, spec.BandwidthLimit, spec.IopsLimit); err != nil {
+		return
+	}
+	if spec.DpSelectorName == "" && spec.DpSelectorParm == "" && spec.IOPriorityClass == "" && !spec.ReadOnly {
+		return
+	}
+	vol.volLock.Lock()
+	oldIOPriorityClass := vol.ioPriorityClass
+	vol.dpSelectorName = spec.DpSelectorName
+	vol.dpSelectorParm = spec.DpSelectorParm
+	if spec.IOPriorityClass != "" {
+		vol.setIOPriorityClass(spec.IOPriorityClass)
+	}
+	vol.readOnly = spec.ReadOnly
+	if err = c.syncUpdateVol(vol); err != nil {
+		vol.dpSelectorName = ""
+		vol.dpSelectorParm = ""
+		vol.setIOPriorityClass(oldIOPriorityClass)
+		vol.readOnly = false
+		vol.volLock.Unlock()
+		log.LogErrorf("action[importVol] vol[%v] update dp selector failed,err[%v]", vol.Name, err)
+		return nil, proto.ErrPersistenceByRaft
+	}
+	vol.volLock.Unlock()
+	// the partitions of the read only vol are frozen as setVolReadOnly does
+	if spec.ReadOnly {
+		vol.freezePartitions()
+		vol.dataPartitions.updateResponseCache(true, 0)
+		vol.updateViewCache(c)
+	}
+	return
+}
+
+// Check if the volume exists with the replica number, the data partition size and the capacity
+// of a creation, so that retrying the creation succeeds.
+func (c *Cluster) isVolCreatedWith(name string, dpReplicaNum, size, capacity int) bool {
+	vol, err := c.getVol(name)
+	if err != nil || vol.Status == markDelete {
+		return false
+	}
+	dataPartitionSize := uint64(size) * util.GB
+	if size <= 0 {
+		dataPartitionSize = util.DefaultDataPartitionSize
+	}
+	if dpReplicaNum < defaultReplicaNum {
+		dpReplicaNum = defaultReplicaNum
+	}
+	return int(vol.dpReplicaNum) == dpReplicaNum && vol.dataPartitionSize == dataPartitionSize && vol.Capacity == uint64(capacity)
+}
+
 func (c *Cluster) doCreateVol(name, owner, zoneName, description string,
 	dpSize, capacity uint64, dpReplicaNum int,
 	followerRead, authenticate, crossZone,
-	defaultPriority bool) (vol *Vol, err error) {
+	defaultPriority bool, tags map[string]string, bandwidthLimit, iopsLimit uint64) (vol *Vol, err error) {
 	var id uint64
 	c.createVolMutex.Lock()
 	defer c.createVolMutex.Unlock()
 	var createTime = time.Now().Unix() // record unix seconds of volume create time
 	if _, err = c.getVol(name); err == nil {
-		err = proto.ErrDuplicateVol
+		return nil, proto.ErrDuplicateVol
+	}
+	if err = c.checkMaxVolumes(); err != nil {
 		goto errHandler
 	}
 	id, err = c.idAlloc.allocateCommonID()
@@ -2021,6 +3199,8 @@ func (c *Cluster) doCreateVol(name, owner, zoneName, description string,
 		capacity, uint8(dpReplicaNum), defaultReplicaNum,
 		followerRead, authenticate, crossZone,
 		defaultPriority, createTime, description)
+	vol.tags = tags
+	vol.bandwidthLimit, vol.iopsLimit = bandwidthLimit, iopsLimit
 	// refresh oss secure
 	vol.refreshOSSSecure()
 	if err = c.syncAddVol(vol); err != nil {
@@ -2068,6 +3248,19 @@ func (c *Cluster) updateInodeIDRange(volName string, start uint64) (err error) {
 	return
 }
 
+// Split the meta partition at the inode, a new meta partition is created for the inodes above it.
+// The inode should be in [Start, End) of the partition and not less than its MaxInodeID.
+func (c *Cluster) splitMetaPartition(mp *MetaPartition, inode uint64) (nextMp *MetaPartition, err error) {
+	var vol *Vol
+	if vol, err = c.getVol(mp.volName); err != nil {
+		return nil, proto.ErrVolNotExists
+	}
+	if nextMp, err = vol.splitMetaPartitionAt(c, mp, inode); err != nil {
+		log.LogErrorf("action[splitMetaPartition] vol[%v] mp[%v] inode[%v] err[%v]", mp.volName, mp.PartitionID, inode, err)
+	}
+	return
+}
+
 // Choose the target hosts from the available zones and meta nodes.
 func (c *Cluster) chooseTargetMetaHosts(
 	excludeZone []string, excludeNodeSets []uint64,
@@ -2180,7 +3373,7 @@ func (c *Cluster) allDataNodes() (dataNodes []proto.NodeView) {
 	dataNodes = make([]proto.NodeView, 0)
 	c.dataNodes.Range(func(addr, node interface{}) bool {
 		dataNode := node.(*DataNode)
-		dataNodes = append(dataNodes, proto.NodeView{Addr: dataNode.Addr, Status: dataNode.isActive, ID: dataNode.ID, IsWritable: dataNode.isWriteAble()})
+		dataNodes = append(dataNodes, dataNode.toNodeView())
 		return true
 	})
 	return
@@ -2190,12 +3383,27 @@ func (c *Cluster) allMetaNodes() (metaNodes []proto.NodeView) {
 	metaNodes = make([]proto.NodeView, 0)
 	c.metaNodes.Range(func(addr, node interface{}) bool {
 		metaNode := node.(*MetaNode)
-		metaNodes = append(metaNodes, proto.NodeView{ID: metaNode.ID, Addr: metaNode.Addr, Status: metaNode.IsActive, IsWritable: metaNode.isWritable()})
+		metaNodes = append(metaNodes, metaNode.toNodeView())
 		return true
 	})
 	return
 }
 
+func (c *Cluster) volCount() int {
+	c.volMutex.RLock()
+	defer c.volMutex.RUnlock()
+	return len(c.vols)
+}
+
+// MaxVolumes limits the number of volumes in the cluster, 0 means unlimited.
+func (c *Cluster) checkMaxVolumes() (err error) {
+	maxVolumes := atomic.LoadUint64(&c.MaxVolumes)
+	if count := c.volCount(); maxVolumes > 0 && uint64(count) >= maxVolumes {
+		return fmt.Errorf("the number of volumes[%v] has reached the limit[%v]", count, maxVolumes)
+	}
+	return
+}
+
 func (c *Cluster) allVolNames() (vols []string) {
 	vols = make([]string, 0)
 	c.volMutex.RLock()
@@ -2318,6 +3526,175 @@ func (c *Cluster) setDisableAutoAllocate(disableAutoAllocate bool) (err error) {
 	return
 }
 
+func (c *Cluster) setMaintenanceMode(enable bool) (err error) {
+	oldMode := c.MaintenanceMode
+	c.MaintenanceMode = enable
+	if err = c.syncPutCluster(); err != nil {
+		log.LogErrorf("action[setMaintenanceMode] err[%v]", err)
+		c.MaintenanceMode = oldMode
+		err = proto.ErrPersistenceByRaft
+		return
+	}
+	log.LogWarnf("action[setMaintenanceMode] clusterID[%v] maintenance mode is set to %v", c.Name, enable)
+	return
+}
+
+func (c *Cluster) getDefaultZone() string {
+	c.zoneMutex.RLock()
+	defer c.zoneMutex.RUnlock()
+	if c.DefaultZone == "" {
+		return DefaultZoneName
+	}
+	return c.DefaultZone
+}
+
+func (c *Cluster) setDefaultZone(zoneName string) (err error) {
+	if _, err = c.t.getZone(zoneName); err != nil {
+		return
+	}
+	c.zoneMutex.Lock()
+	defer c.zoneMutex.Unlock()
+	oldZone := c.DefaultZone
+	c.DefaultZone = zoneName
+	if err = c.syncPutCluster(); err != nil {
+		log.LogErrorf("action[setDefaultZone] err[%v]", err)
+		c.DefaultZone = oldZone
+		err = proto.ErrPersistenceByRaft
+		return
+	}
+	return
+}
+
+func (c *Cluster) getHeartbeatVerbosity() string {
+	if c.HeartbeatVerbosity == "" {
+		return proto.HeartbeatVerbosityMinimal
+	}
+	return c.HeartbeatVerbosity
+}
+
+func (c *Cluster) isHeartbeatVerbose() bool {
+	return c.getHeartbeatVerbosity() == proto.HeartbeatVerbosityVerbose
+}
+
+func (c *Cluster) setHeartbeatVerbosity(verbosity string) (err error) {
+	oldVerbosity := c.HeartbeatVerbosity
+	c.HeartbeatVerbosity = verbosity
+	if err = c.syncPutCluster(); err != nil {
+		log.LogErrorf("action[setHeartbeatVerbosity] err[%v]", err)
+		c.HeartbeatVerbosity = oldVerbosity
+		err = proto.ErrPersistenceByRaft
+		return
+	}
+	return
+}
+
+func (c *Cluster) getMinRWDataPartitions() int {
+	if c.MinRWDataPartitions == 0 {
+		return minNumOfRWDataPartitions
+	}
+	return c.MinRWDataPartitions
+}
+
+func (c *Cluster) getAutoAllocUsedRatio() float64 {
+	if c.AutoAllocUsedRatio == 0 {
+		return defaultAutoAllocUsedRatio
+	}
+	return c.AutoAllocUsedRatio
+}
+
+func (c *Cluster) setAutoAllocThreshold(minRWDataPartitions int, usedRatio float64) (err error) {
+	oldMinRWDataPartitions, oldUsedRatio := c.MinRWDataPartitions, c.AutoAllocUsedRatio
+	c.MinRWDataPartitions, c.AutoAllocUsedRatio = minRWDataPartitions, usedRatio
+	if err = c.syncPutCluster(); err != nil {
+		log.LogErrorf("action[setAutoAllocThreshold] err[%v]", err)
+		c.MinRWDataPartitions, c.AutoAllocUsedRatio = oldMinRWDataPartitions, oldUsedRatio
+		err = proto.ErrPersistenceByRaft
+		return
+	}
+	return
+}
+
+// Set the tags of the data node and the meta node listening on the addr.
+// Set the tags of the data node and the meta node on the addr, the tags of both are set or neither is.
+func (c *Cluster) setNodeTags(addr string, tags []string) (err error) {
+	var (
+		dataNode    *DataNode
+		metaNode    *MetaNode
+		oldDataTags []string
+		oldMetaTags []string
+	)
+	if value, ok := c.dataNodes.Load(addr); ok {
+		dataNode = value.(*DataNode)
+	}
+	if value, ok := c.metaNodes.Load(addr); ok {
+		metaNode = value.(*MetaNode)
+	}
+	if dataNode == nil && metaNode == nil {
+		return fmt.Errorf("[setNodeTags] node %s is not exist", addr)
+	}
+	if dataNode != nil {
+		dataNode.Lock()
+		oldDataTags = dataNode.Tags
+		dataNode.Tags = tags
+		if err = c.syncUpdateDataNode(dataNode); err != nil {
+			dataNode.Tags = oldDataTags
+			dataNode.Unlock()
+			return fmt.Errorf("[setNodeTags] syncUpdateDataNode err(%s)", err.Error())
+		}
+		dataNode.Unlock()
+	}
+	if metaNode != nil {
+		metaNode.Lock()
+		oldMetaTags = metaNode.Tags
+		metaNode.Tags = tags
+		if err = c.syncUpdateMetaNode(metaNode); err != nil {
+			metaNode.Tags = oldMetaTags
+			metaNode.Unlock()
+			if dataNode != nil {
+				c.rollbackDataNodeTags(dataNode, oldDataTags)
+			}
+			return fmt.Errorf("[setNodeTags] syncUpdateMetaNode err(%s)", err.Error())
+		}
+		metaNode.Unlock()
+	}
+	return
+}
+
+// restore the tags of the data node persisted by setNodeTags when the tags of the meta node failed to be set
+func (c *Cluster) rollbackDataNodeTags(dataNode *DataNode, tags []string) {
+	dataNode.Lock()
+	defer dataNode.Unlock()
+	dataNode.Tags = tags
+	if err := c.syncUpdateDataNode(dataNode); err != nil {
+		log.LogErrorf("action[setNodeTags] roll back the tags of data node[%v] err[%v]", dataNode.Addr, err)
+	}
+}
+
+func (c *Cluster) setMaxVolumes(maxVolumes uint64) (err error) {
+	oldMaxVolumes := atomic.LoadUint64(&c.MaxVolumes)
+	atomic.StoreUint64(&c.MaxVolumes, maxVolumes)
+	if err = c.syncPutCluster(); err != nil {
+		log.LogErrorf("action[setMaxVolumes] err[%v]", err)
+		atomic.StoreUint64(&c.MaxVolumes, oldMaxVolumes)
+		err = proto.ErrPersistenceByRaft
+		return
+	}
+	return
+}
+
+func (c *Cluster) setAllocationStrategy(strategy string) (err error) {
+	oldStrategy := c.AllocationStrategy
+	c.AllocationStrategy = strategy
+	if err = c.syncPutCluster(); err != nil {
+		log.LogErrorf("action[setAllocationStrategy] err[%v]", err)
+		c.AllocationStrategy = oldStrategy
+		err = proto.ErrPersistenceByRaft
+		return
+	}
+	setDataNodeAllocStrategy(strategy)
+	return
+}
+
 func (c *Cluster) clearVols() {
 	c.volMutex.Lock()
 	defer c.volMutex.Unlock()
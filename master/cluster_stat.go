@@ -17,6 +17,8 @@ package master
 import (
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/util"
@@ -37,6 +39,100 @@ func newVolStatInfo(name string, total, used uint64, ratio string) *volStatInfo
 	}
 }
 
+// badPartitionTrend records the number of bad data partitions each time the stat info is updated.
+type badPartitionTrend struct {
+	sync.RWMutex
+	samples []*proto.BadPartitionSample
+}
+
+func newBadPartitionTrend() *badPartitionTrend {
+	return &badPartitionTrend{samples: make([]*proto.BadPartitionSample, 0)}
+}
+
+func (t *badPartitionTrend) add(sample *proto.BadPartitionSample) {
+	t.Lock()
+	defer t.Unlock()
+	t.samples = append(t.samples, sample)
+	expireTime := sample.Time - maxBadPartitionTrendHours*3600
+	i := 0
+	for i < len(t.samples) && t.samples[i].Time < expireTime {
+		i++
+	}
+	t.samples = t.samples[i:]
+}
+
+func (t *badPartitionTrend) since(startTime int64) (samples []*proto.BadPartitionSample) {
+	t.RLock()
+	defer t.RUnlock()
+	samples = make([]*proto.BadPartitionSample, 0)
+	for _, sample := range t.samples {
+		if sample.Time >= startTime {
+			samples = append(samples, sample)
+		}
+	}
+	return
+}
+
+// spaceTrend records the space of the data nodes each time the stat info is updated.
+type spaceTrend struct {
+	sync.RWMutex
+	samples []*proto.SpaceSample
+}
+
+func newSpaceTrend() *spaceTrend {
+	return &spaceTrend{samples: make([]*proto.SpaceSample, 0)}
+}
+
+func (t *spaceTrend) add(sample *proto.SpaceSample) {
+	t.Lock()
+	defer t.Unlock()
+	t.samples = append(t.samples, sample)
+	expireTime := sample.Time - maxCapacityForecastHours*3600
+	i := 0
+	for i < len(t.samples) && t.samples[i].Time < expireTime {
+		i++
+	}
+	t.samples = t.samples[i:]
+}
+
+func (t *spaceTrend) since(startTime int64) (samples []*proto.SpaceSample) {
+	t.RLock()
+	defer t.RUnlock()
+	samples = make([]*proto.SpaceSample, 0)
+	for _, sample := range t.samples {
+		if sample.Time >= startTime {
+			samples = append(samples, sample)
+		}
+	}
+	return
+}
+
+// Forecast when the data nodes are full by the used space growing at the rate between the first and the last sample.
+func forecastCapacity(total, used uint64, samples []*proto.SpaceSample, now time.Time) (forecast *proto.CapacityForecast) {
+	forecast = &proto.CapacityForecast{TotalSize: total, UsedSize: used, Samples: len(samples), SecondsToFull: -1}
+	if total > used {
+		forecast.AvailSize = total - used
+	}
+	if total > 0 {
+		forecast.UsedRatio = fixedPoint(float64(used)/float64(total), 3)
+	}
+	if len(samples) < 2 {
+		return
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	if last.Time <= first.Time {
+		return
+	}
+	rate := (float64(last.UsedSize) - float64(first.UsedSize)) / float64(last.Time-first.Time)
+	forecast.FillRatePerDay = int64(rate * 24 * 3600)
+	if rate <= 0 {
+		return
+	}
+	forecast.SecondsToFull = int64(float64(forecast.AvailSize) / rate)
+	forecast.FullTime = now.Add(time.Duration(forecast.SecondsToFull) * time.Second).Format(time.RFC3339)
+	return
+}
+
 func newZoneStatInfo() *proto.ZoneStat {
 	return &proto.ZoneStat{DataNodeStat: new(proto.ZoneNodesStat), MetaNodeStat: new(proto.ZoneNodesStat)}
 }
@@ -54,6 +150,37 @@ func (c *Cluster) updateStatInfo() {
 	c.updateMetaNodeStatInfo()
 	c.updateVolStatInfo()
 	c.updateZoneStatInfo()
+	c.updateBadPartitionTrend()
+	c.updateSpaceTrend()
+}
+
+func (c *Cluster) updateBadPartitionTrend() {
+	count := 0
+	c.BadDataPartitionIds.Range(func(key, value interface{}) bool {
+		count += len(value.([]uint64))
+		return true
+	})
+	c.badPartitionTrend.add(&proto.BadPartitionSample{Time: time.Now().Unix(), Count: count})
+}
+
+func (c *Cluster) updateSpaceTrend() {
+	total, used := c.dataNodeSpace()
+	if total == 0 {
+		return
+	}
+	c.spaceTrend.add(&proto.SpaceSample{Time: time.Now().Unix(), TotalSize: total, UsedSize: used})
+}
+
+// the space of the data nodes and the trend of it during the last hours
+func (c *Cluster) getCapacityForecast(hours int) *proto.CapacityForecast {
+	total, used := c.dataNodeSpace()
+	now := time.Now()
+	return forecastCapacity(total, used, c.spaceTrend.since(now.Unix()-int64(hours)*3600), now)
+}
+
+// the counts of the bad data partitions during the last hours
+func (c *Cluster) getBadPartitionTrend(hours int) []*proto.BadPartitionSample {
+	return c.badPartitionTrend.since(time.Now().Unix() - int64(hours)*3600)
 }
 
 func (c *Cluster) updateZoneStatInfo() {
@@ -102,17 +229,18 @@ func fixedPoint(x float64, scale int) float64 {
 	return float64(int(math.Round(x*decimal))) / decimal
 }
 
-func (c *Cluster) updateDataNodeStatInfo() {
-	var (
-		total uint64
-		used  uint64
-	)
+func (c *Cluster) dataNodeSpace() (total, used uint64) {
 	c.dataNodes.Range(func(addr, node interface{}) bool {
 		dataNode := node.(*DataNode)
 		total = total + dataNode.Total
 		used = used + dataNode.Used
 		return true
 	})
+	return
+}
+
+func (c *Cluster) updateDataNodeStatInfo() {
+	total, used := c.dataNodeSpace()
 	if total <= 0 {
 		return
 	}
@@ -731,12 +731,15 @@ func (c *Cluster) dealMetaNodeHeartbeatResp(nodeAddr string, resp *proto.MetaNod
 		log.LogWarnf("metaNode zone changed from [%v] to [%v]", oldZoneName, resp.ZoneName)
 	}
 	metaNode.updateMetric(resp, c.cfg.MetaNodeThreshold)
-	metaNode.setNodeActive()
+	if metaNode.setNodeActive() {
+		c.publishEvent(proto.EventMetaNodeActive, metaNode.Addr, "")
+	}
 
 	if err = c.t.putMetaNode(metaNode); err != nil {
 		log.LogErrorf("action[dealMetaNodeHeartbeatResp],metaNode[%v] error[%v]", metaNode.Addr, err)
 	}
 	c.updateMetaNode(metaNode, resp.MetaPartitionReports, metaNode.reachesThreshold())
+	c.checkLostMetaReplicas(metaNode.Addr)
 	metaNode.metaPartitionInfos = nil
 	logMsg = fmt.Sprintf("action[dealMetaNodeHeartbeatResp],metaNode:%v,zone[%v], ReportTime:%v  success", metaNode.Addr, metaNode.ZoneName, time.Now().Unix())
 	log.LogInfof(logMsg)
@@ -848,23 +851,19 @@ func (c *Cluster) dealDeleteDataPartitionResponse(nodeAddr string, resp *proto.D
 }
 
 func (c *Cluster) handleResponseToLoadDataPartition(nodeAddr string, resp *proto.LoadDataPartitionResponse) (err error) {
+	c.collectDataPartitionVerification(nodeAddr, resp)
 	if resp.Status == proto.TaskFailed || resp.PartitionSnapshot == nil {
 		return
 	}
 	var (
 		dataNode *DataNode
 		dp       *DataPartition
-		vol      *Vol
 	)
 	if dataNode, err = c.dataNode(nodeAddr); err != nil {
 		return
 	}
 	if resp.VolName != "" {
-		vol, err = c.getVol(resp.VolName)
-		if err != nil {
-			return
-		}
-		dp, err = vol.getDataPartitionByID(resp.PartitionId)
+		_, dp, err = c.getReportedDataPartition(resp.VolName, resp.PartitionId)
 	} else {
 		dp, err = c.getDataPartitionByID(resp.PartitionId)
 	}
@@ -907,7 +906,9 @@ func (c *Cluster) handleDataNodeHeartbeatResp(nodeAddr string, resp *proto.DataN
 		log.LogWarnf("dataNode [%v] zone changed from [%v] to [%v]", dataNode.Addr, oldZoneName, resp.ZoneName)
 	}
 
-	dataNode.updateNodeMetric(resp)
+	if dataNode.updateNodeMetric(resp) {
+		c.publishEvent(proto.EventDataNodeActive, dataNode.Addr, "")
+	}
 	if err = c.t.putDataNode(dataNode); err != nil {
 		log.LogErrorf("action[handleDataNodeHeartbeatResp] dataNode[%v],zone[%v],node set[%v], err[%v]", dataNode.Addr, dataNode.ZoneName, dataNode.NodeSetID, err)
 	}
@@ -965,16 +966,14 @@ func (c *Cluster) updateDataNode(dataNode *DataNode, dps []*proto.PartitionRepor
 			continue
 		}
 		if vr.VolName != "" {
-			vol, err := c.getVol(vr.VolName)
+			vol, dp, err := c.getReportedDataPartition(vr.VolName, vr.PartitionID)
 			if err != nil {
 				continue
 			}
 			if vol.Status == markDelete {
 				continue
 			}
-			if dp, err := vol.getDataPartitionByID(vr.PartitionID); err == nil {
-				dp.updateMetric(vr, dataNode, c)
-			}
+			dp.updateMetric(vr, dataNode, c)
 		} else {
 			if dp, err := c.getDataPartitionByID(vr.PartitionID); err == nil {
 				dp.updateMetric(vr, dataNode, c)
@@ -994,17 +993,13 @@ func (c *Cluster) updateMetaNode(metaNode *MetaNode, metaPartitions []*proto.Met
 		}
 		var mp *MetaPartition
 		if mr.VolName != "" {
-			vol, err = c.getVol(mr.VolName)
+			vol, mp, err = c.getReportedMetaPartition(mr.VolName, mr.PartitionID)
 			if err != nil {
 				continue
 			}
 			if vol.Status == markDelete {
 				continue
 			}
-			mp, err = vol.metaPartition(mr.PartitionID)
-			if err != nil {
-				continue
-			}
 		} else {
 			mp, err = c.getMetaPartitionByID(mr.PartitionID)
 			if err != nil {
@@ -1021,6 +1016,20 @@ func (c *Cluster) updateMetaNode(metaNode *MetaNode, metaPartitions []*proto.Met
 	}
 }
 
+// Track the meta partitions whose replica on the meta node is no longer reported or is unavailable,
+// they are shown as the BadMetaPartitionIDs until the replica is reported again and the partition catches up.
+func (c *Cluster) checkLostMetaReplicas(addr string) {
+	for _, mp := range c.getAllMetaPartitionByMetaNode(addr) {
+		mp.RLock()
+		lost := mp.isMissingReplica(addr)
+		mp.RUnlock()
+		if lost {
+			log.LogWarnf("action[checkLostMetaReplicas] vol[%v] meta partition[%v] lost the replica on [%v]", mp.volName, mp.PartitionID, addr)
+			c.putBadMetaPartitions(addr, mp.PartitionID)
+		}
+	}
+}
+
 func (c *Cluster) updateInodeIDUpperBound(mp *MetaPartition, mr *proto.MetaPartitionReport, hasArriveThreshold bool, metaNode *MetaNode) (err error) {
 	if !hasArriveThreshold {
 		return
@@ -1031,7 +1040,7 @@ func (c *Cluster) updateInodeIDUpperBound(mp *MetaPartition, mr *proto.MetaParti
 		return
 	}
 	maxPartitionID := vol.maxPartitionID()
-	if mr.PartitionID < maxPartitionID {
+	if mr.PartitionID != maxPartitionID {
 		return
 	}
 	var end uint64
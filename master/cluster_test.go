@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
 )
 
 func buildPanicCluster() *Cluster {
@@ -254,6 +255,88 @@ func TestCheckBadMetaPartitionRecovery(t *testing.T) {
 	}
 }
 
+func TestCheckLostMetaReplicas(t *testing.T) {
+	vol, err := server.cluster.getVol(commonVolName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mp := vol.MetaPartitions[vol.maxPartitionID()]
+	mp.RLock()
+	if len(mp.Replicas) == 0 {
+		mp.RUnlock()
+		t.Errorf("no replicas of meta partition[%v]", mp.PartitionID)
+		return
+	}
+	replica := mp.Replicas[0]
+	mp.RUnlock()
+	reportTime := replica.ReportTime
+	replica.ReportTime = time.Now().Unix() - defaultMetaPartitionTimeOutSec - 1
+	defer func() {
+		replica.ReportTime = reportTime
+		server.cluster.BadMetaPartitionIds.Delete(replica.Addr)
+	}()
+	server.cluster.checkLostMetaReplicas(replica.Addr)
+	server.cluster.checkLostMetaReplicas(replica.Addr)
+	found := 0
+	for _, view := range server.cluster.getBadMetaPartitionsView() {
+		if view.Path != replica.Addr {
+			continue
+		}
+		for _, id := range view.PartitionIDs {
+			if id == mp.PartitionID {
+				found++
+			}
+		}
+	}
+	if found != 1 {
+		t.Errorf("expect meta partition[%v] to be bad once on [%v], but found %v times", mp.PartitionID, replica.Addr, found)
+	}
+}
+
+func TestCancelDataNodeDecommission(t *testing.T) {
+	c := server.cluster
+	if _, _, err := c.cancelDataNodeDecommission(mds3Addr); err == nil {
+		t.Errorf("cancel the decommission of node[%v] not being decommissioned should fail", mds3Addr)
+	}
+	src, err := c.dataNode(mds3Addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	partitions := c.getAllDataPartitionByDataNode(mds3Addr)
+	if len(partitions) == 0 {
+		t.Errorf("no partitions on node[%v]", mds3Addr)
+		return
+	}
+	availableSpace := src.AvailableSpace
+	defer func() { src.AvailableSpace = availableSpace }()
+	decommission := &nodeDecommission{
+		job:     c.jobs.register(proto.JobDecommissionDataNode, mds3Addr, len(partitions)),
+		pending: int32(len(partitions)),
+		stopC:   make(chan struct{}),
+	}
+	c.nodeDecommissions.Store(mds3Addr, decommission)
+	jobID, pending, err := c.cancelDataNodeDecommission(mds3Addr)
+	if err != nil || jobID != decommission.job.id || pending != len(partitions) {
+		t.Errorf("cancel job[%v] pending[%v] err[%v], expect job[%v] pending[%v]", jobID, pending, err, decommission.job.id, len(partitions))
+	}
+	// nothing is migrated once it is canceled
+	c.decommissionDataNodeGradually(src, partitions, 1, len(partitions), decommission)
+	if _, ok := c.nodeDecommissions.Load(mds3Addr); ok || src.ToBeOffline {
+		t.Errorf("node[%v] is still being decommissioned", mds3Addr)
+	}
+	if src.AvailableSpace != availableSpace {
+		t.Errorf("available space of node[%v] is %v after the cancellation, expect %v", mds3Addr, src.AvailableSpace, availableSpace)
+	}
+	if len(c.getAllDataPartitionByDataNode(mds3Addr)) != len(partitions) {
+		t.Errorf("partitions of node[%v] are migrated after the cancellation", mds3Addr)
+	}
+	if view := decommission.job.toView(); view.State != proto.JobStateCanceled || view.EndTime == "" {
+		t.Errorf("expect the job to be finished as %v, but got %v", proto.JobStateCanceled, view)
+	}
+}
+
 func TestUpdateInodeIDUpperBound(t *testing.T) {
 	vol, err := server.cluster.getVol(commonVolName)
 	if err != nil {
@@ -289,3 +372,46 @@ func TestUpdateInodeIDUpperBound(t *testing.T) {
 	}
 
 }
+
+func TestForecastCapacity(t *testing.T) {
+	now := time.Now()
+	samples := []*proto.SpaceSample{
+		{Time: now.Unix() - 24*3600, TotalSize: 1000, UsedSize: 100},
+		{Time: now.Unix() - 12*3600, TotalSize: 1000, UsedSize: 150},
+		{Time: now.Unix(), TotalSize: 1000, UsedSize: 200},
+	}
+	forecast := forecastCapacity(1000, 200, samples, now)
+	if forecast.AvailSize != 800 || forecast.FillRatePerDay != 100 || forecast.SecondsToFull != 8*24*3600 {
+		t.Errorf("expect 800 available filled in 8 days at 100 per day, but got %v", *forecast)
+	}
+	if forecast.FullTime != now.Add(8*24*time.Hour).Format(time.RFC3339) {
+		t.Errorf("expect full at %v, but got %v", now.Add(8*24*time.Hour), forecast.FullTime)
+	}
+	samples[0].UsedSize = 300
+	if forecast = forecastCapacity(1000, 200, samples, now); forecast.SecondsToFull != -1 || forecast.FullTime != "" {
+		t.Errorf("expect no forecast of the shrinking space, but got %v", *forecast)
+	}
+	if forecast = forecastCapacity(1000, 200, samples[:1], now); forecast.SecondsToFull != -1 {
+		t.Errorf("expect no forecast of a single sample, but got %v", *forecast)
+	}
+}
+
+func TestDataNodeBalanceScore(t *testing.T) {
+	c := new(Cluster)
+	// the first node set is balanced, the worst node set decides the score rather than the counts of the cluster
+	counts := map[uint64][]uint32{1: {10, 10}, 2: {4, 5}}
+	for nodeSetID, nodeCounts := range counts {
+		for i, count := range nodeCounts {
+			dn := newDataNode(fmt.Sprintf("192.168.102.%v:17310", nodeSetID*10+uint64(i)), testZone1, "test")
+			dn.NodeSetID = nodeSetID
+			dn.AvailableSpace = 100 * util.GB
+			dn.isActive = true
+			dn.DataPartitionCount = count
+			c.dataNodes.Store(dn.Addr, dn)
+			defer dn.clean()
+		}
+	}
+	if score := c.dataNodeBalanceScore(); score != 0.8 {
+		t.Errorf("expect the balance score 0.8 of the worst node set, but got %v", score)
+	}
+}
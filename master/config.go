@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/cubefs/cubefs/raftstore"
+	"github.com/cubefs/cubefs/util"
 	"github.com/tiglabs/raft/proto"
 )
 
@@ -41,6 +42,28 @@ const (
 	faultDomain                         = "faultDomain"
 	cfgDomainBatchGrpCnt                = "faultDomainGrpBatchCnt"
 	cfgDomainBuildAsPossible            = "faultDomainBuildAsPossible"
+	cfgStrictParamCheck                 = "strictParamCheck"
+	cfgAdminToken                       = "adminToken"
+	cfgSecureRead                       = "secureRead"
+	cfgBatchDecommissionConcurrency     = "batchDecommissionConcurrency"
+	cfgMaxReplicaNum                    = "maxReplicaNum"
+	cfgReadyMaxAppliedLag               = "readyMaxAppliedLag"
+	cfgTaskResponseQPS                  = "taskResponseQPS"
+	cfgMaxTaskResponseBytes             = "maxTaskResponseBytes"
+	cfgDecommissionRate                 = "decommissionRate"
+	cfgJobRetentionSec                  = "jobRetentionSec"
+	cfgVolNameMinLen                    = "volNameMinLen"
+	cfgVolNameMaxLen                    = "volNameMaxLen"
+	cfgVolNamePattern                   = "volNamePattern"
+	cfgVolDeletionGraceSec              = "volDeletionGraceSec"
+	cfgGzipMinBytes                     = "gzipMinBytes"
+	cfgAuditLogCapacity                 = "auditLogCapacity"
+	cfgRebalanceMaxConcurrentMoves      = "rebalanceMaxConcurrentMoves"
+	cfgCORSAllowedOrigins               = "corsAllowedOrigins"
+	cfgCORSMutatingAPIs                 = "corsMutatingAPIs"
+	cfgMinVolCapacity                   = "minVolCapacity"
+	cfgMaxVolCapacity                   = "maxVolCapacity"
+	cfgDataNodeAllocStrategy            = "dataNodeAllocStrategy"
 )
 
 //default value
@@ -72,6 +95,16 @@ const (
 	defaultReplicaNum                                  = 3
 	defaultDiffSpaceUsage                              = 1024 * 1024 * 1024
 	defaultNodeSetGrpStep                              = 1
+	defaultBatchDecommissionConcurrency                = 10
+	defaultMaxReplicaNum                               = 3
+	defaultReadyMaxAppliedLag                          = 1000
+	defaultTaskResponseQPS                             = 1000
+	defaultMaxTaskResponseBytes                        = 4 * util.MB
+	defaultJobRetentionSec                             = 24 * 3600
+	defaultVolNameMinLen                               = 3
+	defaultVolNameMaxLen                               = 63
+	defaultVolNamePattern                              = "^[a-zA-Z0-9][a-zA-Z0-9_.-]*[a-zA-Z0-9]$"
+	defaultGzipMinBytes                                = 64 * 1024
 )
 
 // AddrDatabase is a map that stores the address of a given host (e.g., the leader)
@@ -103,6 +136,27 @@ type clusterConfig struct {
 	DomainNodeGrpBatchCnt               int
 	DomainBuildAsPossible               bool
 	DataPartitionUsageThreshold         float64
+	strictParamCheck                    bool   // reject the requests with unknown parameters
+	adminToken                          string // required by the mutating APIs if it is not empty
+	secureRead                          bool   // the read-only APIs require the admin token as well
+	batchDecommissionConcurrency        int    // the max data partitions decommissioned at the same time by a batch
+	maxReplicaNum                       int    // the max replica number of the data partitions of a volume
+	readyMaxAppliedLag                  uint64 // a follower is ready if its applied index is within it of the committed index
+	taskResponseQPS                     int    // the max task responses per second accepted from a node
+	maxTaskResponseBytes                int64  // the max body size of a task response
+	decommissionRate                    int    // the partitions migrated per minute when a data node is decommissioned, 0 means no limit
+	jobRetentionSec                     int64  // how long a job is kept after it finished
+	volDeletionGraceSec                 int64  // how long the partitions of a deleted volume are kept, the volume can be undeleted meanwhile
+	gzipMinBytes                        int    // the min size of a response compressed by gzip, 0 disables the compression
+	auditLogCapacity                    int    // the number of the recent admin actions kept in memory
+	rebalanceMaxConcurrentMoves         int    // the max number of data partitions recovering at the same time when rebalancing by hand
+
+	corsAllowedOrigins []string // the origins allowed to call the APIs from the browsers, * allows all, empty disables CORS
+	corsMutatingAPIs   bool     // serve the mutating APIs to the allowed origins too, only the read APIs if not set
+	minVolCapacity     uint64   // the min capacity in GB of a volume
+	maxVolCapacity     uint64   // the max capacity in GB of a volume, 0 means no limit
+
+	dataNodeAllocStrategy string // the placement strategy of the data partitions until one is set by the API
 }
 
 func newClusterConfig() (cfg *clusterConfig) {
@@ -119,6 +173,16 @@ func newClusterConfig() (cfg *clusterConfig) {
 	cfg.MetaNodeThreshold = defaultMetaPartitionMemUsageThreshold
 	cfg.metaNodeReservedMem = defaultMetaNodeReservedMem
 	cfg.diffSpaceUsage = defaultDiffSpaceUsage
+	cfg.batchDecommissionConcurrency = defaultBatchDecommissionConcurrency
+	cfg.maxReplicaNum = defaultMaxReplicaNum
+	cfg.readyMaxAppliedLag = defaultReadyMaxAppliedLag
+	cfg.dataNodeAllocStrategy = allocStrategyDefault
+	cfg.taskResponseQPS = defaultTaskResponseQPS
+	cfg.maxTaskResponseBytes = defaultMaxTaskResponseBytes
+	cfg.jobRetentionSec = defaultJobRetentionSec
+	cfg.gzipMinBytes = defaultGzipMinBytes
+	cfg.auditLogCapacity = defaultAuditLogCapacity
+	cfg.rebalanceMaxConcurrentMoves = defaultRebalanceMaxConcurrentMoves
 	return
 }
 
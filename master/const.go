@@ -28,7 +28,11 @@ const (
 	nameKey                 = "name"
 	idKey                   = "id"
 	countKey                = "count"
+	actionKey               = "action"
+	levelKey                = "level"
 	startKey                = "start"
+	inodeKey                = "inode"
+	dryRunKey               = "dryRun"
 	enableKey               = "enable"
 	thresholdKey            = "threshold"
 	dataPartitionSizeKey    = "size"
@@ -41,11 +45,14 @@ const (
 	authenticateKey         = "authenticate"
 	akKey                   = "ak"
 	keywordsKey             = "keywords"
+	keywordKey              = "keyword"
 	zoneNameKey             = "zoneName"
+	zoneKey                 = "zone"
 	crossZoneKey            = "crossZone"
 	defaultPriority         = "defaultPriority"
 	userKey                 = "user"
 	nodeHostsKey            = "hosts"
+	nodeSetCapacityKey      = "capacity"
 	nodeDeleteBatchCountKey = "batchCount"
 	nodeMarkDeleteRateKey   = "markDeleteRate"
 	nodeDeleteWorkerSleepMs = "deleteWorkerSleepMs"
@@ -59,6 +66,33 @@ const (
 	srcAddrKey              = "srcAddr"
 	targetAddrKey           = "targetAddr"
 	forceKey                = "force"
+	priorityKey             = "priority"
+	ttlKey                  = "ttl"
+	strategyKey             = "strategy"
+	startTimeKey            = "startTime"
+	endTimeKey              = "endTime"
+	ioPriorityClassKey      = "class"
+	hoursKey                = "hours"
+	minBalanceScoreKey      = "minScore"
+	windowStartKey          = "windowStart"
+	windowEndKey            = "windowEnd"
+	maxMovesKey             = "maxMoves"
+	groupByStatusKey        = "groupByStatus"
+	verbosityKey            = "verbosity"
+	tokenKey                = "token"
+	idsKey                  = "ids"
+	rateKey                 = "rate"
+	typeKey                 = "type"
+	statusKey               = "status"
+	failIfExistsKey         = "failIfExists"
+	tagsKey                 = "tags"
+	tagKey                  = "tag"
+	bandwidthLimitKey       = "bandwidthLimit"
+	iopsLimitKey            = "iopsLimit"
+	timeoutKey              = "timeout"
+	maxNodesKey             = "maxNodes"
+	maxInactiveRatioKey     = "maxInactiveRatio"
+	newNameKey              = "newName"
 )
 
 const (
@@ -74,6 +108,29 @@ const (
 	underlineSeparator = "_"
 )
 
+// Values of the filter of the topology
+const (
+	topologyTypeData       = "data"
+	topologyTypeMeta       = "meta"
+	topologyStatusActive   = "active"
+	topologyStatusInactive = "inactive"
+)
+
+// The addr letting the master choose the data node of a new replica
+const (
+	autoReplicaAddr = "auto"
+)
+
+// Limits of the tags of a volume
+const (
+	maxVolTags        = 32
+	maxVolTagKeyLen   = 64
+	maxVolTagValueLen = 256
+)
+
+// the capacity changes kept for each volume
+const maxVolCapacityHistory = 64
+
 const (
 	LRUCacheSize    = 3 << 30
 	WriteBufferSize = 4 * util.MB
@@ -90,6 +147,7 @@ const (
 	spaceAvailableRate                           = 0.90
 	defaultNodeSetCapacity                       = 18
 	minNumOfRWDataPartitions                     = 10
+	defaultAutoAllocUsedRatio                    = 1.0
 	intervalToCheckMissingReplica                = 600
 	intervalToWarnDataPartition                  = 600
 	intervalToLoadDataPartition                  = 12 * 60 * 60
@@ -104,6 +162,26 @@ const (
 	defaultNodeSetGrpBatchCnt                    = 3
 	defaultMigrateDpCnt                          = 50
 	defaultMigrateMpCnt                          = 15
+	defaultVolAllocPriorityTTL                   = 3600             // seconds
+	maxVolAllocPriorityTTL                       = 7 * 24 * 60 * 60 // seconds
+	allocPriorityContendedRatio                  = 0.8
+	defaultLaggingReplicaCount                   = 10
+	defaultAuditLogCapacity                      = 10000
+	defaultAuditLogQueryCount                    = 100
+	defaultBadPartitionTrendHours                = 24
+	maxBadPartitionTrendHours                    = 7 * 24
+	defaultCapacityForecastHours                 = 24
+	maxCapacityForecastHours                     = 7 * 24
+	maxReservedPartitionIDCount                  = 1000
+	defaultAutoRebalanceMinBalanceScore          = 0.8
+	defaultAutoRebalanceMaxMoves                 = 5
+	defaultRebalanceMaxConcurrentMoves           = 5
+	defaultAutoDecommissionRate                  = 60 // the partitions migrated per minute unless decommissionRate is set
+	defaultAutoDecommissionMaxNodes              = 1
+	defaultAutoDecommissionInactiveRatio         = 0.3
+	maxBatchCreateVolCount                       = 100
+	minReplicaNum                                = 2
+	maskedSecret                                 = "******"
 )
 
 const (
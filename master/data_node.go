@@ -16,6 +16,7 @@ package master
 
 import (
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
@@ -45,9 +46,13 @@ type DataNode struct {
 	NodeSetID                 uint64
 	PersistenceDataPartitions []uint64
 	BadDisks                  []string
+	DecommissionedDisks       []string // no data partition is created on the disks decommissioned until they are recommissioned
 	ToBeOffline               bool
 	RdOnly                    bool
 	MigrateLock               sync.RWMutex
+	Tags                      []string            // the labels used by the topology-aware placement
+	diskWrittenSizes          map[string]uint64   // key: disk path, value: cumulative bytes written to the disk
+	diskReports               []*proto.DiskReport // reported only if the heartbeat is verbose
 }
 
 func newDataNode(addr, zoneName, clusterID string) (dataNode *DataNode) {
@@ -60,12 +65,14 @@ func newDataNode(addr, zoneName, clusterID string) (dataNode *DataNode) {
 	return
 }
 
-func (dataNode *DataNode) checkLiveness() {
+// Return true if the node has just become inactive.
+func (dataNode *DataNode) checkLiveness() (inactivated bool) {
 	dataNode.Lock()
 	defer dataNode.Unlock()
 	log.LogInfof("action[checkLiveness] datanode[%v] report time[%v],since report time[%v], need gap [%v]",
 		dataNode.Addr, dataNode.ReportTime, time.Since(dataNode.ReportTime), time.Second*time.Duration(defaultNodeTimeOutSec))
 	if time.Since(dataNode.ReportTime) > time.Second*time.Duration(defaultNodeTimeOutSec) {
+		inactivated = dataNode.isActive
 		dataNode.isActive = false
 	}
 
@@ -85,7 +92,8 @@ func (dataNode *DataNode) badPartitions(diskPath string, c *Cluster) (partitions
 	return
 }
 
-func (dataNode *DataNode) updateNodeMetric(resp *proto.DataNodeHeartbeatResponse) {
+// Return true if the node has just become active.
+func (dataNode *DataNode) updateNodeMetric(resp *proto.DataNodeHeartbeatResponse) (activated bool) {
 	dataNode.Lock()
 	defer dataNode.Unlock()
 	dataNode.Total = resp.Total
@@ -95,13 +103,104 @@ func (dataNode *DataNode) updateNodeMetric(resp *proto.DataNodeHeartbeatResponse
 	dataNode.DataPartitionCount = resp.CreatedPartitionCnt
 	dataNode.DataPartitionReports = resp.PartitionReports
 	dataNode.BadDisks = resp.BadDisks
+	dataNode.diskWrittenSizes = resp.DiskWrittenSizes
+	dataNode.diskReports = resp.DiskReports
 	if dataNode.Total == 0 {
 		dataNode.UsageRatio = 0.0
 	} else {
 		dataNode.UsageRatio = (float64)(dataNode.Used) / (float64)(dataNode.Total)
 	}
 	dataNode.ReportTime = time.Now()
+	activated = !dataNode.isActive
 	dataNode.isActive = true
+	return
+}
+
+// Return the disks sorted by path. The space of a disk is known only if the heartbeat is verbose,
+// otherwise the disks are the ones holding the reported partitions, with the used space of the partitions.
+func (dataNode *DataNode) disks() (disks []*proto.DataNodeDisk) {
+	dataNode.RLock()
+	defer dataNode.RUnlock()
+	badDisks := make(map[string]bool, len(dataNode.BadDisks))
+	for _, path := range dataNode.BadDisks {
+		badDisks[path] = true
+	}
+	disks = make([]*proto.DataNodeDisk, 0)
+	if len(dataNode.diskReports) > 0 {
+		for _, report := range dataNode.diskReports {
+			disks = append(disks, &proto.DataNodeDisk{
+				Path:           report.Path,
+				Total:          report.Total,
+				Used:           report.Used,
+				Available:      report.Available,
+				PartitionCount: report.PartitionCount,
+				ReadOnly:       report.Status == proto.ReadOnly || badDisks[report.Path],
+			})
+		}
+	} else {
+		diskMap := make(map[string]*proto.DataNodeDisk)
+		for _, report := range dataNode.DataPartitionReports {
+			disk, ok := diskMap[report.DiskPath]
+			if !ok {
+				disk = &proto.DataNodeDisk{Path: report.DiskPath, ReadOnly: badDisks[report.DiskPath]}
+				diskMap[report.DiskPath] = disk
+				disks = append(disks, disk)
+			}
+			disk.Used += report.Used
+			disk.PartitionCount++
+		}
+		for path := range badDisks {
+			if _, ok := diskMap[path]; !ok {
+				disks = append(disks, &proto.DataNodeDisk{Path: path, ReadOnly: true})
+			}
+		}
+	}
+	sort.Slice(disks, func(i, j int) bool { return disks[i].Path < disks[j].Path })
+	for _, disk := range disks {
+		for _, path := range dataNode.DecommissionedDisks {
+			if disk.Path == path {
+				disk.Decommissioned = true
+			}
+		}
+	}
+	return
+}
+
+func (dataNode *DataNode) getDecommissionedDisks() (disks []string) {
+	dataNode.RLock()
+	defer dataNode.RUnlock()
+	disks = make([]string, len(dataNode.DecommissionedDisks))
+	copy(disks, dataNode.DecommissionedDisks)
+	return
+}
+
+func (dataNode *DataNode) isDiskDecommissioned(diskPath string) bool {
+	dataNode.RLock()
+	defer dataNode.RUnlock()
+	for _, path := range dataNode.DecommissionedDisks {
+		if path == diskPath {
+			return true
+		}
+	}
+	return false
+}
+
+// the bytes written to the most worn disk, used to measure the wear of the devices
+func (dataNode *DataNode) getMaxDiskWrittenSize() (maxWritten uint64) {
+	dataNode.RLock()
+	defer dataNode.RUnlock()
+	for _, size := range dataNode.diskWrittenSizes {
+		if size > maxWritten {
+			maxWritten = size
+		}
+	}
+	return
+}
+
+func (dataNode *DataNode) getDiskWrittenSizes() map[string]uint64 {
+	dataNode.RLock()
+	defer dataNode.RUnlock()
+	return dataNode.diskWrittenSizes
 }
 
 func (dataNode *DataNode) isWriteAble() (ok bool) {
@@ -115,6 +214,29 @@ func (dataNode *DataNode) isWriteAble() (ok bool) {
 	return
 }
 
+func (dataNode *DataNode) toNodeView() (view proto.NodeView) {
+	view = proto.NodeView{ID: dataNode.ID, Addr: dataNode.Addr, IsWritable: dataNode.isWriteAble()}
+	dataNode.RLock()
+	defer dataNode.RUnlock()
+	view.Status = dataNode.isActive
+	view.Total = dataNode.Total
+	view.Used = dataNode.Used
+	view.AvailableGB = dataNode.AvailableSpace / util.GB
+	view.ReportTime, view.InactiveSeconds = reportTimeView(dataNode.ReportTime, dataNode.isActive)
+	return
+}
+
+func reportTimeView(reportTime time.Time, isActive bool) (formatted string, inactiveSeconds int64) {
+	if reportTime.IsZero() {
+		return
+	}
+	formatted = reportTime.Format(time.RFC3339)
+	if !isActive {
+		inactiveSeconds = int64(time.Since(reportTime).Seconds())
+	}
+	return
+}
+
 func (dataNode *DataNode) isWriteAbleWithSize(size uint64) (ok bool) {
 	dataNode.RLock()
 	defer dataNode.RUnlock()
@@ -165,10 +287,12 @@ func (dataNode *DataNode) clean() {
 	dataNode.TaskManager.exitCh <- struct{}{}
 }
 
-func (dataNode *DataNode) createHeartbeatTask(masterAddr string) (task *proto.AdminTask) {
+func (dataNode *DataNode) createHeartbeatTask(masterAddr string, verbose bool) (task *proto.AdminTask) {
 	request := &proto.HeartBeatRequest{
-		CurrTime:   time.Now().Unix(),
-		MasterAddr: masterAddr,
+		CurrTime:            time.Now().Unix(),
+		MasterAddr:          masterAddr,
+		Verbose:             verbose,
+		DecommissionedDisks: dataNode.getDecommissionedDisks(),
 	}
 	task = proto.NewAdminTask(proto.OpDataNodeHeartbeat, dataNode.Addr, request)
 	return
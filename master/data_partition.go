@@ -296,10 +296,20 @@ func (partition *DataPartition) convertToDataPartitionResponse() (dpr *proto.Dat
 	dpr.PartitionID = partition.PartitionID
 	dpr.Status = partition.Status
 	dpr.ReplicaNum = partition.ReplicaNum
+	dpr.LiveReplicaNum = partition.liveReplicaNum()
 	dpr.Hosts = make([]string, len(partition.Hosts))
 	copy(dpr.Hosts, partition.Hosts)
 	dpr.LeaderAddr = partition.getLeaderAddr()
 	dpr.IsRecover = partition.isRecover
+	dpr.Replicas = make([]*proto.DataPartitionReplica, 0, len(partition.Replicas))
+	for _, replica := range partition.Replicas {
+		dpr.Replicas = append(dpr.Replicas, &proto.DataPartitionReplica{
+			Addr:     replica.Addr,
+			Status:   replica.Status,
+			DiskPath: replica.DiskPath,
+			Used:     replica.Used,
+		})
+	}
 	return
 }
 
@@ -429,6 +439,16 @@ func (partition *DataPartition) setToNormal() {
 	partition.isRecover = false
 }
 
+// the number of hosts whose data node is active, the caller must hold the lock of the partition
+func (partition *DataPartition) liveReplicaNum() (num uint8) {
+	for _, host := range partition.Hosts {
+		if replica, ok := partition.hasReplica(host); ok && replica.dataNode != nil && replica.dataNode.isActive {
+			num++
+		}
+	}
+	return
+}
+
 func (partition *DataPartition) hasHost(addr string) (ok bool) {
 	for _, host := range partition.Hosts {
 		if host == addr {
@@ -551,6 +571,7 @@ func (partition *DataPartition) updateMetric(vr *proto.PartitionReport, dataNode
 	replica.setAlive()
 	replica.IsLeader = vr.IsLeader
 	replica.NeedsToCompare = vr.NeedCompare
+	replica.ApplyID = vr.ApplyID
 	if replica.DiskPath != vr.DiskPath && vr.DiskPath != "" {
 		oldDiskPath := replica.DiskPath
 		replica.DiskPath = vr.DiskPath
@@ -677,6 +698,38 @@ func (partition *DataPartition) removeOneReplicaByHost(c *Cluster, host string)
 	return
 }
 
+// Get the lag of each replica behind the leader in applying the raft log.
+func (partition *DataPartition) getReplicaLags() (lags []*proto.ReplicaLagView, err error) {
+	partition.RLock()
+	defer partition.RUnlock()
+	var leader *DataReplica
+	for _, replica := range partition.Replicas {
+		if replica.IsLeader {
+			leader = replica
+			break
+		}
+	}
+	if leader == nil {
+		err = proto.ErrNoLeader
+		return
+	}
+	lags = make([]*proto.ReplicaLagView, 0, len(partition.Replicas))
+	for _, replica := range partition.Replicas {
+		lag := &proto.ReplicaLagView{
+			PartitionID: partition.PartitionID,
+			VolName:     partition.VolName,
+			Addr:        replica.Addr,
+			IsLeader:    replica.IsLeader,
+			ApplyID:     replica.ApplyID,
+		}
+		if leader.ApplyID > replica.ApplyID {
+			lag.Lag = leader.ApplyID - replica.ApplyID
+		}
+		lags = append(lags, lag)
+	}
+	return
+}
+
 func (partition *DataPartition) getLiveZones(offlineAddr string) (zones []string) {
 	partition.RLock()
 	defer partition.RUnlock()
@@ -715,6 +768,7 @@ func (partition *DataPartition) ToProto(c *Cluster) *proto.DataPartitionInfo {
 		PartitionID:             partition.PartitionID,
 		LastLoadedTime:          partition.LastLoadedTime,
 		ReplicaNum:              partition.ReplicaNum,
+		LiveReplicaNum:          partition.liveReplicaNum(),
 		Status:                  partition.Status,
 		Replicas:                replicas,
 		Hosts:                   partition.Hosts,
@@ -36,6 +36,7 @@ type DataPartitionMap struct {
 	responseCache          []byte
 	lastAutoCreateTime     time.Time
 	volName                string
+	ioPriorityClass        string
 }
 
 func newDataPartitionMap(volName string) (dpMap *DataPartitionMap) {
@@ -100,6 +101,12 @@ func (dpMap *DataPartitionMap) setDataPartitionResponseCache(responseCache []byt
 	}
 }
 
+func (dpMap *DataPartitionMap) setIOPriorityClass(class string) {
+	dpMap.Lock()
+	defer dpMap.Unlock()
+	dpMap.ioPriorityClass = class
+}
+
 func (dpMap *DataPartitionMap) updateResponseCache(needsUpdate bool, minPartitionID uint64) (body []byte, err error) {
 	responseCache := dpMap.getDataPartitionResponseCache()
 	if responseCache == nil || needsUpdate || len(responseCache) == 0 {
@@ -110,7 +117,7 @@ func (dpMap *DataPartitionMap) updateResponseCache(needsUpdate bool, minPartitio
 			return nil, proto.ErrNoAvailDataPartition
 		}
 		cv := proto.NewDataPartitionsView()
-		cv.DataPartitions = dpResps
+		cv.SetDataPartitions(dpResps)
 		reply := newSuccessHTTPReply(cv)
 		if body, err = json.Marshal(reply); err != nil {
 			log.LogError(fmt.Sprintf("action[updateDpResponseCache],minPartitionID:%v,err:%v",
@@ -136,12 +143,37 @@ func (dpMap *DataPartitionMap) getDataPartitionsView(minPartitionID uint64) (dpR
 			continue
 		}
 		dpResp := dp.convertToDataPartitionResponse()
+		dpResp.IOPriorityClass = dpMap.ioPriorityClass
 		dpResps = append(dpResps, dpResp)
 	}
 
 	return
 }
 
+// Return at most count data partitions from the start index in the order of creation, and the number of all the data partitions.
+func (dpMap *DataPartitionMap) getDataPartitionsPage(start, count int) (total int, dpResps []*proto.DataPartitionResponse) {
+	dpMap.RLock()
+	total = len(dpMap.partitions)
+	partitions := make([]*DataPartition, 0)
+	if start < total {
+		end := total
+		if count < total-start {
+			end = start + count
+		}
+		partitions = append(partitions, dpMap.partitions[start:end]...)
+	}
+	ioPriorityClass := dpMap.ioPriorityClass
+	dpMap.RUnlock()
+
+	dpResps = make([]*proto.DataPartitionResponse, 0, len(partitions))
+	for _, dp := range partitions {
+		dpResp := dp.convertToDataPartitionResponse()
+		dpResp.IOPriorityClass = ioPriorityClass
+		dpResps = append(dpResps, dpResp)
+	}
+	return
+}
+
 func (dpMap *DataPartitionMap) getDataPartitionsToBeReleased(numberOfDataPartitionsToFree int, secondsToFreeDataPartitionAfterLoad int64) (partitions []*DataPartition, startIndex uint64) {
 	partitions = make([]*DataPartition, 0)
 	dpMap.RLock()
@@ -0,0 +1,206 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const defaultVerifyDataPartitionTimeoutSec = 30
+
+// dpVerification collects the file snapshots of the replicas of a data partition, reported by the load tasks.
+type dpVerification struct {
+	sync.Mutex
+	pending   map[string]bool
+	snapshots map[string][]*proto.File
+	failed    []string
+	done      chan struct{}
+}
+
+func newDpVerification(addrs []string) (v *dpVerification) {
+	v = &dpVerification{
+		pending:   make(map[string]bool, len(addrs)),
+		snapshots: make(map[string][]*proto.File, len(addrs)),
+		done:      make(chan struct{}),
+	}
+	for _, addr := range addrs {
+		v.pending[addr] = true
+	}
+	return
+}
+
+func (v *dpVerification) collect(addr string, resp *proto.LoadDataPartitionResponse) {
+	v.Lock()
+	defer v.Unlock()
+	if !v.pending[addr] {
+		return
+	}
+	delete(v.pending, addr)
+	if resp.Status == proto.TaskFailed {
+		v.failed = append(v.failed, addr)
+	} else {
+		v.snapshots[addr] = resp.PartitionSnapshot
+	}
+	if len(v.pending) == 0 {
+		close(v.done)
+	}
+}
+
+// Compare the checksums of the files on the replicas. For each file, the replicas disagreeing with the majority are
+// divergent, or all of them if there is no majority. A replica lacking a file the others have is divergent as well.
+// The files being written or not checksummed yet are skipped, their checksums may differ for the moment.
+func (v *dpVerification) compare(result *proto.DataPartitionVerification) {
+	v.Lock()
+	defer v.Unlock()
+	for addr := range v.pending {
+		result.NoResponseAddrs = append(result.NoResponseAddrs, addr)
+	}
+	result.NoResponseAddrs = append(result.NoResponseAddrs, v.failed...)
+	addrs := make([]string, 0, len(v.snapshots))
+	files := make(map[string]map[string]*proto.File)
+	for addr, snapshot := range v.snapshots {
+		addrs = append(addrs, addr)
+		for _, f := range snapshot {
+			if f == nil {
+				continue
+			}
+			if files[f.Name] == nil {
+				files[f.Name] = make(map[string]*proto.File, len(v.snapshots))
+			}
+			files[f.Name][addr] = f
+		}
+	}
+	sort.Strings(addrs)
+	result.Replicas = addrs
+	divergent := make(map[string]bool)
+	now := time.Now().Unix()
+	for name, replicas := range files {
+		if !isFileSettled(replicas, now) {
+			result.SkippedFiles++
+			continue
+		}
+		result.CheckedFiles++
+		groups := make(map[int64][]string)
+		for _, addr := range addrs {
+			key := int64(-1) // the replica lacks the file
+			if f, ok := replicas[addr]; ok {
+				key = int64(f.Crc)
+			}
+			groups[key] = append(groups[key], addr)
+		}
+		if len(groups) == 1 {
+			continue
+		}
+		bad := divergentReplicas(groups)
+		file := &proto.DivergentFile{Name: name, Crcs: make(map[string]uint32, len(replicas)), DivergentAddrs: bad}
+		for addr, f := range replicas {
+			file.Crcs[addr] = f.Crc
+		}
+		result.DivergentFiles = append(result.DivergentFiles, file)
+		for _, addr := range bad {
+			divergent[addr] = true
+		}
+	}
+	for addr := range divergent {
+		result.DivergentAddrs = append(result.DivergentAddrs, addr)
+	}
+	sort.Strings(result.NoResponseAddrs)
+	sort.Strings(result.DivergentAddrs)
+	sort.Slice(result.DivergentFiles, func(i, j int) bool { return result.DivergentFiles[i].Name < result.DivergentFiles[j].Name })
+	result.Consistent = len(result.NoResponseAddrs) == 0 && len(result.DivergentAddrs) == 0
+}
+
+func isFileSettled(replicas map[string]*proto.File, now int64) bool {
+	for _, f := range replicas {
+		if f.Crc == 0 || f.Crc == EmptyCrcValue || now-f.Modified <= defaultIntervalToCheckCrc {
+			return false
+		}
+	}
+	return true
+}
+
+func divergentReplicas(groups map[int64][]string) (addrs []string) {
+	var majority int64
+	maxCount, tie := 0, false
+	for key, group := range groups {
+		if len(group) > maxCount {
+			majority, maxCount, tie = key, len(group), false
+		} else if len(group) == maxCount {
+			tie = true
+		}
+	}
+	for key, group := range groups {
+		if tie || key != majority {
+			addrs = append(addrs, group...)
+		}
+	}
+	sort.Strings(addrs)
+	return
+}
+
+// Ask the live replicas to report the checksums of their files by the load tasks, and compare them once all of them
+// have responded, or the timeout is reached.
+func (c *Cluster) verifyDataPartition(dp *DataPartition, timeout time.Duration) (result *proto.DataPartitionVerification, err error) {
+	dp.RLock()
+	isRecover, hosts := dp.isRecover, dp.Hosts
+	dp.RUnlock()
+	if isRecover {
+		return nil, fmt.Errorf("data partition[%v] is being recovered", dp.PartitionID)
+	}
+	tasks := dp.createLoadTasks()
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("data partition[%v] has no live replica", dp.PartitionID)
+	}
+	addrs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		addrs = append(addrs, task.OperatorAddr)
+	}
+	v := newDpVerification(addrs)
+	if _, loaded := c.dpVerifications.LoadOrStore(dp.PartitionID, v); loaded {
+		return nil, fmt.Errorf("data partition[%v] is being verified", dp.PartitionID)
+	}
+	defer c.dpVerifications.Delete(dp.PartitionID)
+	c.addDataNodeTasks(tasks)
+	select {
+	case <-v.done:
+	case <-time.After(timeout):
+		log.LogWarnf("action[verifyDataPartition] partitionID[%v] not all the replicas responded in %v", dp.PartitionID, timeout)
+	}
+	result = &proto.DataPartitionVerification{PartitionID: dp.PartitionID}
+	v.compare(result)
+	for _, host := range hosts {
+		if !contains(addrs, host) {
+			result.NoResponseAddrs = append(result.NoResponseAddrs, host)
+			result.Consistent = false
+		}
+	}
+	if len(result.DivergentAddrs) > 0 {
+		Warn(c.Name, fmt.Sprintf("action[verifyDataPartition] clusterID[%v] partitionID[%v] divergent replicas %v",
+			c.Name, dp.PartitionID, result.DivergentAddrs))
+	}
+	return
+}
+
+func (c *Cluster) collectDataPartitionVerification(nodeAddr string, resp *proto.LoadDataPartitionResponse) {
+	if value, ok := c.dpVerifications.Load(resp.PartitionId); ok {
+		value.(*dpVerification).collect(nodeAddr, resp)
+	}
+}
@@ -16,8 +16,10 @@ package master
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/util"
 	"github.com/cubefs/cubefs/util/log"
 )
@@ -92,17 +94,129 @@ func (c *Cluster) checkDiskRecoveryProgress() {
 	})
 }
 
-func (c *Cluster) decommissionDisk(dataNode *DataNode, badDiskPath string, badPartitions []*DataPartition) (err error) {
+// diskDecommission tracks a disk being decommissioned, the partitions not decommissioned yet can be canceled.
+type diskDecommission struct {
+	pending  int32
+	canceled int32
+	job      *asyncJob
+}
+
+func diskDecommissionKey(addr, diskPath string) string {
+	return fmt.Sprintf("%s:%s", addr, diskPath)
+}
+
+func (c *Cluster) decommissionDisk(dataNode *DataNode, badDiskPath string, badPartitions []*DataPartition) (jobID string, err error) {
 	msg := fmt.Sprintf("action[decommissionDisk], Node[%v] OffLine,disk[%v]", dataNode.Addr, badDiskPath)
 	log.LogWarn(msg)
 
+	key := diskDecommissionKey(dataNode.Addr, badDiskPath)
+	decommission := &diskDecommission{
+		pending: int32(len(badPartitions)),
+		job:     newAsyncJob(proto.JobDecommissionDisk, key, len(badPartitions)),
+	}
+	if _, loaded := c.diskDecommissions.LoadOrStore(key, decommission); loaded {
+		return "", fmt.Errorf("disk[%v] of node[%v] is being decommissioned", badDiskPath, dataNode.Addr)
+	}
+	defer c.diskDecommissions.Delete(key)
+	c.jobs.add(decommission.job)
+	jobID = decommission.job.id
+	defer func() { decommission.job.finish(err) }()
+
 	for _, dp := range badPartitions {
+		if atomic.LoadInt32(&decommission.canceled) == 1 {
+			err = fmt.Errorf("decommission of disk[%v] of node[%v] is canceled", badDiskPath, dataNode.Addr)
+			return
+		}
 		if err = c.decommissionDataPartition(dataNode.Addr, dp, diskOfflineErr); err != nil {
+			decommission.job.fail()
 			return
 		}
+		decommission.job.complete()
+		atomic.AddInt32(&decommission.pending, -1)
 	}
 	msg = fmt.Sprintf("action[decommissionDisk],clusterID[%v] Node[%v] OffLine success",
 		c.Name, dataNode.Addr)
 	Warn(c.Name, msg)
 	return
 }
+
+// Keep the data nodes from creating data partitions on the disk decommissioned, it is persisted with the node.
+func (c *Cluster) markDiskDecommissioned(dataNode *DataNode, diskPath string) (err error) {
+	if dataNode.isDiskDecommissioned(diskPath) {
+		return
+	}
+	dataNode.Lock()
+	oldDisks := dataNode.DecommissionedDisks
+	dataNode.DecommissionedDisks = append(append(make([]string, 0, len(oldDisks)+1), oldDisks...), diskPath)
+	dataNode.Unlock()
+	if err = c.syncUpdateDataNode(dataNode); err != nil {
+		dataNode.Lock()
+		dataNode.DecommissionedDisks = oldDisks
+		dataNode.Unlock()
+		log.LogErrorf("action[markDiskDecommissioned] node[%v] disk[%v] err[%v]", dataNode.Addr, diskPath, err)
+		return proto.ErrPersistenceByRaft
+	}
+	return
+}
+
+// Put the disk decommissioned back into service after it is repaired, the data partitions can be created on it again.
+// The data partitions moved off the disk stay on their new replicas.
+func (c *Cluster) recommissionDisk(addr, diskPath string) (disk *proto.DataNodeDisk, err error) {
+	var dataNode *DataNode
+	if dataNode, err = c.dataNode(addr); err != nil {
+		return
+	}
+	if _, ok := c.diskDecommissions.Load(diskDecommissionKey(addr, diskPath)); ok {
+		return nil, fmt.Errorf("disk[%v] of node[%v] is being decommissioned", diskPath, addr)
+	}
+	if !dataNode.isDiskDecommissioned(diskPath) {
+		return nil, fmt.Errorf("disk[%v] of node[%v] is not decommissioned", diskPath, addr)
+	}
+	dataNode.Lock()
+	oldDisks := dataNode.DecommissionedDisks
+	dataNode.DecommissionedDisks = make([]string, 0, len(oldDisks))
+	for _, path := range oldDisks {
+		if path != diskPath {
+			dataNode.DecommissionedDisks = append(dataNode.DecommissionedDisks, path)
+		}
+	}
+	dataNode.Unlock()
+	if err = c.syncUpdateDataNode(dataNode); err != nil {
+		dataNode.Lock()
+		dataNode.DecommissionedDisks = oldDisks
+		dataNode.Unlock()
+		log.LogErrorf("action[recommissionDisk] node[%v] disk[%v] err[%v]", addr, diskPath, err)
+		return nil, proto.ErrPersistenceByRaft
+	}
+	Warn(c.Name, fmt.Sprintf("action[recommissionDisk],clusterID[%v] Node[%v] disk[%v] is recommissioned", c.Name, addr, diskPath))
+	for _, d := range dataNode.disks() {
+		if d.Path == diskPath {
+			return d, nil
+		}
+	}
+	// the space of the disk is unknown until a verbose heartbeat reports it
+	return &proto.DataNodeDisk{Path: diskPath}, nil
+}
+
+// Stop decommissioning the disk, the partitions already decommissioned are kept on the new replicas,
+// and the recovery of them is no longer tracked. Return the number of the partitions canceled.
+func (c *Cluster) cancelDiskDecommission(addr, diskPath string) (jobID string, pending int, err error) {
+	key := diskDecommissionKey(addr, diskPath)
+	value, ok := c.diskDecommissions.Load(key)
+	if !ok {
+		return "", 0, fmt.Errorf("disk[%v] of node[%v] is not being decommissioned", diskPath, addr)
+	}
+	decommission := value.(*diskDecommission)
+	atomic.StoreInt32(&decommission.canceled, 1)
+	pending = int(atomic.LoadInt32(&decommission.pending))
+	jobID = decommission.job.id
+	decommission.job.cancel()
+
+	c.badPartitionMutex.Lock()
+	c.BadDataPartitionIds.Delete(key)
+	c.badPartitionMutex.Unlock()
+	msg := fmt.Sprintf("action[cancelDiskDecommission],clusterID[%v] Node[%v] disk[%v] canceled, pending partitions[%v]",
+		c.Name, addr, diskPath, pending)
+	Warn(c.Name, msg)
+	return
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const (
+	eventSubscriberBufferSize = 64
+	eventKeepaliveInterval    = 15 * time.Second
+)
+
+// eventHub fans the cluster events out to the subscribers, i.e. the clients of /events.
+// The events are published on the master where they happen, they are neither persisted nor replicated by raft.
+type eventHub struct {
+	sync.RWMutex
+	subscribers map[chan *proto.ClusterEvent]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: make(map[chan *proto.ClusterEvent]struct{})}
+}
+
+// The returned function has to be called once the subscriber goes away, it stops the delivery of the events.
+func (hub *eventHub) subscribe() (events <-chan *proto.ClusterEvent, unsubscribe func()) {
+	ch := make(chan *proto.ClusterEvent, eventSubscriberBufferSize)
+	hub.Lock()
+	hub.subscribers[ch] = struct{}{}
+	hub.Unlock()
+	return ch, func() {
+		hub.Lock()
+		delete(hub.subscribers, ch)
+		hub.Unlock()
+	}
+}
+
+// The publisher is never blocked by a slow subscriber, the events it has no room for are dropped.
+func (hub *eventHub) publish(event *proto.ClusterEvent) {
+	hub.RLock()
+	defer hub.RUnlock()
+	for ch := range hub.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.LogWarnf("action[publishEvent] subscriber is too slow, drop event type[%v] target[%v]", event.Type, event.Target)
+		}
+	}
+}
+
+func (c *Cluster) publishEvent(eventType, target, msg string) {
+	c.events.publish(&proto.ClusterEvent{
+		Time:   time.Now().Unix(),
+		Type:   eventType,
+		Target: target,
+		Msg:    msg,
+	})
+}
@@ -200,7 +200,7 @@ func (m *ClusterService) decommissionDisk(ctx context.Context, args struct {
 	}
 	rstMsg := fmt.Sprintf("receive decommissionDisk node[%v] disk[%v], badPartitionIds[%v] has offline successfully",
 		node.Addr, args.DiskPath, badPartitionIds)
-	if err = m.cluster.decommissionDisk(node, args.DiskPath, badPartitions); err != nil {
+	if _, err = m.cluster.decommissionDisk(node, args.DiskPath, badPartitions); err != nil {
 		return nil, err
 	}
 	Warn(m.cluster.Name, rstMsg)
@@ -309,12 +309,12 @@ func (m *ClusterService) getTopology(ctx context.Context, args struct{}) (*proto
 			cv.NodeSet[ns.ID] = nsView
 			ns.dataNodes.Range(func(key, value interface{}) bool {
 				dataNode := value.(*DataNode)
-				nsView.DataNodes = append(nsView.DataNodes, proto.NodeView{ID: dataNode.ID, Addr: dataNode.Addr, Status: dataNode.isActive, IsWritable: dataNode.isWriteAble()})
+				nsView.DataNodes = append(nsView.DataNodes, dataNode.toNodeView())
 				return true
 			})
 			ns.metaNodes.Range(func(key, value interface{}) bool {
 				metaNode := value.(*MetaNode)
-				nsView.MetaNodes = append(nsView.MetaNodes, proto.NodeView{ID: metaNode.ID, Addr: metaNode.Addr, Status: metaNode.IsActive, IsWritable: metaNode.isWritable()})
+				nsView.MetaNodes = append(nsView.MetaNodes, metaNode.toNodeView())
 				return true
 			})
 		}
@@ -476,14 +476,12 @@ func (m *ClusterService) addRaftNode(ctx context.Context, args struct {
 	return proto.Success("success"), nil
 }
 
-// Turn on or off the automatic allocation of the data partitions.
-// If DisableAutoAllocate == off, then we WILL NOT automatically allocate new data partitions for the volume when:
-// 	1. the used space is below the max capacity,
-//	2. and the number of r&w data partition is less than 20.
+// Turn on or off the automatic allocation of the data partitions, Status=true freezes the cluster.
+// If DisableAutoAllocate == off, then we WILL automatically allocate new data partitions for the volume when:
+// 	1. the used space is below the ratio of the capacity, 1 by default,
+//	2. and the number of r&w data partition is less than the threshold, 10 by default.
 //
-// If DisableAutoAllocate == on, then we WILL automatically allocate new data partitions for the volume when:
-// 	1. the used space is below the max capacity,
-//	2. and the number of r&w data partition is less than 20.
+// If DisableAutoAllocate == on, then we WILL NOT automatically allocate new data partitions for the volume.
 func (m *ClusterService) clusterFreeze(ctx context.Context, args struct {
 	Status bool
 }) (*proto.GeneralResp, error) {
@@ -192,7 +192,7 @@ func (s *VolumeService) createVolume(ctx context.Context, args struct {
 
 	vol, err := s.cluster.createVol(args.Name, args.Owner, args.ZoneName, args.Description, int(args.MpCount),
 		int(args.DpReplicaNum), int(args.DataPartitionSize), int(args.Capacity),
-		args.FollowerRead, args.Authenticate, args.CrossZone, args.DefaultPriority)
+		args.FollowerRead, args.Authenticate, args.CrossZone, args.DefaultPriority, nil, 0, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -307,6 +307,7 @@ func (s *VolumeService) updateVolume(ctx context.Context, args struct {
 	if args.Description != nil {
 		newArgs.description = *args.Description
 	}
+	newArgs.caller = uid
 
 	if err = s.cluster.updateVol(args.Name, args.AuthKey, newArgs); err != nil {
 		return nil, err
@@ -0,0 +1,83 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	corsAllowAll        = "*"
+	corsReadMethods     = "GET, HEAD, OPTIONS"
+	corsMutatingMethods = "GET, HEAD, POST, OPTIONS"
+	corsAllowHeaders    = "Authorization, Content-Type, X-Request-ID"
+	corsExposeHeaders   = "X-Request-ID"
+	corsMaxAgeSec       = "600"
+)
+
+func (m *Server) isCORSOriginAllowed(origin string) bool {
+	for _, allowed := range m.config.corsAllowedOrigins {
+		if allowed == corsAllowAll || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Only the read APIs are served to the other origins, unless corsMutatingAPIs is set in the config.
+func (m *Server) isCORSEnabled(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !m.isCORSOriginAllowed(origin) {
+		return false
+	}
+	return m.config.corsMutatingAPIs || !isMutatingAPI(r)
+}
+
+// Wrap the router to let the browsers of the allowed origins call the APIs directly. It is outside of the router,
+// since the preflight requests by OPTIONS match no route.
+func (m *Server) newCORSHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if len(m.config.corsAllowedOrigins) == 0 || r.Header.Get("Origin") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		if !m.isCORSEnabled(r) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		header.Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+		methods := corsReadMethods
+		if m.config.corsMutatingAPIs {
+			methods = corsMutatingMethods
+		}
+		header.Set("Access-Control-Allow-Methods", methods)
+		header.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+		header.Set("Access-Control-Max-Age", corsMaxAgeSec)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+	"github.com/gorilla/mux"
+)
+
+// gzipResponseWriter holds the response until the handler returns, so that it is compressed only if it is large enough.
+// The replies are marshaled in the memory before they are written, holding them does not change much.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	buf        bytes.Buffer
+	statusCode int
+}
+
+func (gw *gzipResponseWriter) WriteHeader(statusCode int) {
+	if gw.statusCode == 0 {
+		gw.statusCode = statusCode
+	}
+}
+
+func (gw *gzipResponseWriter) Write(p []byte) (int, error) {
+	if gw.statusCode == 0 {
+		gw.statusCode = http.StatusOK
+	}
+	return gw.buf.Write(p)
+}
+
+// Write the response held, compressed if it has at least minBytes and is not encoded yet, e.g. by the leader it is proxied from.
+func (gw *gzipResponseWriter) finish(minBytes int) (err error) {
+	if gw.statusCode == 0 {
+		return
+	}
+	body := gw.buf.Bytes()
+	header := gw.ResponseWriter.Header()
+	if len(body) >= minBytes && header.Get("Content-Encoding") == "" && bodyAllowedForStatus(gw.statusCode) {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err = zw.Write(body); err == nil {
+			err = zw.Close()
+		}
+		if err != nil {
+			return
+		}
+		body = compressed.Bytes()
+		header.Set("Content-Encoding", "gzip")
+		header.Add("Vary", "Accept-Encoding")
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	gw.ResponseWriter.WriteHeader(gw.statusCode)
+	_, err = gw.ResponseWriter.Write(body)
+	return
+}
+
+func bodyAllowedForStatus(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode != http.StatusNoContent && statusCode != http.StatusNotModified
+}
+
+// The client accepts gzip unless it is not listed in Accept-Encoding or its q is 0.
+func acceptsGzip(r *http.Request) bool {
+	for _, value := range strings.Split(r.Header.Get("Accept-Encoding"), commaSplit) {
+		parts := strings.Split(value, ";")
+		if coding := strings.TrimSpace(parts[0]); coding != "gzip" && coding != "*" {
+			continue
+		}
+		for _, param := range parts[1:] {
+			if q := strings.TrimSpace(param); strings.HasPrefix(q, "q=") {
+				if weight, err := strconv.ParseFloat(q[2:], 64); err == nil && weight == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Compress the responses of at least gzipMinBytes for the clients accepting gzip, the smaller ones are sent as they are.
+// The event stream is never held, its events are sent as they happen.
+func (m *Server) registerGzipMiddleware(route *mux.Router) {
+	var compressor mux.MiddlewareFunc = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if m.config.gzipMinBytes <= 0 || r.Method == http.MethodHead || !acceptsGzip(r) ||
+					r.URL.Path == proto.AdminEvents {
+					next.ServeHTTP(w, r)
+					return
+				}
+				gw := &gzipResponseWriter{ResponseWriter: w}
+				next.ServeHTTP(gw, r)
+				if err := gw.finish(m.config.gzipMinBytes); err != nil {
+					log.LogErrorf("action[compressor] write response of [%v] to remoteAddr[%v] requestID[%v] err[%v]", r.URL, r.RemoteAddr, requestID(r), err)
+				}
+			})
+	}
+	route.Use(compressor)
+}
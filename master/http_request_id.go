@@ -0,0 +1,69 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/gorilla/mux"
+)
+
+const maxRequestIDLen = 128
+
+var requestSeq uint64
+
+func newRequestID() string {
+	return fmt.Sprintf("%x-%x", time.Now().UnixNano(), atomic.AddUint64(&requestSeq, 1))
+}
+
+// The request id given by the client is taken only if it is short and printable, since it goes into the log lines.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// The id of the request set by the request id middleware, to correlate the log lines of the request.
+func requestID(r *http.Request) string {
+	return r.Header.Get(proto.HeadRequestID)
+}
+
+// Take the request id of the client or generate one, and echo it in the response. It is kept in the request header,
+// so that the request proxied to the leader carries the same id.
+func (m *Server) registerRequestIDMiddleware(route *mux.Router) {
+	var tagger mux.MiddlewareFunc = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				id := r.Header.Get(proto.HeadRequestID)
+				if !isValidRequestID(id) {
+					id = newRequestID()
+					r.Header.Set(proto.HeadRequestID, id)
+				}
+				w.Header().Set(proto.HeadRequestID, id)
+				next.ServeHTTP(w, r)
+			})
+	}
+	route.Use(tagger)
+}
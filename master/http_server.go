@@ -35,11 +35,14 @@ import (
 func (m *Server) startHTTPService(modulename string, cfg *config.Config) {
 	router := mux.NewRouter().SkipClean(true)
 	m.registerAPIRoutes(router)
+	m.registerRequestIDMiddleware(router)
+	m.registerGzipMiddleware(router)
+	m.registerAuthMiddleware(router)
 	m.registerAPIMiddleware(router)
 	exporter.InitWithRouter(modulename, cfg, router, m.port)
 	var server = &http.Server{
 		Addr:    colonSplit + m.port,
-		Handler: router,
+		Handler: m.newCORSHandler(router),
 	}
 	var serveAPI = func() {
 		if err := server.ListenAndServe(); err != nil {
@@ -68,12 +71,25 @@ func (m *Server) isFollowerRead(r *http.Request) (followerRead bool) {
 	return
 }
 
+// The APIs served by every master without forwarding to the leader, they are matched by the route name.
+var localAPIs = map[string]bool{
+	proto.AdminGetIP:         true,
+	proto.AdminLivez:         true,
+	proto.AdminReadyz:        true,
+	proto.AdminSetLogLevel:   true,
+	proto.AdminGetLogLevel:   true,
+	proto.GetRaftStatus:      true,
+	proto.TryToRaftLeader:    true,
+	proto.SnapshotRaftLog:    true,
+	proto.StepDownRaftLeader: true,
+}
+
 func (m *Server) registerAPIMiddleware(route *mux.Router) {
 	var interceptor mux.MiddlewareFunc = func(next http.Handler) http.Handler {
 		return http.HandlerFunc(
 			func(w http.ResponseWriter, r *http.Request) {
-				log.LogDebugf("action[interceptor] request, method[%v] path[%v] query[%v]", r.Method, r.URL.Path, r.URL.Query())
-				if mux.CurrentRoute(r).GetName() == proto.AdminGetIP {
+				log.LogDebugf("action[interceptor] request, method[%v] path[%v] query[%v] requestID[%v]", r.Method, r.URL.Path, r.URL.Query(), requestID(r))
+				if localAPIs[mux.CurrentRoute(r).GetName()] {
 					next.ServeHTTP(w, r)
 					return
 				}
@@ -81,19 +97,26 @@ func (m *Server) registerAPIMiddleware(route *mux.Router) {
 				isFollowerRead := m.isFollowerRead(r)
 				if m.partition.IsRaftLeader() || isFollowerRead {
 					if m.metaReady || isFollowerRead {
-						log.LogDebugf("action[interceptor] request, method[%v] path[%v] query[%v]", r.Method, r.URL.Path, r.URL.Query())
+						if m.rejectInMaintenance(w, r) {
+							return
+						}
+						log.LogDebugf("action[interceptor] request, method[%v] path[%v] query[%v] requestID[%v]", r.Method, r.URL.Path, r.URL.Query(), requestID(r))
 						next.ServeHTTP(w, r)
 						return
 					}
-					log.LogWarnf("action[interceptor] leader meta has not ready")
+					log.LogWarnf("action[interceptor] leader meta has not ready, requestID[%v]", requestID(r))
 					http.Error(w, m.leaderInfo.addr, http.StatusBadRequest)
 					return
 				}
 				if m.leaderInfo.addr == "" {
-					log.LogErrorf("action[interceptor] no leader,request[%v]", r.URL)
+					log.LogErrorf("action[interceptor] no leader,request[%v] requestID[%v]", r.URL, requestID(r))
 					http.Error(w, "no leader", http.StatusBadRequest)
 					return
 				}
+				if isMutatingAPI(r) {
+					m.redirectToLeader(w, r)
+					return
+				}
 				m.proxy(w, r)
 			})
 	}
@@ -116,12 +139,113 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 		Methods(http.MethodGet).
 		Path(proto.AdminGetIP).
 		HandlerFunc(m.getIPAddr)
+	router.NewRoute().Name(proto.AdminLivez).
+		Methods(http.MethodGet).
+		Path(proto.AdminLivez).
+		HandlerFunc(m.livez)
+	router.NewRoute().Name(proto.AdminReadyz).
+		Methods(http.MethodGet).
+		Path(proto.AdminReadyz).
+		HandlerFunc(m.readyz)
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.AdminGetCluster).
 		HandlerFunc(m.getCluster)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetConfig).
+		HandlerFunc(m.getConfig)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminMetrics).
+		HandlerFunc(m.getMetrics)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminClusterFreeze).
 		HandlerFunc(m.setupAutoAllocation)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetMaintenance).
+		HandlerFunc(m.setMaintenance)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetAllocationStrategy).
+		HandlerFunc(m.setAllocationStrategy)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetMaxVolumes).
+		HandlerFunc(m.setMaxVolumes)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetDefaultZone).
+		HandlerFunc(m.setDefaultZone)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetHeartbeatVerbosity).
+		HandlerFunc(m.setHeartbeatVerbosity)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetAutoAllocThreshold).
+		HandlerFunc(m.setAutoAllocThreshold)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetAutoRebalancePolicy).
+		HandlerFunc(m.setAutoRebalancePolicy)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetAutoDecommission).
+		HandlerFunc(m.setAutoDecommission)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetClusterConfig).
+		HandlerFunc(m.getClusterConfig)
+	router.NewRoute().Methods(http.MethodPost).
+		Path(proto.AdminBatchSetNodeTags).
+		HandlerFunc(m.batchSetNodeTags)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetAuditByAddr).
+		HandlerFunc(m.getAuditByAddr)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetAuditLog).
+		HandlerFunc(m.getAuditLog)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminEvents).
+		HandlerFunc(m.streamEvents)
+	router.NewRoute().Name(proto.AdminSetLogLevel).
+		Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetLogLevel).
+		HandlerFunc(m.setLogLevel)
+	router.NewRoute().Name(proto.AdminGetLogLevel).
+		Methods(http.MethodGet).
+		Path(proto.AdminGetLogLevel).
+		HandlerFunc(m.getLogLevel)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminPreCheckDecommission).
+		HandlerFunc(m.preCheckDecommission)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminSimulateNodeSetFailure).
+		HandlerFunc(m.simulateNodeSetFailure)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminCreateNodeSet).
+		HandlerFunc(m.createNodeSet)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetNodeSet).
+		HandlerFunc(m.getNodeSet)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminMoveNodeToNodeSet).
+		HandlerFunc(m.moveNodeToNodeSet)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetBadPartitionTrend).
+		HandlerFunc(m.getBadPartitionTrend)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminReservePartitionIDs).
+		HandlerFunc(m.reservePartitionIDs)
+	router.NewRoute().Name(proto.GetRaftStatus).
+		Methods(http.MethodGet).
+		Path(proto.GetRaftStatus).
+		HandlerFunc(m.getRaftStatus)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TransferRaftLeader).
+		HandlerFunc(m.transferRaftLeader)
+	router.NewRoute().Name(proto.TryToRaftLeader).
+		Methods(http.MethodGet, http.MethodPost).
+		Path(proto.TryToRaftLeader).
+		HandlerFunc(m.tryToRaftLeader)
+	router.NewRoute().Name(proto.SnapshotRaftLog).
+		Methods(http.MethodGet, http.MethodPost).
+		Path(proto.SnapshotRaftLog).
+		HandlerFunc(m.snapshotRaftLog)
+	router.NewRoute().Name(proto.StepDownRaftLeader).
+		Methods(http.MethodGet, http.MethodPost).
+		Path(proto.StepDownRaftLeader).
+		HandlerFunc(m.stepDownRaftLeader)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AddRaftNode).
 		HandlerFunc(m.addRaftNode)
@@ -129,17 +253,32 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 		Path(proto.RemoveRaftNode).
 		HandlerFunc(m.removeRaftNode)
 	router.NewRoute().Methods(http.MethodGet).Path(proto.AdminClusterStat).HandlerFunc(m.clusterStat)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetCapacityForecast).
+		HandlerFunc(m.getCapacityForecast)
 
 	// volume management APIs
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminCreateVol).
 		HandlerFunc(m.createVol)
+	router.NewRoute().Methods(http.MethodPost).
+		Path(proto.AdminBatchCreateVol).
+		HandlerFunc(m.batchCreateVol)
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.AdminGetVol).
 		HandlerFunc(m.getVolSimpleInfo)
+	router.NewRoute().Methods(http.MethodHead).
+		Path(proto.AdminGetVol).
+		HandlerFunc(m.headVol)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminDeleteVol).
 		HandlerFunc(m.markDeleteVol)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminUndeleteVol).
+		HandlerFunc(m.undeleteVol)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminRenameVol).
+		HandlerFunc(m.renameVol)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminUpdateVol).
 		HandlerFunc(m.updateVol)
@@ -149,6 +288,30 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminVolExpand).
 		HandlerFunc(m.volExpand)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminExportVol).
+		HandlerFunc(m.exportVol)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetVolHistory).
+		HandlerFunc(m.getVolHistory)
+	router.NewRoute().Methods(http.MethodPost).
+		Path(proto.AdminImportVol).
+		HandlerFunc(m.importVol)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetVolAllocPriority).
+		HandlerFunc(m.setVolAllocationPriority)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetVolIOPriority).
+		HandlerFunc(m.setVolIOPriority)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSetVolReadOnly).
+		HandlerFunc(m.setVolReadOnly)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetVolSkew).
+		HandlerFunc(m.getVolSkew)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminPreviewVolDeletion).
+		HandlerFunc(m.previewVolDeletion)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.ClientVol).
 		HandlerFunc(m.getVol)
@@ -161,6 +324,9 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.AdminListVols).
 		HandlerFunc(m.listVols)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetAllVols).
+		HandlerFunc(m.getAllVols)
 
 	// node task response APIs
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
@@ -177,6 +343,9 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminDecommissionMetaPartition).
 		HandlerFunc(m.decommissionMetaPartition)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminSplitMetaPartition).
+		HandlerFunc(m.splitMetaPartition)
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.ClientMetaPartitions).
 		HandlerFunc(m.getMetaPartitions)
@@ -206,12 +375,39 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminLoadDataPartition).
 		HandlerFunc(m.loadDataPartition)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminVerifyDataPartition).
+		HandlerFunc(m.verifyDataPartition)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminDecommissionDataPartition).
 		HandlerFunc(m.decommissionDataPartition)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminMigrateDataPartition).
+		HandlerFunc(m.migrateDataPartition)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminRebalanceDataPartitions).
+		HandlerFunc(m.rebalanceDataPartitions)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.AdminBatchDecommissionDps).
+		HandlerFunc(m.batchDecommissionDataPartition)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminDiagnoseDataPartition).
 		HandlerFunc(m.diagnoseDataPartition)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetOverReplicatedDps).
+		HandlerFunc(m.getOverReplicatedPartitions)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetUnderReplicated).
+		HandlerFunc(m.getUnderReplicatedPartitions)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetDecommissioningNodes).
+		HandlerFunc(m.getDecommissioningNodes)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetReplicaLag).
+		HandlerFunc(m.getReplicaLag)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetLaggingReplicas).
+		HandlerFunc(m.getLaggingReplicas)
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.ClientDataPartitions).
 		HandlerFunc(m.getDataPartitions)
@@ -228,6 +424,9 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.GetMetaNode).
 		HandlerFunc(m.getMetaNode)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.GetMetaNodePartitions).
+		HandlerFunc(m.getMetaNodePartitions)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminSetMetaNodeThreshold).
 		HandlerFunc(m.setMetaNodeThreshold)
@@ -257,12 +456,30 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.MigrateDataNode).
 		HandlerFunc(m.migrateDataNodeHandler)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.CancelDecommissionDataNode).
+		HandlerFunc(m.cancelDecommissionDataNode)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetJob).
+		HandlerFunc(m.getJob)
 	router.NewRoute().Methods(http.MethodGet).
 		Path(proto.GetDataNode).
 		HandlerFunc(m.getDataNode)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.GetDataNodePartitions).
+		HandlerFunc(m.getDataNodePartitions)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.GetDataNodeDisks).
+		HandlerFunc(m.getDataNodeDisks)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.DecommissionDisk).
 		HandlerFunc(m.decommissionDisk)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.CancelDecommissionDisk).
+		HandlerFunc(m.cancelDecommissionDisk)
+	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
+		Path(proto.RecommissionDisk).
+		HandlerFunc(m.recommissionDisk)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminSetNodeInfo).
 		HandlerFunc(m.setNodeInfoHandler)
@@ -278,6 +495,9 @@ func (m *Server) registerAPIRoutes(router *mux.Router) {
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminGetNodeSetGrpInfo).
 		HandlerFunc(m.getNodeSetGrpInfoHandler)
+	router.NewRoute().Methods(http.MethodGet).
+		Path(proto.AdminGetNodeSetStats).
+		HandlerFunc(m.getNodeSetStats)
 	router.NewRoute().Methods(http.MethodGet, http.MethodPost).
 		Path(proto.AdminUpdateNodeSetCapcity).
 		HandlerFunc(m.updateNodeSetCapacityHandler)
@@ -379,13 +599,29 @@ func ErrResponse(w http.ResponseWriter, err error) {
 	}
 }
 
+// The request id is set on the reply by the follower already, so the one echoed by the leader is dropped,
+// or the proxy would add it to the reply twice.
 func (m *Server) newReverseProxy() *httputil.ReverseProxy {
-	return &httputil.ReverseProxy{Director: func(request *http.Request) {
-		request.URL.Scheme = "http"
-		request.URL.Host = m.leaderInfo.addr
-	}}
+	return &httputil.ReverseProxy{
+		Director: func(request *http.Request) {
+			request.URL.Scheme = "http"
+			request.URL.Host = m.leaderInfo.addr
+		},
+		ModifyResponse: func(response *http.Response) error {
+			response.Header.Del(proto.HeadRequestID)
+			return nil
+		},
+	}
 }
 
 func (m *Server) proxy(w http.ResponseWriter, r *http.Request) {
 	m.reverseProxy.ServeHTTP(w, r)
 }
+
+// Only the leader can apply the changes by raft, so the follower redirects the mutating requests to it
+// with the same path and query.
+func (m *Server) redirectToLeader(w http.ResponseWriter, r *http.Request) {
+	leaderURL := fmt.Sprintf("http://%v%v", m.leaderInfo.addr, r.URL.RequestURI())
+	log.LogInfof("action[redirectToLeader] redirect [%v] from remoteAddr[%v] requestID[%v] to [%v]", r.URL, r.RemoteAddr, requestID(r), leaderURL)
+	http.Redirect(w, r, leaderURL, http.StatusTemporaryRedirect)
+}
@@ -139,6 +139,32 @@ errHandler:
 	return
 }
 
+// allocate count continuous data partition ids [start, end] by one raft submission
+func (alloc *IDAllocator) allocateDataPartitionIDs(count uint64) (start, end uint64, err error) {
+	alloc.dpIDLock.Lock()
+	defer alloc.dpIDLock.Unlock()
+	var cmd []byte
+	metadata := new(RaftCmd)
+	start = atomic.LoadUint64(&alloc.dataPartitionID) + 1
+	end = start + count - 1
+	metadata.Op = opSyncAllocDataPartitionID
+	metadata.K = maxDataPartitionIDKey
+	value := strconv.FormatUint(end, 10)
+	metadata.V = []byte(value)
+	cmd, err = metadata.Marshal()
+	if err != nil {
+		goto errHandler
+	}
+	if _, err = alloc.partition.Submit(cmd); err != nil {
+		goto errHandler
+	}
+	alloc.setDataPartitionID(end)
+	return
+errHandler:
+	log.LogErrorf("action[allocateDataPartitionIDs] err:%v", err.Error())
+	return
+}
+
 func (alloc *IDAllocator) allocateMetaPartitionID() (partitionID uint64, err error) {
 	alloc.mpIDLock.Lock()
 	defer alloc.mpIDLock.Unlock()
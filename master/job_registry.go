@@ -0,0 +1,192 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+)
+
+var jobSeq uint64
+
+func newJobID() string {
+	return fmt.Sprintf("%d-%d", time.Now().Unix(), atomic.AddUint64(&jobSeq, 1))
+}
+
+// asyncJob tracks the progress of a long running operation, such as the decommission of a disk or a node.
+type asyncJob struct {
+	sync.RWMutex
+	id        string
+	jobType   string
+	target    string
+	total     int32
+	completed int32
+	failed    int32
+	state     string
+	msg       string
+	note      string
+	startTime time.Time
+	endTime   time.Time
+}
+
+func (j *asyncJob) complete() {
+	atomic.AddInt32(&j.completed, 1)
+}
+
+func (j *asyncJob) fail() {
+	atomic.AddInt32(&j.failed, 1)
+}
+
+// Mark the job canceled, it is kept canceled when it finishes later.
+func (j *asyncJob) cancel() {
+	j.Lock()
+	defer j.Unlock()
+	if j.state == proto.JobStateRunning {
+		j.state = proto.JobStateCanceled
+	}
+}
+
+// Attach a note to the job, such as the limits of it, which is shown along with the state.
+func (j *asyncJob) setNote(note string) {
+	j.Lock()
+	defer j.Unlock()
+	j.note = note
+}
+
+func (j *asyncJob) finish(err error) {
+	j.Lock()
+	defer j.Unlock()
+	j.endTime = time.Now()
+	if err != nil {
+		j.msg = err.Error()
+	}
+	if j.state != proto.JobStateRunning {
+		return
+	}
+	if err != nil {
+		j.state = proto.JobStateFailed
+		return
+	}
+	j.state = proto.JobStateSucceeded
+}
+
+func (j *asyncJob) isFinished(now time.Time, ttl time.Duration) (finished, expired bool) {
+	j.RLock()
+	defer j.RUnlock()
+	if j.state == proto.JobStateRunning || j.endTime.IsZero() {
+		return false, false
+	}
+	return true, now.Sub(j.endTime) > ttl
+}
+
+func (j *asyncJob) toView() *proto.JobView {
+	j.RLock()
+	defer j.RUnlock()
+	view := &proto.JobView{
+		ID:        j.id,
+		Type:      j.jobType,
+		Target:    j.target,
+		Total:     int(atomic.LoadInt32(&j.total)),
+		Completed: int(atomic.LoadInt32(&j.completed)),
+		Failed:    int(atomic.LoadInt32(&j.failed)),
+		State:     j.state,
+		Msg:       j.msg,
+		Note:      j.note,
+		StartTime: j.startTime.Format(time.RFC3339),
+	}
+	if !j.endTime.IsZero() {
+		view.EndTime = j.endTime.Format(time.RFC3339)
+	}
+	return view
+}
+
+// jobRegistry keeps the jobs in the memory of the master, the finished ones are dropped after ttl.
+type jobRegistry struct {
+	sync.Mutex
+	ttl  time.Duration
+	jobs map[string]*asyncJob
+}
+
+func newJobRegistry(ttl time.Duration) *jobRegistry {
+	return &jobRegistry{ttl: ttl, jobs: make(map[string]*asyncJob)}
+}
+
+func newAsyncJob(jobType, target string, total int) *asyncJob {
+	return &asyncJob{
+		id:        newJobID(),
+		jobType:   jobType,
+		target:    target,
+		total:     int32(total),
+		state:     proto.JobStateRunning,
+		startTime: time.Now(),
+	}
+}
+
+func (jr *jobRegistry) register(jobType, target string, total int) (job *asyncJob) {
+	job = newAsyncJob(jobType, target, total)
+	jr.add(job)
+	return
+}
+
+// Add the job created by newAsyncJob, once the operation it tracks is sure to run.
+func (jr *jobRegistry) add(job *asyncJob) {
+	jr.Lock()
+	defer jr.Unlock()
+	jr.prune()
+	jr.jobs[job.id] = job
+}
+
+func (jr *jobRegistry) get(id string) (job *asyncJob, err error) {
+	jr.Lock()
+	defer jr.Unlock()
+	jr.prune()
+	job, ok := jr.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job[%v] not exists", id)
+	}
+	return
+}
+
+// Return the views of all the jobs retained, in the order they were started.
+func (jr *jobRegistry) list() (views []*proto.JobView) {
+	jr.Lock()
+	jr.prune()
+	jobs := make([]*asyncJob, 0, len(jr.jobs))
+	for _, job := range jr.jobs {
+		jobs = append(jobs, job)
+	}
+	jr.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].startTime.Before(jobs[j].startTime) })
+	views = make([]*proto.JobView, 0, len(jobs))
+	for _, job := range jobs {
+		views = append(views, job.toView())
+	}
+	return
+}
+
+// The caller should hold the lock.
+func (jr *jobRegistry) prune() {
+	now := time.Now()
+	for id, job := range jr.jobs {
+		if _, expired := job.isFinished(now, jr.ttl); expired {
+			delete(jr.jobs, id)
+		}
+	}
+}
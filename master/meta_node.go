@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
 )
 
 // MetaNode defines the structure of a meta node
@@ -45,6 +46,7 @@ type MetaNode struct {
 	PersistenceMetaPartitions []uint64
 	RdOnly                    bool
 	MigrateLock               sync.RWMutex
+	Tags                      []string // the labels used by the topology-aware placement
 }
 
 func newMetaNode(addr, zoneName, clusterID string) (node *MetaNode) {
@@ -98,6 +100,20 @@ func (metaNode *MetaNode) isWritable() (ok bool) {
 	return
 }
 
+func (metaNode *MetaNode) toNodeView() (view proto.NodeView) {
+	view = proto.NodeView{ID: metaNode.ID, Addr: metaNode.Addr, IsWritable: metaNode.isWritable()}
+	metaNode.RLock()
+	defer metaNode.RUnlock()
+	view.Status = metaNode.IsActive
+	view.Total = metaNode.Total
+	view.Used = metaNode.Used
+	view.AvailableGB = metaNode.MaxMemAvailWeight / util.GB
+	view.Ratio = metaNode.Ratio
+	view.Threshold = metaNode.Threshold
+	view.ReportTime, view.InactiveSeconds = reportTimeView(metaNode.ReportTime, metaNode.IsActive)
+	return
+}
+
 // A carry node is the meta node whose carry is greater than one.
 func (metaNode *MetaNode) isCarryNode() (ok bool) {
 	metaNode.RLock()
@@ -105,11 +121,14 @@ func (metaNode *MetaNode) isCarryNode() (ok bool) {
 	return metaNode.Carry >= 1
 }
 
-func (metaNode *MetaNode) setNodeActive() {
+// Return true if the node has just become active.
+func (metaNode *MetaNode) setNodeActive() (activated bool) {
 	metaNode.Lock()
 	defer metaNode.Unlock()
 	metaNode.ReportTime = time.Now()
+	activated = !metaNode.IsActive
 	metaNode.IsActive = true
+	return
 }
 
 func (metaNode *MetaNode) updateMetric(resp *proto.MetaNodeHeartbeatResponse, threshold float32) {
@@ -136,19 +155,23 @@ func (metaNode *MetaNode) reachesThreshold() bool {
 	return float32(float64(metaNode.Used)/float64(metaNode.Total)) > metaNode.Threshold
 }
 
-func (metaNode *MetaNode) createHeartbeatTask(masterAddr string) (task *proto.AdminTask) {
+func (metaNode *MetaNode) createHeartbeatTask(masterAddr string, verbose bool) (task *proto.AdminTask) {
 	request := &proto.HeartBeatRequest{
 		CurrTime:   time.Now().Unix(),
 		MasterAddr: masterAddr,
+		Verbose:    verbose,
 	}
 	task = proto.NewAdminTask(proto.OpMetaNodeHeartbeat, metaNode.Addr, request)
 	return
 }
 
-func (metaNode *MetaNode) checkHeartbeat() {
+// Return true if the node has just become inactive.
+func (metaNode *MetaNode) checkHeartbeat() (inactivated bool) {
 	metaNode.Lock()
 	defer metaNode.Unlock()
 	if time.Since(metaNode.ReportTime) > time.Second*time.Duration(defaultNodeTimeOutSec) {
+		inactivated = metaNode.IsActive
 		metaNode.IsActive = false
 	}
+	return
 }
@@ -142,6 +142,10 @@ func (mp *MetaPartition) canSplit(end uint64) (err error) {
 		err = fmt.Errorf("end[%v] less than mp.start[%v]", end, mp.Start)
 		return
 	}
+	if end >= mp.End {
+		err = fmt.Errorf("end[%v] not less than mp.end[%v]", end, mp.End)
+		return
+	}
 	// overflow
 	if end > (defaultMaxMetaPartitionInodeID - defaultMetaPartitionInodeIDStep) {
 		msg := fmt.Sprintf("action[updateInodeIDRange] vol[%v] partitionID[%v] nextStart[%v] "+
@@ -176,6 +176,12 @@ func (c *Cluster) checkMetaPartitionRecoveryProgress() {
 				continue
 			}
 
+			// the replica on the node is lost, but the node is still a host of the partition
+			if addr, ok := key.(string); ok && contains(partition.Hosts, addr) && partition.isMissingReplica(addr) {
+				newBadMpIds = append(newBadMpIds, partitionID)
+				continue
+			}
+
 			if partition.getMinusOfMaxInodeID() < defaultMinusOfMaxInodeID {
 				partition.IsRecover = false
 				partition.RLock()
@@ -1,8 +1,10 @@
 package master
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/cubefs/cubefs/proto"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -106,3 +108,72 @@ func decommissionMetaPartition(vol *Vol, id uint64, t *testing.T) {
 		return
 	}
 }
+
+func TestSplitMetaPartition(t *testing.T) {
+	server.cluster.checkMetaNodeHeartbeat()
+	time.Sleep(5 * time.Second)
+	vol, err := server.cluster.getVol(commonVolName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	maxPartitionID := vol.maxPartitionID()
+	var mp *MetaPartition
+	for _, partition := range vol.cloneMetaPartitionMap() {
+		if partition.PartitionID != maxPartitionID && (mp == nil || partition.Start < mp.Start) {
+			mp = partition
+		}
+	}
+	if mp == nil {
+		t.Errorf("no meta partition before the last one[%v]", maxPartitionID)
+		return
+	}
+	reqURL := fmt.Sprintf("%v%v?id=%v&inode=%v", hostAddr, proto.AdminSplitMetaPartition, mp.PartitionID, mp.End)
+	fmt.Println(reqURL)
+	resp, err := http.Get(reqURL)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	reply := &proto.HTTPReply{}
+	err = json.NewDecoder(resp.Body).Decode(reply)
+	resp.Body.Close()
+	if err != nil || reply.Code != proto.ErrCodeParamError {
+		t.Errorf("expect the inode out of the range rejected, reply[%v] err[%v]", reply, err)
+		return
+	}
+	oldEnd := mp.End
+	inode := mp.MaxInodeID + (oldEnd-mp.MaxInodeID)/2
+	reqURL = fmt.Sprintf("%v%v?id=%v&inode=%v", hostAddr, proto.AdminSplitMetaPartition, mp.PartitionID, inode)
+	fmt.Println(reqURL)
+	if reply = process(reqURL, t); reply == nil {
+		return
+	}
+	split := reply.Data.(map[string]interface{})
+	nextMp, err := vol.metaPartition(uint64(split["NewPartitionID"].(float64)))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if mp.End != inode || nextMp.Start != inode+1 || nextMp.End != oldEnd {
+		t.Errorf("expect [%v,%v] and [%v,%v], but got [%v,%v] and [%v,%v]",
+			mp.Start, inode, inode+1, oldEnd, mp.Start, mp.End, nextMp.Start, nextMp.End)
+	}
+	if id := vol.maxPartitionID(); id != maxPartitionID {
+		t.Errorf("expect the last meta partition[%v] unchanged, but got %v", maxPartitionID, id)
+	}
+}
+
+func TestMetaPartitionViewCounts(t *testing.T) {
+	mp := newMetaPartition(1, 1, defaultMaxMetaPartitionInodeID, 3, "test_view_counts", 1)
+	mp.MaxInodeID = 100
+	mp.InodeCount = 80
+	mp.DentryCount = 60
+	view := getMetaPartitionView(mp)
+	if view.LeaderAddr != "" {
+		t.Errorf("expect no leader, but got %v", view.LeaderAddr)
+	}
+	if view.MaxInodeID != 100 || view.InodeCount != 80 || view.DentryCount != 60 {
+		t.Errorf("expect the counts shown without the leader, but got %v", view)
+	}
+}
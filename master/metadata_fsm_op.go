@@ -35,11 +35,20 @@ type clusterValue struct {
 	Name                        string
 	Threshold                   float32
 	DisableAutoAllocate         bool
+	MaintenanceMode             bool
 	DataNodeDeleteLimitRate     uint64
 	MetaNodeDeleteBatchCount    uint64
 	MetaNodeDeleteWorkerSleepMs uint64
 	DataNodeAutoRepairLimitRate uint64
 	FaultDomain                 bool
+	AllocationStrategy          string
+	MaxVolumes                  uint64
+	DefaultZone                 string
+	HeartbeatVerbosity          string
+	MinRWDataPartitions         int
+	AutoAllocUsedRatio          float64
+	AutoRebalancePolicy         *bsProto.AutoRebalancePolicy
+	AutoDecommissionPolicy      *bsProto.AutoDecommissionPolicy
 }
 
 func newClusterValue(c *Cluster) (cv *clusterValue) {
@@ -51,7 +60,16 @@ func newClusterValue(c *Cluster) (cv *clusterValue) {
 		MetaNodeDeleteWorkerSleepMs: c.cfg.MetaNodeDeleteWorkerSleepMs,
 		DataNodeAutoRepairLimitRate: c.cfg.DataNodeAutoRepairLimitRate,
 		DisableAutoAllocate:         c.DisableAutoAllocate,
+		MaintenanceMode:             c.MaintenanceMode,
 		FaultDomain:                 c.FaultDomain,
+		AllocationStrategy:          c.AllocationStrategy,
+		MaxVolumes:                  atomic.LoadUint64(&c.MaxVolumes),
+		DefaultZone:                 c.DefaultZone,
+		HeartbeatVerbosity:          c.HeartbeatVerbosity,
+		MinRWDataPartitions:         c.MinRWDataPartitions,
+		AutoAllocUsedRatio:          c.AutoAllocUsedRatio,
+		AutoRebalancePolicy:         c.autoRebalancer.getPolicy(),
+		AutoDecommissionPolicy:      c.autoDecommissioner.getPolicy(),
 	}
 	return cv
 }
@@ -146,6 +164,13 @@ type volValue struct {
 	DpSelectorName    string
 	DpSelectorParm    string
 	DefaultPriority   bool
+	IOPriorityClass   string
+	ReadOnly          bool
+	Tags              map[string]string
+	BandwidthLimit    uint64
+	IopsLimit         uint64
+	CapacityHistory   []*bsProto.VolCapacityChange
+	Reclaiming        bool
 }
 
 func (v *volValue) Bytes() (raw []byte, err error) {
@@ -175,6 +200,13 @@ func newVolValue(vol *Vol) (vv *volValue) {
 		DpSelectorName:    vol.dpSelectorName,
 		DpSelectorParm:    vol.dpSelectorParm,
 		DefaultPriority:   vol.defaultPriority,
+		IOPriorityClass:   vol.ioPriorityClass,
+		ReadOnly:          vol.readOnly,
+		Tags:              vol.tags,
+		BandwidthLimit:    vol.bandwidthLimit,
+		IopsLimit:         vol.iopsLimit,
+		CapacityHistory:   vol.capacityHistory,
+		Reclaiming:        vol.reclaiming,
 	}
 	return
 }
@@ -193,6 +225,9 @@ type dataNodeValue struct {
 	Addr      string
 	ZoneName  string
 	RdOnly    bool
+	Tags      []string
+
+	DecommissionedDisks []string
 }
 
 func newDataNodeValue(dataNode *DataNode) *dataNodeValue {
@@ -202,6 +237,9 @@ func newDataNodeValue(dataNode *DataNode) *dataNodeValue {
 		Addr:      dataNode.Addr,
 		ZoneName:  dataNode.ZoneName,
 		RdOnly:    dataNode.RdOnly,
+		Tags:      dataNode.Tags,
+
+		DecommissionedDisks: dataNode.DecommissionedDisks,
 	}
 }
 
@@ -211,6 +249,7 @@ type metaNodeValue struct {
 	Addr      string
 	ZoneName  string
 	RdOnly    bool
+	Tags      []string
 }
 
 func newMetaNodeValue(metaNode *MetaNode) *metaNodeValue {
@@ -220,6 +259,7 @@ func newMetaNodeValue(metaNode *MetaNode) *metaNodeValue {
 		Addr:      metaNode.Addr,
 		ZoneName:  metaNode.ZoneName,
 		RdOnly:    metaNode.RdOnly,
+		Tags:      metaNode.Tags,
 	}
 }
 
@@ -383,17 +423,22 @@ func (c *Cluster) syncDeleteDataPartition(dp *DataPartition) (err error) {
 }
 
 func (c *Cluster) putDataPartitionInfo(opType uint32, dp *DataPartition) (err error) {
-	metadata := new(RaftCmd)
-	metadata.Op = opType
-	metadata.K = dataPartitionPrefix + strconv.FormatUint(dp.VolID, 10) + keySeparator + strconv.FormatUint(dp.PartitionID, 10)
-	dpv := newDataPartitionValue(dp)
-	metadata.V, err = json.Marshal(dpv)
+	metadata, err := c.buildDataPartitionRaftCmd(opType, dp)
 	if err != nil {
 		return
 	}
 	return c.submit(metadata)
 }
 
+func (c *Cluster) buildDataPartitionRaftCmd(opType uint32, dp *DataPartition) (metadata *RaftCmd, err error) {
+	metadata = new(RaftCmd)
+	metadata.Op = opType
+	metadata.K = dataPartitionPrefix + strconv.FormatUint(dp.VolID, 10) + keySeparator + strconv.FormatUint(dp.PartitionID, 10)
+	dpv := newDataPartitionValue(dp)
+	metadata.V, err = json.Marshal(dpv)
+	return
+}
+
 func (c *Cluster) submit(metadata *RaftCmd) (err error) {
 	cmd, err := metadata.Marshal()
 	if err != nil {
@@ -420,14 +465,22 @@ func (c *Cluster) syncDeleteVol(vol *Vol) (err error) {
 }
 
 func (c *Cluster) syncPutVolInfo(opType uint32, vol *Vol) (err error) {
-	metadata := new(RaftCmd)
+	metadata, err := c.buildVolRaftCmd(opType, vol)
+	if err != nil {
+		return
+	}
+	return c.submit(metadata)
+}
+
+func (c *Cluster) buildVolRaftCmd(opType uint32, vol *Vol) (metadata *RaftCmd, err error) {
+	metadata = new(RaftCmd)
 	metadata.Op = opType
 	metadata.K = volPrefix + strconv.FormatUint(vol.ID, 10)
 	vv := newVolValue(vol)
 	if metadata.V, err = json.Marshal(vv); err != nil {
-		return errors.New(err.Error())
+		return metadata, errors.New(err.Error())
 	}
-	return c.submit(metadata)
+	return
 }
 
 // key=#mp#volID#metaPartitionID,value=json.Marshal(metaPartitionValue)
@@ -574,6 +627,22 @@ func (c *Cluster) loadClusterValue() (err error) {
 		}
 		c.cfg.MetaNodeThreshold = cv.Threshold
 		c.DisableAutoAllocate = cv.DisableAutoAllocate
+		c.MaintenanceMode = cv.MaintenanceMode
+		c.AllocationStrategy = cv.AllocationStrategy
+		atomic.StoreUint64(&c.MaxVolumes, cv.MaxVolumes)
+		c.DefaultZone = cv.DefaultZone
+		c.HeartbeatVerbosity = cv.HeartbeatVerbosity
+		c.MinRWDataPartitions = cv.MinRWDataPartitions
+		c.AutoAllocUsedRatio = cv.AutoAllocUsedRatio
+		if cv.AutoRebalancePolicy != nil {
+			c.autoRebalancer.setPolicy(cv.AutoRebalancePolicy)
+		}
+		if cv.AutoDecommissionPolicy != nil {
+			c.autoDecommissioner.setPolicy(cv.AutoDecommissionPolicy)
+		}
+		if cv.AllocationStrategy != "" {
+			setDataNodeAllocStrategy(cv.AllocationStrategy)
+		}
 		c.updateMetaNodeDeleteBatchCount(cv.MetaNodeDeleteBatchCount)
 		c.updateMetaNodeDeleteWorkerSleepMs(cv.MetaNodeDeleteWorkerSleepMs)
 		c.updateDataNodeDeleteLimitRate(cv.DataNodeDeleteLimitRate)
@@ -745,6 +814,8 @@ func (c *Cluster) loadDataNodes() (err error) {
 		dataNode.ID = dnv.ID
 		dataNode.NodeSetID = dnv.NodeSetID
 		dataNode.RdOnly = dnv.RdOnly
+		dataNode.Tags = dnv.Tags
+		dataNode.DecommissionedDisks = dnv.DecommissionedDisks
 		olddn, ok := c.dataNodes.Load(dataNode.Addr)
 		if ok {
 			if olddn.(*DataNode).ID <= dataNode.ID {
@@ -776,6 +847,7 @@ func (c *Cluster) loadMetaNodes() (err error) {
 		metaNode.ID = mnv.ID
 		metaNode.NodeSetID = mnv.NodeSetID
 		metaNode.RdOnly = mnv.RdOnly
+		metaNode.Tags = mnv.Tags
 
 		oldmn, ok := c.metaNodes.Load(metaNode.Addr)
 		if ok {
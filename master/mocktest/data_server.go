@@ -251,6 +251,25 @@ func (mds *MockDataServer) handleHeartbeats(conn net.Conn, pkg *proto.Packet, ta
 		}
 		response.PartitionReports = append(response.PartitionReports, vr)
 	}
+	// the per-disk stats are reported only if the master asks for a verbose heartbeat
+	requestJson, err := json.Marshal(task.Request)
+	if err != nil {
+		return
+	}
+	req := &proto.HeartBeatRequest{}
+	if err = json.Unmarshal(requestJson, req); err != nil {
+		return
+	}
+	if req.Verbose {
+		response.DiskReports = []*proto.DiskReport{{
+			Path:           "/cfs",
+			Status:         proto.ReadWrite,
+			Total:          response.Total,
+			Used:           response.Used,
+			Available:      response.Available,
+			PartitionCount: len(mds.partitions),
+		}}
+	}
 
 	task.Response = response
 	if err = mds.mc.NodeAPI().ResponseDataNodeTask(task); err != nil {
@@ -15,10 +15,13 @@
 package master
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util"
 	"github.com/cubefs/cubefs/util/exporter"
 	"github.com/cubefs/cubefs/util/log"
 )
@@ -292,3 +295,56 @@ func (mm *monitorMetrics) resetAllMetrics() {
 	mm.dataNodesInactive.Set(0)
 	mm.metaNodesInactive.Set(0)
 }
+
+// Format the cluster stats as the Prometheus gauges in the text format served by the master /admin/metrics API.
+func formatClusterMetrics(cv *proto.ClusterView, dataNodeCount, metaNodeCount int) []byte {
+	buf := bytes.NewBuffer(nil)
+	gauge := func(name, help string) {
+		fmt.Fprintf(buf, "# HELP %v %v\n# TYPE %v gauge\n", name, help, name)
+	}
+	countIDs := func(views []proto.BadPartitionView) (count int) {
+		for _, view := range views {
+			count += len(view.PartitionIDs)
+		}
+		return
+	}
+
+	gauge("cubefs_datanode_total", "The number of the data nodes.")
+	fmt.Fprintf(buf, "cubefs_datanode_total %v\n", dataNodeCount)
+	gauge("cubefs_metanode_total", "The number of the meta nodes.")
+	fmt.Fprintf(buf, "cubefs_metanode_total %v\n", metaNodeCount)
+	if cv.DataNodeStatInfo != nil {
+		gauge("cubefs_datanode_total_bytes", "The total space of the data nodes.")
+		fmt.Fprintf(buf, "cubefs_datanode_total_bytes %v\n", cv.DataNodeStatInfo.TotalGB*util.GB)
+		gauge("cubefs_datanode_used_bytes", "The used space of the data nodes.")
+		fmt.Fprintf(buf, "cubefs_datanode_used_bytes %v\n", cv.DataNodeStatInfo.UsedGB*util.GB)
+	}
+	if cv.MetaNodeStatInfo != nil {
+		gauge("cubefs_metanode_total_bytes", "The total memory of the meta nodes.")
+		fmt.Fprintf(buf, "cubefs_metanode_total_bytes %v\n", cv.MetaNodeStatInfo.TotalGB*util.GB)
+		gauge("cubefs_metanode_used_bytes", "The used memory of the meta nodes.")
+		fmt.Fprintf(buf, "cubefs_metanode_used_bytes %v\n", cv.MetaNodeStatInfo.UsedGB*util.GB)
+	}
+	gauge("cubefs_metanode_threshold", "The memory usage threshold of the meta nodes.")
+	fmt.Fprintf(buf, "cubefs_metanode_threshold %v\n", cv.MetaNodeThreshold)
+	gauge("cubefs_bad_datapartition_count", "The number of the bad data partitions.")
+	fmt.Fprintf(buf, "cubefs_bad_datapartition_count %v\n", countIDs(cv.BadPartitionIDs))
+	gauge("cubefs_bad_metapartition_count", "The number of the bad meta partitions.")
+	fmt.Fprintf(buf, "cubefs_bad_metapartition_count %v\n", countIDs(cv.BadMetaPartitionIDs))
+	gauge("cubefs_vol_count", "The number of the volumes.")
+	fmt.Fprintf(buf, "cubefs_vol_count %v\n", cv.VolCount)
+
+	gauge("cubefs_vol_total_bytes", "The capacity of the volume.")
+	for _, stat := range cv.VolStatInfo {
+		fmt.Fprintf(buf, "cubefs_vol_total_bytes{vol=%q} %v\n", stat.Name, stat.TotalSize)
+	}
+	gauge("cubefs_vol_used_bytes", "The used space of the volume.")
+	for _, stat := range cv.VolStatInfo {
+		fmt.Fprintf(buf, "cubefs_vol_used_bytes{vol=%q} %v\n", stat.Name, stat.UsedSize)
+	}
+	gauge("cubefs_vol_inode_count", "The number of the inodes of the volume.")
+	for _, stat := range cv.VolStatInfo {
+		fmt.Fprintf(buf, "cubefs_vol_inode_count{vol=%q} %v\n", stat.Name, stat.InodeCount)
+	}
+	return buf.Bytes()
+}
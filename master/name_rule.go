@@ -0,0 +1,48 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// nameRule validates the names of the volumes, the pattern is compiled once when the master starts.
+type nameRule struct {
+	minLen  int
+	maxLen  int
+	pattern *regexp.Regexp
+}
+
+func newNameRule(minLen, maxLen int, pattern string) (rule *nameRule, err error) {
+	if minLen <= 0 || maxLen < minLen {
+		return nil, fmt.Errorf("invalid name length range [%v, %v]", minLen, maxLen)
+	}
+	rule = &nameRule{minLen: minLen, maxLen: maxLen}
+	if rule.pattern, err = regexp.Compile(pattern); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (rule *nameRule) check(name string) (err error) {
+	if len(name) < rule.minLen || len(name) > rule.maxLen {
+		return fmt.Errorf("name length should be between %v and %v", rule.minLen, rule.maxLen)
+	}
+	if !rule.pattern.MatchString(name) {
+		return fmt.Errorf("name should match %v", rule.pattern.String())
+	}
+	return
+}
@@ -0,0 +1,212 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// nodeDecommission tracks a data node being decommissioned in the background at a limited pace,
+// the partitions not migrated yet can be canceled. It is kept in the memory of the leader only,
+// a new leader does not resume it and the decommission has to be started again.
+type nodeDecommission struct {
+	job      *asyncJob
+	pending  int32
+	canceled int32
+	stopOnce sync.Once
+	stopC    chan struct{}
+}
+
+func (d *nodeDecommission) cancel() {
+	d.stopOnce.Do(func() {
+		atomic.StoreInt32(&d.canceled, 1)
+		d.job.cancel()
+		close(d.stopC)
+	})
+}
+
+func (d *nodeDecommission) isCanceled() bool {
+	return atomic.LoadInt32(&d.canceled) == 1
+}
+
+// Start migrating the data partitions of the node at rate partitions per minute, at most limit of them if limit is positive.
+// The node is deleted once all of its partitions are migrated, as migrateDataNode does.
+func (c *Cluster) startDataNodeDecommission(srcAddr string, limit, rate int) (jobID string, err error) {
+	src, err := c.dataNode(srcAddr)
+	if err != nil {
+		return
+	}
+	partitions := c.getAllDataPartitionByDataNode(src.Addr)
+	if limit <= 0 || limit > len(partitions) {
+		limit = len(partitions)
+	}
+	if _, ok := c.nodeDecommissions.Load(src.Addr); ok {
+		return "", fmt.Errorf("data node[%v] is being decommissioned", src.Addr)
+	}
+	decommission := &nodeDecommission{
+		job:     newAsyncJob(proto.JobDecommissionDataNode, src.Addr, limit),
+		pending: int32(limit),
+		stopC:   make(chan struct{}),
+	}
+	if _, loaded := c.nodeDecommissions.LoadOrStore(src.Addr, decommission); loaded {
+		return "", fmt.Errorf("data node[%v] is being decommissioned", src.Addr)
+	}
+	c.jobs.add(decommission.job)
+	decommission.job.setNote("kept in the memory of the leader only, start it again if the leader changes")
+	log.LogWarnf("action[startDataNodeDecommission] clusterID[%v] node[%v] job[%v] partitions[%v] rate[%v/min]",
+		c.Name, src.Addr, decommission.job.id, limit, rate)
+	go c.decommissionDataNodeGradually(src, partitions[:limit], rate, len(partitions), decommission)
+	return decommission.job.id, nil
+}
+
+func (c *Cluster) decommissionDataNodeGradually(src *DataNode, partitions []*DataPartition, rate, total int, decommission *nodeDecommission) {
+	var err error
+	defer c.nodeDecommissions.Delete(src.Addr)
+	defer func() { decommission.job.finish(err) }()
+
+	// the migrate lock is taken for each partition, so that other migrations of the node are not blocked for the whole run
+	src.Lock()
+	availableSpace := src.AvailableSpace
+	src.ToBeOffline = true
+	src.AvailableSpace = 1
+	src.Unlock()
+	defer func() {
+		src.Lock()
+		src.ToBeOffline = false
+		if src.AvailableSpace == 1 {
+			src.AvailableSpace = availableSpace
+		}
+		src.Unlock()
+	}()
+
+	interval := time.Minute / time.Duration(rate)
+	failed := 0
+	for i, dp := range partitions {
+		if i > 0 {
+			select {
+			case <-decommission.stopC:
+			case <-time.After(interval):
+			}
+		}
+		if decommission.isCanceled() {
+			Warn(c.Name, fmt.Sprintf("action[decommissionDataNodeGradually],clusterID[%v] Node[%v] job[%v] canceled, pending partitions[%v]",
+				c.Name, src.Addr, decommission.job.id, atomic.LoadInt32(&decommission.pending)))
+			return
+		}
+		src.MigrateLock.Lock()
+		err1 := c.migrateDataPartition(src.Addr, "", dp, dataNodeOfflineErr)
+		src.MigrateLock.Unlock()
+		if err1 != nil {
+			log.LogErrorf("action[decommissionDataNodeGradually] clusterID[%v] node[%v] partition[%v] err[%v]",
+				c.Name, src.Addr, dp.PartitionID, err1)
+			decommission.job.fail()
+			failed++
+		} else {
+			decommission.job.complete()
+		}
+		atomic.AddInt32(&decommission.pending, -1)
+	}
+	if failed > 0 {
+		err = fmt.Errorf("%v of %v partitions failed to migrate", failed, len(partitions))
+	}
+	if failed > 0 || len(partitions) < total {
+		log.LogWarnf("action[decommissionDataNodeGradually] clusterID[%v] node[%v] job[%v] migrated[%v] failed[%v] total[%v]",
+			c.Name, src.Addr, decommission.job.id, len(partitions)-failed, failed, total)
+		return
+	}
+	if err = c.syncDeleteDataNode(src); err != nil {
+		Warn(c.Name, fmt.Sprintf("action[decommissionDataNodeGradually],clusterID[%v] Node[%v] OffLine syncDelNode failed,err[%s]",
+			c.Name, src.Addr, err.Error()))
+		return
+	}
+	c.delDataNodeFromCache(src)
+	Warn(c.Name, fmt.Sprintf("action[decommissionDataNodeGradually],clusterID[%v] Node[%v] job[%v] OffLine success",
+		c.Name, src.Addr, decommission.job.id))
+}
+
+// Stop decommissioning the data node, the partitions already migrated are kept on the new replicas.
+// Return the job and the number of the partitions canceled.
+func (c *Cluster) cancelDataNodeDecommission(addr string) (jobID string, pending int, err error) {
+	value, ok := c.nodeDecommissions.Load(addr)
+	if !ok {
+		return "", 0, fmt.Errorf("data node[%v] is not being decommissioned", addr)
+	}
+	decommission := value.(*nodeDecommission)
+	decommission.cancel()
+	return decommission.job.id, int(atomic.LoadInt32(&decommission.pending)), nil
+}
+
+// List the data and meta nodes being decommissioned or migrated, with the running job moving their partitions.
+func (c *Cluster) getDecommissioningNodes() (nodes []*proto.DecommissioningNode) {
+	jobs := make(map[string]*proto.JobView)
+	for _, job := range c.jobs.list() {
+		if job.State != proto.JobStateRunning || job.Type == proto.JobDecommissionDisk {
+			continue
+		}
+		// the target of a migration is src->target
+		jobs[job.Type+":"+strings.SplitN(job.Target, "->", 2)[0]] = job
+	}
+	findJob := func(addr string, jobTypes ...string) *proto.JobView {
+		for _, jobType := range jobTypes {
+			if job, ok := jobs[jobType+":"+addr]; ok {
+				return job
+			}
+		}
+		return nil
+	}
+	nodes = make([]*proto.DecommissioningNode, 0)
+	c.dataNodes.Range(func(key, value interface{}) bool {
+		dataNode := value.(*DataNode)
+		_, gradually := c.nodeDecommissions.Load(dataNode.Addr)
+		if !dataNode.ToBeOffline && !gradually {
+			return true
+		}
+		nodes = append(nodes, &proto.DecommissioningNode{
+			Addr:                dataNode.Addr,
+			Type:                topologyTypeData,
+			RemainingPartitions: len(c.getAllDataPartitionByDataNode(dataNode.Addr)),
+			Job:                 findJob(dataNode.Addr, proto.JobDecommissionDataNode, proto.JobMigrateDataNode),
+		})
+		return true
+	})
+	c.metaNodes.Range(func(key, value interface{}) bool {
+		metaNode := value.(*MetaNode)
+		if !metaNode.ToBeOffline {
+			return true
+		}
+		nodes = append(nodes, &proto.DecommissioningNode{
+			Addr:                metaNode.Addr,
+			Type:                topologyTypeMeta,
+			RemainingPartitions: len(c.getAllMetaPartitionByMetaNode(metaNode.Addr)),
+			Job:                 findJob(metaNode.Addr, proto.JobMigrateMetaNode),
+		})
+		return true
+	})
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Type != nodes[j].Type {
+			return nodes[i].Type < nodes[j].Type
+		}
+		return nodes[i].Addr < nodes[j].Addr
+	})
+	return
+}
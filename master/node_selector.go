@@ -16,8 +16,10 @@ package master
 
 import (
 	"fmt"
+	"math/rand"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/util/log"
@@ -28,6 +30,47 @@ const (
 	selectMetaNode = 1
 )
 
+// the placement strategies of the data partitions
+const (
+	allocStrategyDefault      = "default"
+	allocStrategyWearLeveling = "wearLeveling"   // prefer the data nodes whose disks have been written less
+	allocStrategyAvailSpace   = "availableSpace" // choose the data nodes at random, weighted by their available space
+)
+
+var allocStrategies = []string{allocStrategyDefault, allocStrategyWearLeveling, allocStrategyAvailSpace}
+
+// the factor of the wear that reduces the weight of the data node with the most written disk
+const wearLevelingFactor = 0.9
+
+// dataNodeAllocStrategy mirrors Cluster.AllocationStrategy, the node sets choose the data nodes
+// without a reference to the cluster.
+var dataNodeAllocStrategy atomic.Value
+
+func isValidAllocStrategy(strategy string) bool {
+	return contains(allocStrategies, strategy)
+}
+
+func setDataNodeAllocStrategy(strategy string) {
+	dataNodeAllocStrategy.Store(strategy)
+}
+
+func getDataNodeAllocStrategy() string {
+	if strategy, ok := dataNodeAllocStrategy.Load().(string); ok && strategy != "" {
+		return strategy
+	}
+	return allocStrategyDefault
+}
+
+func getMaxDiskWrittenSize(dataNodes *sync.Map) (maxWritten uint64) {
+	dataNodes.Range(func(key, value interface{}) bool {
+		if written := value.(*DataNode).getMaxDiskWrittenSize(); written > maxWritten {
+			maxWritten = written
+		}
+		return true
+	})
+	return
+}
+
 type weightedNode struct {
 	Carry  float64
 	Weight float64
@@ -78,6 +121,35 @@ func (nodes SortedWeightedNodes) setNodeCarry(availCarryCount, replicaNum int) {
 	}
 }
 
+// Pick count nodes at random without replacement, the chance of a node is proportional to its weight. A node of
+// twice the available space is chosen about twice as often, the nodes of no weight only if all of them are.
+func (nodes SortedWeightedNodes) pickByWeight(count int) (picked SortedWeightedNodes) {
+	candidates := append(SortedWeightedNodes(nil), nodes...)
+	for len(picked) < count && len(candidates) > 0 {
+		var total float64
+		for _, nt := range candidates {
+			total += nt.Weight
+		}
+		i := rand.Intn(len(candidates))
+		if total > 0 {
+			r := rand.Float64() * total
+			for j, nt := range candidates {
+				if nt.Weight <= 0 {
+					continue
+				}
+				i = j
+				if r < nt.Weight {
+					break
+				}
+				r -= nt.Weight
+			}
+		}
+		picked = append(picked, candidates[i])
+		candidates = append(candidates[:i], candidates[i+1:]...)
+	}
+	return
+}
+
 func (ns *nodeSet) getMetaNodeMaxTotal() (maxTotal uint64) {
 	ns.metaNodes.Range(func(key, value interface{}) bool {
 		metaNode := value.(*MetaNode)
@@ -150,7 +222,12 @@ func getAllCarryMetaNodes(maxTotal uint64, excludeHosts []string, metaNodes *syn
 }
 
 func getAvailCarryDataNodeTab(maxTotal uint64, excludeHosts []string, dataNodes *sync.Map) (nodeTabs SortedWeightedNodes, availCount int) {
+	var maxWritten uint64
 	nodeTabs = make(SortedWeightedNodes, 0)
+	wearLeveling := getDataNodeAllocStrategy() == allocStrategyWearLeveling
+	if wearLeveling {
+		maxWritten = getMaxDiskWrittenSize(dataNodes)
+	}
 	dataNodes.Range(func(key, value interface{}) bool {
 		dataNode := value.(*DataNode)
 		if contains(excludeHosts, dataNode.Addr) {
@@ -175,6 +252,10 @@ func getAvailCarryDataNodeTab(maxTotal uint64, excludeHosts []string, dataNodes
 		} else {
 			nt.Weight = float64(dataNode.AvailableSpace) / float64(maxTotal)
 		}
+		if wearLeveling && maxWritten > 0 {
+			wear := float64(dataNode.getMaxDiskWrittenSize()) / float64(maxWritten)
+			nt.Weight = nt.Weight * (1 - wear*wearLevelingFactor)
+		}
 		nt.Ptr = dataNode
 		nodeTabs = append(nodeTabs, nt)
 
@@ -212,8 +293,12 @@ func getAvailHosts(nodes *sync.Map, excludeHosts []string, replicaNum int, selec
 			replicaNum, len(weightedNodes))
 		return
 	}
-	weightedNodes.setNodeCarry(count, replicaNum)
-	sort.Sort(weightedNodes)
+	if selectType == selectDataNode && getDataNodeAllocStrategy() == allocStrategyAvailSpace {
+		weightedNodes = weightedNodes.pickByWeight(replicaNum)
+	} else {
+		weightedNodes.setNodeCarry(count, replicaNum)
+		sort.Sort(weightedNodes)
+	}
 
 	for i := 0; i < replicaNum; i++ {
 		node := weightedNodes[i].Ptr
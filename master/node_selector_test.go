@@ -0,0 +1,83 @@
+package master
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/cubefs/cubefs/util"
+)
+
+func TestGetAvailHostsByAvailSpace(t *testing.T) {
+	oldStrategy := getDataNodeAllocStrategy()
+	setDataNodeAllocStrategy(allocStrategyAvailSpace)
+	defer setDataNodeAllocStrategy(oldStrategy)
+
+	nodes := new(sync.Map)
+	availGBs := []uint64{20, 40, 80, 160}
+	for i, availGB := range availGBs {
+		dn := newDataNode(fmt.Sprintf("192.168.100.%v:17310", i+1), testZone1, "test")
+		dn.ID = uint64(i + 1)
+		dn.Total = 200 * util.GB
+		dn.AvailableSpace = availGB * util.GB
+		dn.isActive = true
+		nodes.Store(dn.Addr, dn)
+		defer dn.clean()
+	}
+	counts := make(map[string]int)
+	rounds := 4000
+	for i := 0; i < rounds; i++ {
+		hosts, _, err := getAvailHosts(nodes, nil, 2, selectDataNode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hosts) != 2 || hosts[0] == hosts[1] {
+			t.Fatalf("expect 2 distinct hosts, but got %v", hosts)
+		}
+		for _, host := range hosts {
+			counts[host]++
+		}
+	}
+	for i := 1; i < len(availGBs); i++ {
+		fuller, emptier := fmt.Sprintf("192.168.100.%v:17310", i), fmt.Sprintf("192.168.100.%v:17310", i+1)
+		if counts[fuller] >= counts[emptier] {
+			t.Errorf("expect %v chosen more often than %v, but got %v", emptier, fuller, counts)
+		}
+	}
+	// the emptiest node has more than half of the available space, it takes one of the 2 replicas most of the time
+	if emptiest := counts["192.168.100.4:17310"]; emptiest < rounds*3/4 {
+		t.Errorf("expect the emptiest node chosen in most rounds, but got %v of %v", emptiest, rounds)
+	}
+}
+
+func TestGetAvailCarryDataNodeTabByWear(t *testing.T) {
+	oldStrategy := getDataNodeAllocStrategy()
+	setDataNodeAllocStrategy(allocStrategyWearLeveling)
+	defer setDataNodeAllocStrategy(oldStrategy)
+
+	nodes := new(sync.Map)
+	// the first node has written less on average, but one of its disks is the most worn
+	writtenSizes := []map[string]uint64{
+		{"/disk1": 100 * util.GB, "/disk2": 900 * util.GB},
+		{"/disk1": 600 * util.GB, "/disk2": 600 * util.GB},
+	}
+	for i, sizes := range writtenSizes {
+		dn := newDataNode(fmt.Sprintf("192.168.101.%v:17310", i+1), testZone1, "test")
+		dn.ID = uint64(i + 1)
+		dn.Total = 200 * util.GB
+		dn.AvailableSpace = 100 * util.GB
+		dn.isActive = true
+		dn.diskWrittenSizes = sizes
+		nodes.Store(dn.Addr, dn)
+		defer dn.clean()
+	}
+	nodeTabs, _ := getAvailCarryDataNodeTab(200*util.GB, nil, nodes)
+	weights := make(map[string]float64)
+	for _, nt := range nodeTabs {
+		weights[nt.Ptr.GetAddr()] = nt.Weight
+	}
+	worn, lessWorn := weights["192.168.101.1:17310"], weights["192.168.101.2:17310"]
+	if worn >= lessWorn {
+		t.Errorf("expect the node with the most worn disk weighted less, but got %v", weights)
+	}
+}
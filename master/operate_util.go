@@ -170,12 +170,23 @@ func WarnBySpecialKey(key, msg string) {
 	exporter.Warning(msg)
 }
 
+// codedError carries the reply code of the errors built by the helpers below,
+// so that the clients can tell them apart without parsing the message.
+type codedError struct {
+	error
+	code int32
+}
+
+func withCode(code int32, err error) error {
+	return &codedError{error: err, code: code}
+}
+
 func keyNotFound(name string) (err error) {
-	return errors.NewErrorf("parameter %v not found", name)
+	return withCode(proto.ErrCodeParamError, errors.NewErrorf("parameter %v not found", name))
 }
 
 func unmatchedKey(name string) (err error) {
-	return errors.NewErrorf("parameter %v not match", name)
+	return withCode(proto.ErrCodeParamError, errors.NewErrorf("parameter %v not match", name))
 }
 
 func notFoundMsg(name string) (err error) {
@@ -183,7 +194,7 @@ func notFoundMsg(name string) (err error) {
 }
 
 func metaPartitionNotFound(id uint64) (err error) {
-	return notFoundMsg(fmt.Sprintf("meta partition[%v]", id))
+	return withCode(proto.ErrCodeMetaPartitionNotExists, notFoundMsg(fmt.Sprintf("meta partition[%v]", id)))
 }
 
 func metaReplicaNotFound(addr string) (err error) {
@@ -191,7 +202,7 @@ func metaReplicaNotFound(addr string) (err error) {
 }
 
 func dataPartitionNotFound(id uint64) (err error) {
-	return notFoundMsg(fmt.Sprintf("data partition[%v]", id))
+	return withCode(proto.ErrCodeDataPartitionNotExists, notFoundMsg(fmt.Sprintf("data partition[%v]", id)))
 }
 
 func dataReplicaNotFound(addr string) (err error) {
@@ -199,19 +210,19 @@ func dataReplicaNotFound(addr string) (err error) {
 }
 
 func zoneNotFound(name string) (err error) {
-	return notFoundMsg(fmt.Sprintf("zone[%v]", name))
+	return withCode(proto.ErrCodeZoneNotExists, notFoundMsg(fmt.Sprintf("zone[%v]", name)))
 }
 
 func dataNodeNotFound(addr string) (err error) {
-	return notFoundMsg(fmt.Sprintf("data node[%v]", addr))
+	return withCode(proto.ErrCodeDataNodeNotExists, notFoundMsg(fmt.Sprintf("data node[%v]", addr)))
 }
 
 func metaNodeNotFound(addr string) (err error) {
-	return notFoundMsg(fmt.Sprintf("meta node[%v]", addr))
+	return withCode(proto.ErrCodeMetaNodeNotExists, notFoundMsg(fmt.Sprintf("meta node[%v]", addr)))
 }
 
 func volNotFound(name string) (err error) {
-	return notFoundMsg(fmt.Sprintf("vol[%v]", name))
+	return withCode(proto.ErrCodeVolNotExists, notFoundMsg(fmt.Sprintf("vol[%v]", name)))
 }
 
 func matchKey(serverKey, clientKey string) bool {
@@ -0,0 +1,114 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+const leaderTransferTimeout = 5 * time.Second
+
+// The target of the leadership should be another peer of the raft group, active and caught up with the leader,
+// that is its replicated index is within readyMaxAppliedLag of the committed index.
+func (m *Server) checkLeaderTransferTarget(targetID uint64) (addr string, err error) {
+	status := m.partition.Status()
+	if status == nil {
+		return "", fmt.Errorf("the raft partition of the master is stopped")
+	}
+	if status.Leader != status.NodeID {
+		return "", fmt.Errorf("master[%v] is not the leader", status.NodeID)
+	}
+	if targetID == status.NodeID {
+		return "", fmt.Errorf("master[%v] is the leader already", targetID)
+	}
+	replica, ok := status.Replicas[targetID]
+	if !ok {
+		return "", fmt.Errorf("master[%v] is not a member of the raft group", targetID)
+	}
+	if !replica.Active {
+		return "", fmt.Errorf("master[%v] is not active", targetID)
+	}
+	if status.Commit > replica.Match+m.config.readyMaxAppliedLag {
+		return "", fmt.Errorf("master[%v] is not caught up, replicated index %v, committed index %v",
+			targetID, replica.Match, status.Commit)
+	}
+	if addr = AddrDatabase[targetID]; addr == "" {
+		return "", fmt.Errorf("the addr of master[%v] is unknown", targetID)
+	}
+	return
+}
+
+// Transfer the leadership by asking the target master to campaign, its log is up to date so that it wins the election.
+func (m *Server) transferLeader(targetID uint64) (addr string, err error) {
+	if addr, err = m.checkLeaderTransferTarget(targetID); err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%v%v", addr, proto.TryToRaftLeader), nil)
+	if err != nil {
+		return
+	}
+	if m.config.adminToken != "" {
+		req.Header.Set(proto.HeadAuthorized, m.config.adminToken)
+	}
+	client := &http.Client{Timeout: leaderTransferTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	reply := &proto.HTTPReply{}
+	if err = json.NewDecoder(resp.Body).Decode(reply); err != nil {
+		return "", fmt.Errorf("decode the reply of master[%v] err[%v]", addr, err)
+	}
+	if reply.Code != proto.ErrCodeSuccess {
+		return "", fmt.Errorf("master[%v] failed to campaign, %v", addr, reply.Msg)
+	}
+	log.LogWarnf("action[transferLeader] transfer the leadership from [%v] to master[%v] addr[%v]",
+		m.leaderInfo.addr, targetID, addr)
+	return
+}
+
+// Step down from the leadership without a successor given. The raft library has no way for the leader to resign,
+// so the most caught-up active peer is chosen and asked to campaign, and the masters are not left without a leader
+// until an election timeout.
+func (m *Server) stepDownLeader() (targetID uint64, addr string, err error) {
+	status := m.partition.Status()
+	if status == nil {
+		return 0, "", fmt.Errorf("the raft partition of the master is stopped")
+	}
+	if status.Leader != status.NodeID {
+		return 0, "", fmt.Errorf("master[%v] is not the leader", status.NodeID)
+	}
+	var match uint64
+	for id, replica := range status.Replicas {
+		if id == status.NodeID || !replica.Active {
+			continue
+		}
+		if targetID == 0 || replica.Match > match || (replica.Match == match && id < targetID) {
+			targetID, match = id, replica.Match
+		}
+	}
+	if targetID == 0 {
+		return 0, "", fmt.Errorf("master[%v] has no active peer to take over the leadership", status.NodeID)
+	}
+	addr, err = m.transferLeader(targetID)
+	return
+}
@@ -22,7 +22,9 @@ import (
 	"net/http/httputil"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/cubefs/cubefs/proto"
 	"github.com/cubefs/cubefs/raftstore"
@@ -54,8 +56,9 @@ const (
 
 var (
 	// regexps for data validation
-	volNameRegexp = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9_.-]{1,61}[a-zA-Z0-9]$")
-	ownerRegexp   = regexp.MustCompile("^[A-Za-z][A-Za-z0-9_]{0,20}$")
+	volNameRule = &nameRule{minLen: defaultVolNameMinLen, maxLen: defaultVolNameMaxLen, pattern: regexp.MustCompile(defaultVolNamePattern)}
+	ownerRegexp = regexp.MustCompile("^[A-Za-z][A-Za-z0-9_]{0,20}$")
+	ipRegexp    = regexp.MustCompile(`^(([1-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.)(([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.){2}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])`)
 
 	useConnPool = true //for test
 	gConfig     *clusterConfig
@@ -85,6 +88,9 @@ type Server struct {
 	reverseProxy    *httputil.ReverseProxy
 	metaReady       bool
 	apiServer       *http.Server
+	startTime       time.Time
+
+	taskResponseLimiter *addrLimiter
 }
 
 // NewServer creates a new server
@@ -94,6 +100,7 @@ func NewServer() *Server {
 
 // Start starts a server
 func (m *Server) Start(cfg *config.Config) (err error) {
+	m.startTime = time.Now()
 	m.config = newClusterConfig()
 	gConfig = m.config
 	m.leaderInfo = &LeaderInfo{}
@@ -102,6 +109,7 @@ func (m *Server) Start(cfg *config.Config) (err error) {
 		log.LogError(errors.Stack(err))
 		return
 	}
+	m.taskResponseLimiter = newAddrLimiter(m.config.taskResponseQPS)
 
 	if m.rocksDBStore, err = raftstore.NewRocksDBStore(m.storeDir, LRUCacheSize, WriteBufferSize); err != nil {
 		return
@@ -159,6 +167,74 @@ func (m *Server) checkConfig(cfg *config.Config) (err error) {
 		return fmt.Errorf("%v,err:%v", proto.ErrInvalidCfg, err.Error())
 	}
 	m.config.faultDomain = cfg.GetBoolWithDefault(faultDomain, false)
+	m.config.strictParamCheck = cfg.GetBoolWithDefault(cfgStrictParamCheck, false)
+	m.config.adminToken = cfg.GetString(cfgAdminToken)
+	m.config.secureRead = cfg.GetBoolWithDefault(cfgSecureRead, false)
+	m.config.corsAllowedOrigins = cfg.GetStringSlice(cfgCORSAllowedOrigins)
+	m.config.corsMutatingAPIs = cfg.GetBoolWithDefault(cfgCORSMutatingAPIs, false)
+	if strategy := cfg.GetString(cfgDataNodeAllocStrategy); strategy != "" {
+		if !isValidAllocStrategy(strategy) {
+			return fmt.Errorf("%v,err:%v can only be one of %v", proto.ErrInvalidCfg, cfgDataNodeAllocStrategy, strings.Join(allocStrategies, ", "))
+		}
+		m.config.dataNodeAllocStrategy = strategy
+	}
+	if concurrency := cfg.GetInt64(cfgBatchDecommissionConcurrency); concurrency > 0 {
+		m.config.batchDecommissionConcurrency = int(concurrency)
+	}
+	if err = m.loadVolNameRule(cfg); err != nil {
+		return fmt.Errorf("%v,err:%v", proto.ErrInvalidCfg, err.Error())
+	}
+	if value := cfg.GetInt64(cfgMinVolCapacity); value > 0 {
+		m.config.minVolCapacity = uint64(value)
+	}
+	if value := cfg.GetInt64(cfgMaxVolCapacity); value > 0 {
+		m.config.maxVolCapacity = uint64(value)
+	}
+	if m.config.maxVolCapacity > 0 && m.config.minVolCapacity > m.config.maxVolCapacity {
+		return fmt.Errorf("%v,err:%v[%v] should not be larger than %v[%v]", proto.ErrInvalidCfg,
+			cfgMinVolCapacity, m.config.minVolCapacity, cfgMaxVolCapacity, m.config.maxVolCapacity)
+	}
+	// the grace period is off unless it is set
+	grace, present, err := cfg.CheckAndGetInt64(cfgVolDeletionGraceSec)
+	if err != nil || grace < 0 {
+		return fmt.Errorf("%v,err:%v should be a non-negative integer", proto.ErrInvalidCfg, cfgVolDeletionGraceSec)
+	}
+	if present {
+		m.config.volDeletionGraceSec = grace
+	}
+	// 0 disables the compression
+	if minBytes := cfg.GetInt64(cfgGzipMinBytes); minBytes > 0 {
+		m.config.gzipMinBytes = int(minBytes)
+	} else if cfg.GetFloat(cfgGzipMinBytes) == 0 {
+		m.config.gzipMinBytes = 0
+	}
+	if capacity := cfg.GetInt64(cfgAuditLogCapacity); capacity > 0 {
+		m.config.auditLogCapacity = int(capacity)
+	}
+	if moves := cfg.GetInt64(cfgRebalanceMaxConcurrentMoves); moves > 0 {
+		m.config.rebalanceMaxConcurrentMoves = int(moves)
+	}
+	if retention := cfg.GetInt64(cfgJobRetentionSec); retention > 0 {
+		m.config.jobRetentionSec = retention
+	}
+	if rate := cfg.GetInt64(cfgDecommissionRate); rate > 0 {
+		m.config.decommissionRate = int(rate)
+	}
+	if maxBytes := cfg.GetInt64(cfgMaxTaskResponseBytes); maxBytes > 0 {
+		m.config.maxTaskResponseBytes = maxBytes
+	}
+	if qps := cfg.GetInt64(cfgTaskResponseQPS); qps > 0 {
+		m.config.taskResponseQPS = int(qps)
+	}
+	if lag := cfg.GetInt64(cfgReadyMaxAppliedLag); lag > 0 {
+		m.config.readyMaxAppliedLag = uint64(lag)
+	}
+	if maxReplicaNum := cfg.GetInt64(cfgMaxReplicaNum); maxReplicaNum > 0 {
+		if maxReplicaNum < defaultMaxReplicaNum {
+			return fmt.Errorf("%v,err:%v should not be less than %v", proto.ErrInvalidCfg, cfgMaxReplicaNum, defaultMaxReplicaNum)
+		}
+		m.config.maxReplicaNum = int(maxReplicaNum)
+	}
 	m.config.heartbeatPort = cfg.GetInt64(heartbeatPortKey)
 	m.config.replicaPort = cfg.GetInt64(replicaPortKey)
 	if m.config.heartbeatPort <= 1024 {
@@ -251,6 +327,27 @@ func (m *Server) checkConfig(cfg *config.Config) (err error) {
 	return
 }
 
+// The rule of the volume names, the unset items keep the default ones.
+func (m *Server) loadVolNameRule(cfg *config.Config) (err error) {
+	minLen, maxLen, pattern := defaultVolNameMinLen, defaultVolNameMaxLen, defaultVolNamePattern
+	if value := cfg.GetInt64(cfgVolNameMinLen); value > 0 {
+		minLen = int(value)
+	}
+	if value := cfg.GetInt64(cfgVolNameMaxLen); value > 0 {
+		maxLen = int(value)
+	}
+	if value := cfg.GetString(cfgVolNamePattern); value != "" {
+		pattern = value
+	}
+	rule, err := newNameRule(minLen, maxLen, pattern)
+	if err != nil {
+		return
+	}
+	volNameRule = rule
+	syslog.Printf("volNameRule: length[%v, %v] pattern[%v]\n", minLen, maxLen, pattern)
+	return
+}
+
 func (m *Server) createRaftServer() (err error) {
 	raftCfg := &raftstore.Config{
 		NodeID:            m.id,
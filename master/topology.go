@@ -852,6 +852,36 @@ func (ns *nodeSet) dataNodeLen() (count int) {
 	return
 }
 
+// the used ratio and the remaining capacity are calculated by the data nodes,
+// the partition count contains both the data partitions and the meta partitions
+func (ns *nodeSet) getStat() (stat *proto.NodeSetStat) {
+	var dataTotal, dataUsed uint64
+	stat = &proto.NodeSetStat{ID: ns.ID, ZoneName: ns.zoneName}
+	ns.dataNodes.Range(func(key, value interface{}) bool {
+		dataNode := value.(*DataNode)
+		dataNode.RLock()
+		dataTotal += dataNode.Total
+		dataUsed += dataNode.Used
+		stat.CapacityRemaining += dataNode.AvailableSpace
+		stat.PartitionCount += uint64(dataNode.DataPartitionCount)
+		dataNode.RUnlock()
+		stat.DataNodeCount++
+		return true
+	})
+	ns.metaNodes.Range(func(key, value interface{}) bool {
+		metaNode := value.(*MetaNode)
+		metaNode.RLock()
+		stat.PartitionCount += uint64(metaNode.MetaPartitionCount)
+		metaNode.RUnlock()
+		stat.MetaNodeCount++
+		return true
+	})
+	if dataTotal != 0 {
+		stat.UsedRatio = float64(dataUsed) / float64(dataTotal)
+	}
+	return
+}
+
 func (ns *nodeSet) putMetaNode(metaNode *MetaNode) {
 	ns.metaNodes.Store(metaNode.Addr, metaNode)
 }
@@ -938,6 +968,15 @@ func (t *topology) getAllZones() (zones []*Zone) {
 	return
 }
 
+func (t *topology) getNodeSetByID(id uint64) (ns *nodeSet, err error) {
+	for _, zone := range t.getAllZones() {
+		if ns, err = zone.getNodeSet(id); err == nil {
+			return
+		}
+	}
+	return nil, fmt.Errorf("nodeSet[%v] not found", id)
+}
+
 func (t *topology) getZoneByIndex(index int) (zone *Zone) {
 	t.zoneLock.RLock()
 	defer t.zoneLock.RUnlock()
@@ -398,6 +398,78 @@ func (u *User) deleteVolPolicy(volName string) (err error) {
 	return
 }
 
+// Move the policies of the users on the volume to its new name, as well as the index of the users of the volume.
+// The policies moved already are moved back if any of them fails, so that they still match the volume renamed back.
+func (u *User) renameVolPolicy(oldName, newName string) (err error) {
+	var (
+		volUser  *proto.VolUser
+		userInfo *proto.UserInfo
+		userIDs  []string
+	)
+	if userIDs, err = u.getUsersOfVol(oldName); err != nil {
+		if err == proto.ErrHaveNoPolicy {
+			return nil
+		}
+		return
+	}
+	renamed := make([]*proto.UserInfo, 0, len(userIDs))
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, userInfo := range renamed {
+			userInfo.Mu.Lock()
+			userInfo.Policy.RenameVol(newName, oldName)
+			if e := u.syncUpdateUserInfo(userInfo); e != nil {
+				log.LogErrorf("action[renameVolPolicy], userID: %v, roll back to volName: %v, err: %v", userInfo.UserID, oldName, e)
+			}
+			userInfo.Mu.Unlock()
+		}
+	}()
+	for _, userID := range userIDs {
+		if userInfo, err = u.getUserInfo(userID); err != nil {
+			if err == proto.ErrUserNotExists {
+				log.LogWarnf("action[renameVolPolicy], userID: %v does not exist", userID)
+				continue
+			}
+			return
+		}
+		userInfo.Mu.Lock()
+		userInfo.Policy.RenameVol(oldName, newName)
+		renamed = append(renamed, userInfo)
+		if err = u.syncUpdateUserInfo(userInfo); err != nil {
+			err = proto.ErrPersistenceByRaft
+			userInfo.Mu.Unlock()
+			return
+		}
+		userInfo.Mu.Unlock()
+	}
+	u.volUserMutex.Lock()
+	defer u.volUserMutex.Unlock()
+	if value, exist := u.volUser.Load(oldName); exist {
+		volUser = value.(*proto.VolUser)
+	} else {
+		return nil
+	}
+	volUser.Mu.Lock()
+	defer volUser.Mu.Unlock()
+	newVolUser := &proto.VolUser{Vol: newName, UserIDs: volUser.UserIDs}
+	if err = u.syncAddVolUser(newVolUser); err != nil {
+		return proto.ErrPersistenceByRaft
+	}
+	u.volUser.Store(newName, newVolUser)
+	if err = u.syncDeleteVolUser(volUser); err != nil {
+		if e := u.syncDeleteVolUser(newVolUser); e != nil {
+			log.LogErrorf("action[renameVolPolicy], delete volName: %v, err: %v", newName, e)
+		}
+		u.volUser.Delete(newName)
+		return proto.ErrPersistenceByRaft
+	}
+	u.volUser.Delete(oldName)
+	log.LogInfof("action[renameVolPolicy], volName: %v, newName: %v", oldName, newName)
+	return
+}
+
 func (u *User) transferVol(params *proto.UserTransferVolParam) (targetUserInfo *proto.UserInfo, err error) {
 	var userInfo *proto.UserInfo
 	userInfo, err = u.getUserInfo(params.UserSrc)
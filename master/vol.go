@@ -35,6 +35,11 @@ type VolVarargs struct {
 	authenticate   bool
 	dpSelectorName string
 	dpSelectorParm string
+	force          bool // allow shrinking the capacity below the used space
+	tags           map[string]string
+	bandwidthLimit uint64
+	iopsLimit      uint64
+	caller         string // who changes the volume, recorded in the capacity history
 }
 
 // Vol represents a set of meta partitionMap and data partitionMap
@@ -68,6 +73,16 @@ type Vol struct {
 	description        string
 	dpSelectorName     string
 	dpSelectorParm     string
+	ioPriorityClass    string
+	readOnly           bool // the writes are frozen, all the partitions are kept read only
+	tags               map[string]string
+	bandwidthLimit     uint64 // the bytes per second, 0 means unlimited
+	iopsLimit          uint64 // the operations per second, 0 means unlimited
+	capacityHistory    []*proto.VolCapacityChange
+	reservedDpIDs      []uint64 // the reserved data partition ids are kept in memory on the leader, lost if the leader changes
+	reservedDpIDsLock  sync.Mutex
+	markDeleteTime     time.Time // when the volume was marked deleted, the partitions are deleted after the grace period
+	reclaiming         bool      // the tasks deleting the partitions have been sent, it is persisted so that no leader undeletes the volume
 	volLock            sync.RWMutex
 }
 
@@ -105,6 +120,7 @@ func newVol(id uint64, name, owner, zoneName string,
 	vol.createTime = createTime
 	vol.description = description
 	vol.defaultPriority = defaultPriority
+	vol.setIOPriorityClass(proto.IOPriorityNormal)
 	return
 }
 
@@ -129,9 +145,51 @@ func newVolFromVolValue(vv *volValue) (vol *Vol) {
 	vol.Status = vv.Status
 	vol.dpSelectorName = vv.DpSelectorName
 	vol.dpSelectorParm = vv.DpSelectorParm
+	if vv.IOPriorityClass != "" {
+		vol.setIOPriorityClass(vv.IOPriorityClass)
+	}
+	vol.readOnly = vv.ReadOnly
+	vol.tags = vv.Tags
+	vol.bandwidthLimit = vv.BandwidthLimit
+	vol.iopsLimit = vv.IopsLimit
+	vol.capacityHistory = vv.CapacityHistory
+	vol.reclaiming = vv.Reclaiming
 	return vol
 }
 
+// The IO priority class is also carried by the data partition responses of the volume.
+func (vol *Vol) setIOPriorityClass(class string) {
+	vol.ioPriorityClass = class
+	vol.dataPartitions.setIOPriorityClass(class)
+}
+
+func (vol *Vol) addReservedDataPartitionIDs(start, end uint64) {
+	vol.reservedDpIDsLock.Lock()
+	defer vol.reservedDpIDsLock.Unlock()
+	for id := start; id <= end; id++ {
+		vol.reservedDpIDs = append(vol.reservedDpIDs, id)
+	}
+}
+
+// the smallest reserved data partition id of the volume, ok is false if none is reserved
+func (vol *Vol) popReservedDataPartitionID() (id uint64, ok bool) {
+	vol.reservedDpIDsLock.Lock()
+	defer vol.reservedDpIDsLock.Unlock()
+	if len(vol.reservedDpIDs) == 0 {
+		return
+	}
+	id = vol.reservedDpIDs[0]
+	vol.reservedDpIDs = vol.reservedDpIDs[1:]
+	return id, true
+}
+
+// give back the reserved id taken by a data partition which failed to be created
+func (vol *Vol) returnReservedDataPartitionID(id uint64) {
+	vol.reservedDpIDsLock.Lock()
+	defer vol.reservedDpIDsLock.Unlock()
+	vol.reservedDpIDs = append([]uint64{id}, vol.reservedDpIDs...)
+}
+
 func (vol *Vol) refreshOSSSecure() (key, secret string) {
 	vol.OSSAccessKey = util.RandomString(16, util.Numeric|util.LowerLetter|util.UpperLetter)
 	vol.OSSSecretKey = util.RandomString(32, util.Numeric|util.LowerLetter|util.UpperLetter)
@@ -159,12 +217,16 @@ func (vol *Vol) metaPartition(partitionID uint64) (mp *MetaPartition, err error)
 	return
 }
 
+// Return the ID of the meta partition holding the last inode range. The partitions are created in the order of
+// their ranges, except the ones split manually in the middle, so it is the one of the largest start.
 func (vol *Vol) maxPartitionID() (maxPartitionID uint64) {
 	vol.mpsLock.RLock()
 	defer vol.mpsLock.RUnlock()
-	for id := range vol.MetaPartitions {
-		if id > maxPartitionID {
+	var maxStart uint64
+	for id, mp := range vol.MetaPartitions {
+		if maxPartitionID == 0 || mp.Start > maxStart || (mp.Start == maxStart && id > maxPartitionID) {
 			maxPartitionID = id
+			maxStart = mp.Start
 		}
 	}
 	return
@@ -212,7 +274,7 @@ func (vol *Vol) initMetaPartitions(c *Cluster, count int) (err error) {
 
 func (vol *Vol) initDataPartitions(c *Cluster) (err error) {
 	// initialize k data partitionMap at a time
-	err = c.batchCreateDataPartition(vol, defaultInitDataPartitionCnt)
+	_, err = c.batchCreateDataPartition(vol, defaultInitDataPartitionCnt)
 	return
 }
 
@@ -220,11 +282,19 @@ func (vol *Vol) checkDataPartitions(c *Cluster) (cnt int) {
 	if vol.getDataPartitionsCount() == 0 && vol.Status != markDelete {
 		c.batchCreateDataPartition(vol, 1)
 	}
+	readOnly := vol.isReadOnly()
 	vol.dataPartitions.RLock()
 	defer vol.dataPartitions.RUnlock()
 	for _, dp := range vol.dataPartitions.partitionMap {
 		dp.checkReplicaStatus(c.cfg.DataPartitionTimeOutSec)
 		dp.checkStatus(c.Name, true, c.cfg.DataPartitionTimeOutSec)
+		if readOnly {
+			dp.Lock()
+			if dp.Status == proto.ReadWrite {
+				dp.Status = proto.ReadOnly
+			}
+			dp.Unlock()
+		}
 		dp.checkLeader(c.cfg.DataPartitionTimeOutSec)
 		dp.checkMissingReplicas(c.Name, c.leaderInfo.addr, c.cfg.MissingDataPartitionInterval, c.cfg.IntervalToAlarmMissingDataPartition)
 		dp.checkReplicaNum(c, vol)
@@ -290,8 +360,16 @@ func (vol *Vol) checkMetaPartitions(c *Cluster) {
 		doSplit bool
 		err     error
 	)
+	readOnly := vol.isReadOnly()
 	for _, mp := range mps {
 		doSplit = mp.checkStatus(c.Name, true, int(vol.mpReplicaNum), maxPartitionID)
+		if readOnly {
+			mp.Lock()
+			if mp.Status == proto.ReadWrite {
+				mp.Status = proto.ReadOnly
+			}
+			mp.Unlock()
+		}
 		if doSplit {
 			nextStart := mp.MaxInodeID + defaultMetaPartitionInodeIDStep
 			log.LogInfof(c.Name, fmt.Sprintf("cluster[%v],vol[%v],meta partition[%v] splits start[%v] maxinodeid:[%v] default step:[%v],nextStart[%v]",
@@ -375,6 +453,33 @@ func (vol *Vol) status() uint8 {
 	return vol.Status
 }
 
+func (vol *Vol) isReadOnly() bool {
+	vol.volLock.RLock()
+	defer vol.volLock.RUnlock()
+	return vol.readOnly
+}
+
+// The status shown to the clients, a normal volume whose writes are frozen is shown as read only.
+func (vol *Vol) viewStatus() uint8 {
+	if vol.Status == normal && vol.isReadOnly() {
+		return proto.VolStatusReadOnly
+	}
+	return vol.Status
+}
+
+// Keep all the data and meta partitions read only, they go back to read write in the next check
+// after the volume is writable again.
+func (vol *Vol) freezePartitions() {
+	vol.setAllDataPartitionsToReadOnly()
+	for _, mp := range vol.cloneMetaPartitionMap() {
+		mp.Lock()
+		if mp.Status == proto.ReadWrite {
+			mp.Status = proto.ReadOnly
+		}
+		mp.Unlock()
+	}
+}
+
 func (vol *Vol) capacity() uint64 {
 	vol.volLock.RLock()
 	defer vol.volLock.RUnlock()
@@ -402,7 +507,10 @@ func (vol *Vol) checkAutoDataPartitionCreation(c *Cluster) {
 	}
 	vol.setStatus(normal)
 
-	if vol.status() == normal && !c.DisableAutoAllocate {
+	if float64(usedSpace) >= float64(vol.capacity())*c.getAutoAllocUsedRatio() {
+		return
+	}
+	if vol.status() == normal && !vol.isReadOnly() && !c.DisableAutoAllocate && !c.shouldYieldAllocation(vol.Name) {
 		vol.autoCreateDataPartitions(c)
 	}
 }
@@ -417,20 +525,21 @@ func (vol *Vol) autoCreateDataPartitions(c *Cluster) {
 		return
 	}
 
-	if (vol.Capacity > 200000 && vol.dataPartitions.readableAndWritableCnt < 200) || vol.dataPartitions.readableAndWritableCnt < minNumOfRWDataPartitions {
+	minRWDataPartitions := c.getMinRWDataPartitions()
+	if (vol.Capacity > 200000 && vol.dataPartitions.readableAndWritableCnt < 200) || vol.dataPartitions.readableAndWritableCnt < minRWDataPartitions {
 		vol.dataPartitions.lastAutoCreateTime = time.Now()
-		count := vol.calculateExpansionNum()
+		count := vol.calculateExpansionNum(minRWDataPartitions)
 		log.LogInfof("action[autoCreateDataPartitions] vol[%v] count[%v]", vol.Name, count)
 		c.batchCreateDataPartition(vol, count)
 	}
 }
 
 // Calculate the expansion number (the number of data partitions to be allocated to the given volume)
-func (vol *Vol) calculateExpansionNum() (count int) {
+func (vol *Vol) calculateExpansionNum(minCount int) (count int) {
 	c := float64(vol.Capacity) * float64(volExpansionRatio) * float64(util.GB) / float64(util.DefaultDataPartitionSize)
 	switch {
-	case c < minNumOfRWDataPartitions:
-		count = minNumOfRWDataPartitions
+	case c < float64(minCount):
+		count = minCount
 	case c > maxNumberOfDataPartitionsForExpansion:
 		count = maxNumberOfDataPartitionsForExpansion
 	default:
@@ -463,7 +572,7 @@ func (vol *Vol) sendViewCacheToFollower(c *Cluster) {
 }
 
 func (vol *Vol) updateViewCache(c *Cluster) {
-	view := proto.NewVolView(vol.Name, vol.Status, vol.FollowerRead, vol.createTime)
+	view := proto.NewVolView(vol.Name, vol.viewStatus(), vol.FollowerRead, vol.createTime)
 	view.SetOwner(vol.Owner)
 	view.SetOSSSecure(vol.OSSAccessKey, vol.OSSSecretKey)
 	mpViews := vol.getMetaPartitionsView()
@@ -478,6 +587,10 @@ func (vol *Vol) updateViewCache(c *Cluster) {
 	// dpResps := vol.dataPartitions.getDataPartitionsView(0)
 	// view.DataPartitions = dpResps
 	view.DomainOn = vol.domainOn
+	view.IOPriorityClass = vol.ioPriorityClass
+	view.Tags = vol.tags
+	view.BandwidthLimit = vol.bandwidthLimit
+	view.IopsLimit = vol.iopsLimit
 	viewReply := newSuccessHTTPReply(view)
 	body, err := json.Marshal(viewReply)
 	if err != nil {
@@ -538,12 +651,55 @@ func (vol *Vol) checkStatus(c *Cluster) {
 	if vol.Status != markDelete {
 		return
 	}
+	// the volume loaded from the store or marked by the former leader waits for a whole grace period
+	if vol.markDeleteTime.IsZero() {
+		vol.markDeleteTime = time.Now()
+	}
+	if time.Since(vol.markDeleteTime) < time.Duration(c.cfg.volDeletionGraceSec)*time.Second {
+		return
+	}
 	log.LogInfof("action[volCheckStatus] vol[%v],status[%v]", vol.Name, vol.Status)
+	vol.deletePartitions(c)
+	return
+}
+
+// Delete the volume marked deleted right away instead of waiting for checkStatus,
+// return the number of the partitions scheduled for deletion.
+func (vol *Vol) forceDelete(c *Cluster) (count int) {
+	vol.volLock.Lock()
+	defer vol.volLock.Unlock()
+	if vol.Status != markDelete {
+		return
+	}
+	return vol.deletePartitions(c)
+}
+
+// Send the tasks deleting the replicas of the partitions in the background, the volume is deleted
+// once all the replicas are deleted. The caller should hold the volLock.
+func (vol *Vol) deletePartitions(c *Cluster) (count int) {
 	metaTasks := vol.getTasksToDeleteMetaPartitions()
 	dataTasks := vol.getTasksToDeleteDataPartitions()
 
 	if len(metaTasks) == 0 && len(dataTasks) == 0 {
 		vol.deleteVolFromStore(c)
+		return
+	}
+	// the volume can not be undeleted once any task is sent, it is retried in the next check if it is not persisted
+	if !vol.reclaiming {
+		vol.reclaiming = true
+		if err := c.syncUpdateVol(vol); err != nil {
+			vol.reclaiming = false
+			log.LogErrorf("action[deletePartitions] vol[%v] err[%v]", vol.Name, err)
+			return
+		}
+	}
+	metaPartitions := make(map[uint64]bool)
+	for _, task := range metaTasks {
+		metaPartitions[task.PartitionID] = true
+	}
+	dataPartitions := make(map[uint64]bool)
+	for _, task := range dataTasks {
+		dataPartitions[task.PartitionID] = true
 	}
 	go func() {
 		for _, metaTask := range metaTasks {
@@ -555,7 +711,7 @@ func (vol *Vol) checkStatus(c *Cluster) {
 		}
 	}()
 
-	return
+	return len(metaPartitions) + len(dataPartitions)
 }
 
 func (vol *Vol) deleteMetaPartitionFromMetaNode(c *Cluster, task *proto.AdminTask) {
@@ -709,7 +865,7 @@ func (vol *Vol) doSplitMetaPartition(c *Cluster, mp *MetaPartition, end uint64)
 		return
 	}
 	cmdMap[updateMpRaftCmd.K] = updateMpRaftCmd
-	if nextMp, err = vol.doCreateMetaPartition(c, mp.End+1, defaultMaxMetaPartitionInodeID); err != nil {
+	if nextMp, err = vol.doCreateMetaPartition(c, mp.End+1, oldEnd); err != nil {
 		Warn(c.Name, fmt.Sprintf("action[updateEnd] clusterID[%v] partitionID[%v] create meta partition err[%v]",
 			c.Name, mp.PartitionID, err))
 		log.LogErrorf("action[updateEnd] partitionID[%v] err[%v]", mp.PartitionID, err)
@@ -749,6 +905,20 @@ func (vol *Vol) splitMetaPartition(c *Cluster, mp *MetaPartition, end uint64) (e
 	return
 }
 
+// Split the meta partition at the inode whatever its position, the partition keeps [Start, inode]
+// and a new meta partition takes (inode, End].
+func (vol *Vol) splitMetaPartitionAt(c *Cluster, mp *MetaPartition, inode uint64) (nextMp *MetaPartition, err error) {
+	vol.createMpMutex.Lock()
+	defer vol.createMpMutex.Unlock()
+	if nextMp, err = vol.doSplitMetaPartition(c, mp, inode); err != nil {
+		return
+	}
+	vol.addMetaPartition(nextMp)
+	log.LogWarnf("action[splitMetaPartitionAt],partition[%v],next partition[%v],start[%v],end[%v]",
+		mp.PartitionID, nextMp.PartitionID, nextMp.Start, nextMp.End)
+	return
+}
+
 func (vol *Vol) createMetaPartition(c *Cluster, start, end uint64) (err error) {
 	vol.createMpMutex.Lock()
 	defer vol.createMpMutex.Unlock()
@@ -845,5 +1015,39 @@ func getVolVarargs(vol *Vol) *VolVarargs {
 		authenticate:   vol.authenticate,
 		dpSelectorName: vol.dpSelectorName,
 		dpSelectorParm: vol.dpSelectorParm,
+		tags:           vol.tags,
+		bandwidthLimit: vol.bandwidthLimit,
+		iopsLimit:      vol.iopsLimit,
+	}
+}
+
+// Append a change of the capacity to the history, only the latest maxVolCapacityHistory changes are kept.
+// The history is a new slice, so the old one can be restored if the change fails to persist.
+func (vol *Vol) recordCapacityChange(oldCapacity, newCapacity uint64, caller string) {
+	history := vol.capacityHistory
+	if len(history) >= maxVolCapacityHistory {
+		history = history[len(history)-maxVolCapacityHistory+1:]
+	}
+	vol.capacityHistory = append(append(make([]*proto.VolCapacityChange, 0, len(history)+1), history...),
+		&proto.VolCapacityChange{Time: time.Now().Unix(), OldCapacity: oldCapacity, NewCapacity: newCapacity, Caller: caller})
+}
+
+func (vol *Vol) getCapacityHistory() []*proto.VolCapacityChange {
+	vol.volLock.RLock()
+	defer vol.volLock.RUnlock()
+	history := make([]*proto.VolCapacityChange, len(vol.capacityHistory))
+	copy(history, vol.capacityHistory)
+	return history
+}
+
+// Check if the volume has all the tags, an empty value matches any value of the key.
+func (vol *Vol) matchTags(tags map[string]string) bool {
+	vol.volLock.RLock()
+	defer vol.volLock.RUnlock()
+	for key, value := range tags {
+		if v, ok := vol.tags[key]; !ok || (value != "" && v != value) {
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,110 @@
+// Copyright 2018 The Chubao Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package master
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cubefs/cubefs/proto"
+	"github.com/cubefs/cubefs/util/log"
+)
+
+// volAllocPriority temporarily boosts the data partition allocation of a volume.
+// It only lives in the memory of the leader, so a boost is dropped when the leader changes.
+type volAllocPriority struct {
+	priority   int
+	expireTime time.Time
+}
+
+func (p *volAllocPriority) isExpired() bool {
+	return time.Now().After(p.expireTime)
+}
+
+func (c *Cluster) setVolAllocPriority(name string, priority int, ttl time.Duration) {
+	if priority == 0 {
+		c.volAllocPriorities.Delete(name)
+		log.LogInfof("action[setVolAllocPriority] vol[%v] priority removed", name)
+		return
+	}
+	c.volAllocPriorities.Store(name, &volAllocPriority{priority: priority, expireTime: time.Now().Add(ttl)})
+	log.LogInfof("action[setVolAllocPriority] vol[%v] priority[%v] ttl[%v]", name, priority, ttl)
+}
+
+// return 0 if the volume has no active priority, the expired ones are removed
+func (c *Cluster) getVolAllocPriority(name string) int {
+	value, ok := c.volAllocPriorities.Load(name)
+	if !ok {
+		return 0
+	}
+	p := value.(*volAllocPriority)
+	if p.isExpired() {
+		c.volAllocPriorities.Delete(name)
+		return 0
+	}
+	return p.priority
+}
+
+func (c *Cluster) getActiveVolAllocPriorities() (views []proto.VolAllocPriorityView) {
+	views = make([]proto.VolAllocPriorityView, 0)
+	c.volAllocPriorities.Range(func(key, value interface{}) bool {
+		p := value.(*volAllocPriority)
+		if p.isExpired() {
+			c.volAllocPriorities.Delete(key)
+			return true
+		}
+		views = append(views, proto.VolAllocPriorityView{
+			VolName:    key.(string),
+			Priority:   p.priority,
+			ExpireTime: p.expireTime.Format(proto.TimeFormat),
+		})
+		return true
+	})
+	return
+}
+
+func (c *Cluster) hasActiveVolAllocPriority() bool {
+	return len(c.getActiveVolAllocPriorities()) != 0
+}
+
+// the free capacity is contended when the used ratio of the data nodes reaches allocPriorityContendedRatio
+func (c *Cluster) isDataCapacityContended() bool {
+	stat := c.dataNodeStatInfo
+	if stat == nil || stat.TotalGB == 0 {
+		return false
+	}
+	return float64(stat.UsedGB)/float64(stat.TotalGB) >= allocPriorityContendedRatio
+}
+
+// When the free capacity is contended, the volumes without an active priority
+// stop the automatic allocation and leave the space to the boosted ones.
+func (c *Cluster) shouldYieldAllocation(name string) bool {
+	if c.getVolAllocPriority(name) > 0 {
+		return false
+	}
+	return c.hasActiveVolAllocPriority() && c.isDataCapacityContended()
+}
+
+// return the volumes with the highest allocation priority first
+func (c *Cluster) getVolsOrderByAllocPriority() (vols []*Vol) {
+	vols = make([]*Vol, 0)
+	for _, vol := range c.copyVols() {
+		vols = append(vols, vol)
+	}
+	sort.SliceStable(vols, func(i, j int) bool {
+		return c.getVolAllocPriority(vols[i].Name) > c.getVolAllocPriority(vols[j].Name)
+	})
+	return
+}
@@ -241,3 +241,20 @@ func TestConcurrentReadWriteDataPartitionMap(t *testing.T) {
 		vol.updateViewCache(server.cluster)
 	}
 }
+
+func TestReturnReservedDataPartitionID(t *testing.T) {
+	vol := &Vol{}
+	vol.addReservedDataPartitionIDs(100, 102)
+	id, ok := vol.popReservedDataPartitionID()
+	if !ok || id != 100 {
+		t.Fatalf("expect the reserved id 100, but got %v %v", id, ok)
+	}
+	// the id of a failed creation is taken first again
+	vol.returnReservedDataPartitionID(id)
+	if id, ok = vol.popReservedDataPartitionID(); !ok || id != 100 {
+		t.Errorf("expect the returned id 100 taken again, but got %v %v", id, ok)
+	}
+	if len(vol.reservedDpIDs) != 2 {
+		t.Errorf("expect 2 ids left, but got %v", vol.reservedDpIDs)
+	}
+}
@@ -18,29 +18,78 @@ package proto
 const (
 	// Admin APIs
 	AdminGetCluster                = "/admin/getCluster"
+	AdminGetConfig                 = "/admin/getConfig"
+	AdminMetrics                   = "/admin/metrics"
+	AdminLivez                     = "/livez"
+	AdminReadyz                    = "/readyz"
 	AdminGetDataPartition          = "/dataPartition/get"
 	AdminLoadDataPartition         = "/dataPartition/load"
+	AdminVerifyDataPartition       = "/dataPartition/verify"
 	AdminCreateDataPartition       = "/dataPartition/create"
 	AdminDecommissionDataPartition = "/dataPartition/decommission"
+	AdminBatchDecommissionDps      = "/dataPartition/batchDecommission"
+	AdminMigrateDataPartition      = "/dataPartition/migrate"
+	AdminRebalanceDataPartitions   = "/dataPartition/rebalance"
 	AdminDiagnoseDataPartition     = "/dataPartition/diagnose"
+	AdminGetOverReplicatedDps      = "/dataPartition/overReplicated"
+	AdminGetUnderReplicated        = "/admin/underReplicated"
+	AdminGetDecommissioningNodes   = "/admin/decommissioningNodes"
+	AdminGetReplicaLag             = "/dataPartition/replicaLag"
+	AdminGetLaggingReplicas        = "/dataPartition/laggingReplicas"
 	AdminDeleteDataReplica         = "/dataReplica/delete"
 	AdminAddDataReplica            = "/dataReplica/add"
 	AdminDeleteVol                 = "/vol/delete"
+	AdminUndeleteVol               = "/vol/undelete"
+	AdminRenameVol                 = "/vol/rename"
 	AdminUpdateVol                 = "/vol/update"
 	AdminVolShrink                 = "/vol/shrink"
 	AdminVolExpand                 = "/vol/expand"
+	AdminExportVol                 = "/vol/export"
+	AdminGetVolHistory             = "/vol/history"
+	AdminImportVol                 = "/vol/import"
+	AdminSetVolAllocPriority       = "/vol/setAllocationPriority"
+	AdminSetVolIOPriority          = "/vol/setIOPriority"
+	AdminSetVolReadOnly            = "/vol/setReadOnly"
+	AdminGetVolSkew                = "/vol/skew"
+	AdminPreviewVolDeletion        = "/vol/previewDeletion"
 	AdminCreateVol                 = "/admin/createVol"
+	AdminBatchCreateVol            = "/vol/batchCreate"
 	AdminGetVol                    = "/admin/getVol"
 	AdminClusterFreeze             = "/cluster/freeze"
+	AdminSetMaintenance            = "/cluster/setMaintenance"
+	AdminSetAllocationStrategy     = "/cluster/setAllocationStrategy"
+	AdminSetMaxVolumes             = "/cluster/setMaxVolumes"
+	AdminSetDefaultZone            = "/cluster/setDefaultZone"
+	AdminSetAutoRebalancePolicy    = "/cluster/setAutoRebalancePolicy"
+	AdminSetAutoDecommission       = "/cluster/setAutoDecommission"
+	AdminSetHeartbeatVerbosity     = "/cluster/setHeartbeatVerbosity"
+	AdminSetAutoAllocThreshold     = "/cluster/setAutoAllocThreshold"
+	AdminGetClusterConfig          = "/cluster/config"
+	AdminBatchSetNodeTags          = "/node/batchSetTags"
+	AdminGetAuditByAddr            = "/admin/getAuditByAddr"
+	AdminGetAuditLog               = "/admin/getAuditLog"
+	AdminEvents                    = "/events"
+	AdminSetLogLevel               = "/admin/setLogLevel"
+	AdminGetLogLevel               = "/admin/getLogLevel"
+	AdminPreCheckDecommission      = "/dataNode/preCheckDecommission"
+	AdminSimulateNodeSetFailure    = "/nodeSet/simulateFailure"
+	AdminCreateNodeSet             = "/nodeSet/create"
+	AdminGetNodeSet                = "/nodeSet/get"
+	AdminMoveNodeToNodeSet         = "/nodeSet/moveNode"
+	AdminGetBadPartitionTrend      = "/dataPartition/badTrend"
+	AdminReservePartitionIDs       = "/dataPartition/reserveIds"
 	AdminClusterStat               = "/cluster/stat"
+	AdminGetCapacityForecast       = "/cluster/capacityForecast"
 	AdminGetIP                     = "/admin/getIp"
 	AdminCreateMetaPartition       = "/metaPartition/create"
 	AdminSetMetaNodeThreshold      = "/threshold/set"
 	AdminListVols                  = "/vol/list"
+	AdminGetAllVols                = "/admin/getAllVols"
 	AdminSetNodeInfo               = "/admin/setNodeInfo"
 	AdminGetNodeInfo               = "/admin/getNodeInfo"
 	AdminGetAllNodeSetGrpInfo      = "/admin/getDomainInfo"
 	AdminGetNodeSetGrpInfo         = "/admin/getDomainNodeSetGrpInfo"
+	AdminGetNodeSetStats           = "/admin/getNodeSetStats"
 	AdminGetIsDomainOn             = "/admin/getIsDomainOn"
 	AdminUpdateNodeSetCapcity      = "/admin/updateNodeSetCapcity"
 	AdminUpdateNodeSetId           = "/admin/updateNodeSetId"
@@ -68,25 +117,38 @@ const (
 	ClientMetaPartitions = "/client/metaPartitions"
 
 	//raft node APIs
-	AddRaftNode    = "/raftNode/add"
-	RemoveRaftNode = "/raftNode/remove"
+	AddRaftNode        = "/raftNode/add"
+	RemoveRaftNode     = "/raftNode/remove"
+	GetRaftStatus      = "/raftNode/status"
+	TransferRaftLeader = "/raftNode/transferLeader"
+	TryToRaftLeader    = "/raftNode/tryToLeader"
+	SnapshotRaftLog    = "/raftNode/snapshot"
+	StepDownRaftLeader = "/raftNode/stepDown"
 
 	// Node APIs
 	AddDataNode                    = "/dataNode/add"
 	DecommissionDataNode           = "/dataNode/decommission"
 	MigrateDataNode                = "/dataNode/migrate"
+	CancelDecommissionDataNode     = "/dataNode/cancelDecommission"
+	AdminGetJob                    = "/job/get"
 	DecommissionDisk               = "/disk/decommission"
+	CancelDecommissionDisk         = "/disk/cancelDecommission"
+	RecommissionDisk               = "/disk/recommission"
 	GetDataNode                    = "/dataNode/get"
+	GetDataNodePartitions          = "/dataNode/dataPartitions"
+	GetDataNodeDisks               = "/dataNode/disks"
 	AddMetaNode                    = "/metaNode/add"
 	DecommissionMetaNode           = "/metaNode/decommission"
 	MigrateMetaNode                = "/metaNode/migrate"
 	GetMetaNode                    = "/metaNode/get"
+	GetMetaNodePartitions          = "/metaNode/metaPartitions"
 	AdminUpdateMetaNode            = "/metaNode/update"
 	AdminUpdateDataNode            = "/dataNode/update"
 	AdminGetInvalidNodes           = "/invalid/nodes"
 	AdminLoadMetaPartition         = "/metaPartition/load"
 	AdminDiagnoseMetaPartition     = "/metaPartition/diagnose"
 	AdminDecommissionMetaPartition = "/metaPartition/decommission"
+	AdminSplitMetaPartition        = "/metaPartition/split"
 	AdminAddMetaReplica            = "/metaReplica/add"
 	AdminDeleteMetaReplica         = "/metaReplica/delete"
 
@@ -119,14 +181,30 @@ const (
 	ParamAuthorized = "_authorization"
 	UserKey         = "_user_key"
 	UserInfoKey     = "_user_info_key"
+	// the request is rejected if it carries unknown parameters when this header is true
+	HeadStrictParamCheck = "X-Strict-Param-Check"
+	// the id correlating the request with the log lines of the master, generated if the client does not give one
+	HeadRequestID = "X-Request-ID"
 )
 
 const TimeFormat = "2006-01-02 15:04:05"
 
+// The IO priority classes of a volume.
+const (
+	IOPriorityHigh   = "high"
+	IOPriorityNormal = "normal"
+	IOPriorityLow    = "low"
+)
+
+func IsValidIOPriorityClass(class string) bool {
+	return class == IOPriorityHigh || class == IOPriorityNormal || class == IOPriorityLow
+}
+
 // HTTPReply uniform response structure
 type HTTPReply struct {
 	Code int32       `json:"code"`
 	Msg  string      `json:"msg"`
+	Op   string      `json:"op,omitempty"` // the failed operation, only set in the error reply
 	Data interface{} `json:"data"`
 }
 
@@ -233,6 +311,25 @@ type File struct {
 	Modified int64
 }
 
+// DataPartitionVerification is the result of comparing the file checksums of the replicas of a data partition.
+type DataPartitionVerification struct {
+	PartitionID     uint64
+	Consistent      bool
+	Replicas        []string         // the replicas having reported their files
+	NoResponseAddrs []string         `json:",omitempty"`
+	DivergentAddrs  []string         `json:",omitempty"`
+	DivergentFiles  []*DivergentFile `json:",omitempty"`
+	CheckedFiles    int
+	SkippedFiles    int // the files being written, not comparable yet
+}
+
+// DivergentFile is a file whose checksum differs among the replicas.
+type DivergentFile struct {
+	Name           string
+	Crcs           map[string]uint32 // keyed by the replica addr, a replica lacking the file is absent
+	DivergentAddrs []string
+}
+
 // LoadMetaPartitionMetricRequest defines the request of loading the meta partition metrics.
 type LoadMetaPartitionMetricRequest struct {
 	PartitionID uint64
@@ -253,6 +350,27 @@ type LoadMetaPartitionMetricResponse struct {
 type HeartBeatRequest struct {
 	CurrTime   int64
 	MasterAddr string
+	Verbose    bool // ask the node to report the diagnostics such as the per-disk stats
+	// the disks decommissioned by the master, the data node creates no data partition on them
+	DecommissionedDisks []string
+}
+
+const (
+	HeartbeatVerbosityMinimal = "minimal"
+	HeartbeatVerbosityVerbose = "verbose"
+)
+
+// DiskReport defines the per-disk stats reported by the verbose data node heartbeat.
+type DiskReport struct {
+	Path           string
+	Status         int
+	Total          uint64
+	Used           uint64
+	Available      uint64
+	ReadErrCnt     uint64
+	WriteErrCnt    uint64
+	WrittenSize    uint64
+	PartitionCount int
 }
 
 // PartitionReport defines the partition report.
@@ -266,6 +384,7 @@ type PartitionReport struct {
 	IsLeader        bool
 	ExtentCount     int
 	NeedCompare     bool
+	ApplyID         uint64
 }
 
 // DataNodeHeartbeatResponse defines the response to the data node heartbeat.
@@ -282,6 +401,8 @@ type DataNodeHeartbeatResponse struct {
 	Status              uint8
 	Result              string
 	BadDisks            []string
+	DiskWrittenSizes    map[string]uint64 // key: disk path, value: cumulative bytes written to the disk
+	DiskReports         []*DiskReport     // only reported by the verbose heartbeat
 }
 
 // MetaPartitionReport defines the meta partition report.
@@ -384,34 +505,101 @@ type MetaPartitionLoadResponse struct {
 
 // DataPartitionResponse defines the response from a data node to the master that is related to a data partition.
 type DataPartitionResponse struct {
-	PartitionID uint64
-	Status      int8
-	ReplicaNum  uint8
-	Hosts       []string
-	LeaderAddr  string
-	Epoch       uint64
-	IsRecover   bool
+	PartitionID    uint64
+	Status         int8
+	ReplicaNum     uint8
+	LiveReplicaNum uint8 // the number of hosts whose data node is active
+	Hosts          []string
+	LeaderAddr     string
+	Epoch          uint64
+	IsRecover      bool
+	// the IO priority class of the volume, used by the nodes to schedule IO
+	IOPriorityClass string
+	// the replicas reported by the data nodes, Hosts is kept for the old clients
+	Replicas []*DataPartitionReplica `json:",omitempty"`
+}
+
+// DataPartitionReplica is the status of a replica of a data partition on its data node.
+type DataPartitionReplica struct {
+	Addr     string
+	Status   int8
+	DiskPath string
+	Used     uint64
+}
+
+// NodeDataPartition is a data partition with a replica on the data node.
+type NodeDataPartition struct {
+	*DataPartitionResponse
+	VolName  string
+	IsLeader bool // the replica on the data node is the leader
+}
+
+// NodeMetaPartition is a meta partition with a replica on the meta node.
+type NodeMetaPartition struct {
+	*MetaPartitionView
+	VolName  string
+	IsLeader bool // the replica on the meta node is the leader
 }
 
 // DataPartitionsView defines the view of a data partition
 type DataPartitionsView struct {
+	Total          int // the number of the data partitions in the view
+	ReadWriteCount int
+	ReadOnlyCount  int
 	DataPartitions []*DataPartitionResponse
 }
 
+// DataPartitionsPage is a page of the data partitions of a volume.
+type DataPartitionsPage struct {
+	Total          int // the number of all the data partitions of the volume
+	Start          int
+	DataPartitions []*DataPartitionResponse
+}
+
+// DataPartitionsGroupedView buckets the data partitions of a volume by the partition status.
+type DataPartitionsGroupedView struct {
+	ReadWrite   []*DataPartitionResponse `json:"readWrite"`
+	ReadOnly    []*DataPartitionResponse `json:"readOnly"`
+	Unavailable []*DataPartitionResponse `json:"unavailable"`
+}
+
+func NewDataPartitionsGroupedView() *DataPartitionsGroupedView {
+	return &DataPartitionsGroupedView{
+		ReadWrite:   make([]*DataPartitionResponse, 0),
+		ReadOnly:    make([]*DataPartitionResponse, 0),
+		Unavailable: make([]*DataPartitionResponse, 0),
+	}
+}
+
 func NewDataPartitionsView() (dataPartitionsView *DataPartitionsView) {
 	dataPartitionsView = new(DataPartitionsView)
 	dataPartitionsView.DataPartitions = make([]*DataPartitionResponse, 0)
 	return
 }
 
+// SetDataPartitions sets the data partitions of the view and counts them by status.
+func (view *DataPartitionsView) SetDataPartitions(dps []*DataPartitionResponse) {
+	view.DataPartitions = dps
+	view.Total = len(dps)
+	view.ReadWriteCount, view.ReadOnlyCount = 0, 0
+	for _, dp := range dps {
+		switch dp.Status {
+		case ReadWrite:
+			view.ReadWriteCount++
+		case ReadOnly:
+			view.ReadOnlyCount++
+		}
+	}
+}
+
 // MetaPartitionView defines the view of a meta partition
 type MetaPartitionView struct {
 	PartitionID uint64
 	Start       uint64
 	End         uint64
-	MaxInodeID  uint64
-	InodeCount  uint64
-	DentryCount uint64
+	MaxInodeID  uint64 `json:",omitempty"`
+	InodeCount  uint64 `json:",omitempty"`
+	DentryCount uint64 `json:",omitempty"`
 	IsRecover   bool
 	Members     []string
 	LeaderAddr  string
@@ -423,6 +611,9 @@ type OSSSecure struct {
 	SecretKey string
 }
 
+// VolStatusReadOnly is the status of a volume whose writes are frozen, besides the normal 0 and the marked to delete 1.
+const VolStatusReadOnly uint8 = 2
+
 // VolView defines the view of a volume
 type VolView struct {
 	Name           string
@@ -434,6 +625,13 @@ type VolView struct {
 	DomainOn       bool
 	OSSSecure      *OSSSecure
 	CreateTime     int64
+	// the IO priority class of the volume, one of high, normal and low
+	IOPriorityClass string
+	Tags            map[string]string `json:",omitempty"`
+	// the QoS limits of the volume for the clients and the data nodes, 0 means unlimited.
+	// They are advisory, nothing enforces them yet.
+	BandwidthLimit uint64 // bytes per second
+	IopsLimit      uint64
 }
 
 func (v *VolView) SetOwner(owner string) {
@@ -492,7 +690,64 @@ type SimpleVolView struct {
 	DpSelectorName     string
 	DpSelectorParm     string
 	DefaultZonePrior   bool
+	IOPriorityClass    string
+	Tags               map[string]string `json:",omitempty" graphql:"-"`
+	BandwidthLimit     uint64            // bytes per second, 0 means unlimited
+	IopsLimit          uint64            // 0 means unlimited
+}
+
+// CreateVolRequest is the JSON body of createVol, the fields are the same as the form parameters.
+type CreateVolRequest struct {
+	Name              string            `json:"name"`
+	Owner             string            `json:"owner"`
+	Capacity          int               `json:"capacity"` // in GB
+	ReplicaNum        int               `json:"replicaNum,omitempty"`
+	DataPartitionSize int               `json:"dataPartitionSize,omitempty"` // in GB
+	MpCount           int               `json:"mpCount,omitempty"`
+	ZoneName          string            `json:"zoneName,omitempty"`
+	CrossZone         bool              `json:"crossZone,omitempty"`
+	FollowerRead      bool              `json:"followerRead,omitempty"`
+	Authenticate      bool              `json:"authenticate,omitempty"`
+	DefaultPriority   bool              `json:"defaultPriority,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	FailIfExists      bool              `json:"failIfExists,omitempty"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	BandwidthLimit    int64             `json:"bandwidthLimit,omitempty"` // bytes per second
+	IopsLimit         int64             `json:"iopsLimit,omitempty"`
+}
+
+// VolCapacityChange records a change of the capacity of a volume
+type VolCapacityChange struct {
+	Time        int64  // unix seconds
+	OldCapacity uint64 // GB
+	NewCapacity uint64 // GB
+	Caller      string // the remote address of the request, or the user of the graphql API
+}
+
+// VolSpec defines the importable configuration of a volume, it carries no data
+type VolSpec struct {
+	Name              string
+	Owner             string
+	ZoneName          string
+	Description       string
+	Capacity          uint64 // GB
+	DataPartitionSize uint64 // GB
+	DpReplicaNum      uint8
+	MpReplicaNum      uint8
+	MpCount           int
+	FollowerRead      bool
+	Authenticate      bool
+	CrossZone         bool
+	DefaultPriority   bool
+	DpSelectorName    string
+	DpSelectorParm    string
+	IOPriorityClass   string
+	BandwidthLimit    uint64 // bytes per second, 0 means unlimited
+	IopsLimit         uint64 // 0 means unlimited
+	ReadOnly          bool   // the writes are frozen
+	Tags              map[string]string
 }
+
 type NodeSetInfo struct {
 	ID           uint64
 	ZoneName     string
@@ -506,6 +761,18 @@ type NodeSetInfo struct {
 	DataTotal    uint64
 	DataNodes    []*DataNodeInfo
 }
+
+// NodeSetStat defines the utilization summary of a node set
+type NodeSetStat struct {
+	ID                uint64
+	ZoneName          string
+	DataNodeCount     int
+	MetaNodeCount     int
+	UsedRatio         float64
+	PartitionCount    uint64
+	CapacityRemaining uint64
+}
+
 type SimpleNodeSetGrpInfo struct {
 	ID          uint64
 	Status      uint8
@@ -528,6 +795,15 @@ type MasterAPIAccessResp struct {
 	Data    []byte        `json:"data"`
 }
 
+// VolSummary is the brief of a volume listed by getAllVols.
+type VolSummary struct {
+	Name     string
+	Status   uint8
+	Capacity uint64 // GB
+	UsedSize uint64
+	OwnerID  string
+}
+
 type VolInfo struct {
 	Name       string
 	Owner      string
@@ -535,6 +811,7 @@ type VolInfo struct {
 	Status     uint8
 	TotalSize  uint64
 	UsedSize   uint64
+	Tags       map[string]string `json:",omitempty"`
 }
 
 func NewVolInfo(name, owner string, createTime int64, status uint8, totalSize, usedSize uint64) *VolInfo {
@@ -564,5 +841,7 @@ type NodeSetView struct {
 
 // TopologyView provides the view of the topology view of the cluster
 type TopologyView struct {
-	Zones []*ZoneView
+	DataNodeTotal int
+	MetaNodeTotal int
+	Zones         []*ZoneView
 }
@@ -82,6 +82,8 @@ var (
 	ErrInvalidSecretKey                = errors.New("invalid secret key")
 	ErrIsOwner                         = errors.New("user owns the volume")
 	ErrZoneNum                         = errors.New("zone num not qualified")
+	ErrDuplicateNode                   = errors.New("duplicate node")
+	ErrClusterInMaintenance            = errors.New("cluster in maintenance")
 )
 
 // http response error code and error message definitions
@@ -146,6 +148,8 @@ const (
 	ErrCodeInvalidSecretKey
 	ErrCodeIsOwner
 	ErrCodeZoneNumError
+	ErrCodeDuplicateNode
+	ErrCodeClusterInMaintenance
 )
 
 // Err2CodeMap error map to code
@@ -208,6 +212,8 @@ var Err2CodeMap = map[error]int32{
 	ErrInvalidSecretKey:                ErrCodeInvalidSecretKey,
 	ErrIsOwner:                         ErrCodeIsOwner,
 	ErrZoneNum:                         ErrCodeZoneNumError,
+	ErrDuplicateNode:                   ErrCodeDuplicateNode,
+	ErrClusterInMaintenance:            ErrCodeClusterInMaintenance,
 }
 
 func ParseErrorCode(code int32) error {
@@ -277,6 +283,8 @@ var code2ErrMap = map[int32]error{
 	ErrCodeInvalidSecretKey:                ErrInvalidSecretKey,
 	ErrCodeIsOwner:                         ErrIsOwner,
 	ErrCodeZoneNumError:                    ErrZoneNum,
+	ErrCodeDuplicateNode:                   ErrDuplicateNode,
+	ErrCodeClusterInMaintenance:            ErrClusterInMaintenance,
 }
 
 type GeneralResp struct {
@@ -41,6 +41,7 @@ type MetaNodeInfo struct {
 	NodeSetID                 uint64
 	PersistenceMetaPartitions []uint64
 	RdOnly                    bool
+	Tags                      []string
 }
 
 // DataNode stores all the information about a data node
@@ -63,6 +64,9 @@ type DataNodeInfo struct {
 	PersistenceDataPartitions []uint64
 	BadDisks                  []string
 	RdOnly                    bool
+	DiskWrittenSizes          map[string]uint64
+	DiskReports               []*DiskReport
+	Tags                      []string
 }
 
 // MetaPartition defines the structure of a meta partition
@@ -96,29 +100,426 @@ type MetaReplicaInfo struct {
 
 // ClusterView provides the view of a cluster.
 type ClusterView struct {
+	Name                   string
+	LeaderAddr             string
+	Version                string // the build version of the master replying, that is the leader
+	StartTime              string
+	UptimeSeconds          int64
+	DisableAutoAlloc       bool // true if the cluster is frozen, i.e. the data partitions are not allocated automatically
+	MaintenanceMode        bool // true if the mutating APIs are rejected for the maintenance of the cluster
+	AllocationStrategy     string
+	HeartbeatVerbosity     string
+	MinRWDataPartitions    int     // the vols with fewer r&w data partitions get new ones automatically
+	AutoAllocUsedRatio     float64 // the vols with more used space than the ratio of the capacity get no new data partitions
+	VolCount               int
+	MaxVolumes             uint64
+	MetaNodeThreshold      float32
+	Applied                uint64
+	MaxDataPartitionID     uint64
+	MaxMetaNodeID          uint64
+	MaxMetaPartitionID     uint64
+	DataNodeStatInfo       *NodeStatInfo
+	MetaNodeStatInfo       *NodeStatInfo
+	VolStatInfo            []*VolStatInfo
+	BadPartitionIDs        []BadPartitionView
+	BadMetaPartitionIDs    []BadPartitionView
+	MetaNodes              []NodeView
+	DataNodes              []NodeView
+	VolAllocPriorities     []VolAllocPriorityView
+	AutoRebalancePolicy    *AutoRebalancePolicy
+	LastAutoRebalance      *AutoRebalanceRecord
+	AutoDecommissionPolicy *AutoDecommissionPolicy
+	PartitionSummary       *PartitionSummary
+}
+
+// RaftSnapshotView is the result of compacting the raft log of a master.
+type RaftSnapshotView struct {
+	NodeID uint64 // the master compacting its log
+	Index  uint64 // the raft log is truncated up to the index
+}
+
+// RaftStatus is the status of the raft group of the masters seen by a master.
+type RaftStatus struct {
+	ID         uint64 // the raft group
+	NodeID     uint64 // the master replying the status
+	LeaderID   uint64
+	LeaderAddr string
+	Term       uint64
+	Index      uint64
+	Commit     uint64
+	Applied    uint64
+	State      string
+	Peers      []*RaftPeerStatus
+}
+
+// RaftPeerStatus is the replication progress of a peer, only the leader knows it of the other peers.
+type RaftPeerStatus struct {
+	ID         uint64
+	Addr       string
+	IsLeader   bool
+	Match      uint64 // the last index replicated to the peer
+	Commit     uint64
+	Next       uint64
+	State      string
+	Active     bool
+	LastActive string `json:",omitempty"`
+}
+
+// PartitionStatusCount is the number of partitions of each status.
+type PartitionStatusCount struct {
+	ReadWrite   int
+	ReadOnly    int
+	Unavailable int
+}
+
+// PartitionSummary counts the data partitions and the meta partitions of the cluster by status.
+type PartitionSummary struct {
+	DataPartitions PartitionStatusCount
+	MetaPartitions PartitionStatusCount
+}
+
+// AutoRebalancePolicy defines when the master moves data partitions automatically to balance the data nodes.
+type AutoRebalancePolicy struct {
+	Enable             bool
+	MinBalanceScore    float64 // rebalance when the balance score drops below it
+	WindowStartHour    int     // rebalance only in [WindowStartHour, WindowEndHour), all day if they are the same
+	WindowEndHour      int
+	MaxConcurrentMoves int // the max number of data partitions recovering at the same time
+}
+
+// AutoRebalanceRecord records the last automatic rebalance.
+type AutoRebalanceRecord struct {
+	Time         int64
+	BalanceScore float64
+	Moves        int
+	Msg          string
+}
+
+// AutoDecommissionPolicy defines when the master decommissions a data node without heartbeat automatically.
+type AutoDecommissionPolicy struct {
+	Enable             bool
+	TimeoutSec         int64   // decommission the data nodes without heartbeat for longer than it
+	MaxConcurrentNodes int     // the max number of data nodes decommissioned in the background at the same time
+	MaxInactiveRatio   float64 // nothing is decommissioned if more of the data nodes are inactive, such as in a network partition
+}
+
+// DataPartitionMove is a replica of a data partition moved from SrcAddr to TargetAddr to balance the data nodes.
+type DataPartitionMove struct {
+	PartitionID uint64
+	VolName     string
+	SrcAddr     string
+	TargetAddr  string
+	Err         string `json:",omitempty"` // why the move failed, empty if it succeeded or is only planned
+}
+
+// DecommissionDryRun previews the decommission of the data partitions on a node or a disk, nothing is moved.
+type DecommissionDryRun struct {
+	Addr        string
+	DiskPath    string `json:",omitempty"`
+	Moves       []*DataPartitionMove
+	Unplaceable int                   // the moves without a target, the reasons are in their Err
+	PreCheck    *DecommissionPreCheck `json:",omitempty"`
+}
+
+// RebalancePlan is the result of rebalancing the data partitions, the moves are not executed if DryRun is set.
+type RebalancePlan struct {
+	DryRun       bool
+	BalanceScore float64
+	Recovering   int // the data partitions recovering when the plan was made
+	Moves        []*DataPartitionMove
+}
+
+// NodeTags are the tags to set on the data node and the meta node of the addr.
+type NodeTags struct {
+	Addr string
+	Tags []string
+}
+
+// BatchDecommissionDataPartitions is the request to decommission the data partitions on the addr.
+type BatchDecommissionDataPartitions struct {
+	Addr         string
+	PartitionIDs []uint64
+}
+
+// DataPartitionDecommissionResult is the result of decommissioning a data partition in a batch.
+type DataPartitionDecommissionResult struct {
+	PartitionID uint64
+	Success     bool
+	Msg         string
+}
+
+// VolCreationResult is the result of creating a volume in a batch.
+type VolCreationResult struct {
+	Name               string
+	Success            bool
+	Msg                string
+	DataPartitionCount int
+}
+
+// DiskDecommissionCancellation is the result of canceling the decommission of a disk.
+type DiskDecommissionCancellation struct {
+	JobID             string
+	Addr              string
+	DiskPath          string
+	PendingPartitions int // the partitions not decommissioned yet when it was canceled
+}
+
+// The types and the states of the jobs.
+const (
+	JobDecommissionDisk     = "decommissionDisk"
+	JobDecommissionDataNode = "decommissionDataNode"
+	JobMigrateDataNode      = "migrateDataNode"
+	JobMigrateMetaNode      = "migrateMetaNode"
+
+	JobStateRunning   = "running"
+	JobStateSucceeded = "succeeded"
+	JobStateFailed    = "failed"
+	JobStateCanceled  = "canceled"
+)
+
+// JobView is the progress of a long running operation, such as the decommission of a disk or a data node.
+type JobView struct {
+	ID        string
+	Type      string
+	Target    string // the node, the disk as addr:diskPath, or src->target of a migration
+	Total     int
+	Completed int
+	Failed    int
+	State     string
+	Msg       string `json:",omitempty"`
+	Note      string `json:",omitempty"` // the limits of the job, such as being lost on a leader change
+	StartTime string
+	EndTime   string `json:",omitempty"`
+}
+
+// DecommissioningNode is a data or meta node whose partitions are being moved off.
+type DecommissioningNode struct {
+	Addr                string
+	Type                string   // data or meta
+	RemainingPartitions int      // the partitions still on the node
+	Job                 *JobView `json:",omitempty"`
+}
+
+// DataNodeDecommissionJob is the job decommissioning a data node in the background.
+type DataNodeDecommissionJob struct {
+	JobID string
+	Addr  string
+	Rate  int // the partitions migrated per minute
+}
+
+// DataNodeDecommissionCancellation is the result of canceling the decommission of a data node.
+type DataNodeDecommissionCancellation struct {
+	JobID             string
+	Addr              string
+	PendingPartitions int // the partitions not migrated yet when it was canceled
+}
+
+// VolForceDeletion is the result of deleting a volume without waiting for the background check.
+type VolForceDeletion struct {
 	Name                string
-	LeaderAddr          string
-	DisableAutoAlloc    bool
-	MetaNodeThreshold   float32
-	Applied             uint64
-	MaxDataPartitionID  uint64
-	MaxMetaNodeID       uint64
-	MaxMetaPartitionID  uint64
-	DataNodeStatInfo    *NodeStatInfo
-	MetaNodeStatInfo    *NodeStatInfo
-	VolStatInfo         []*VolStatInfo
-	BadPartitionIDs     []BadPartitionView
-	BadMetaPartitionIDs []BadPartitionView
-	MetaNodes           []NodeView
-	DataNodes           []NodeView
+	ScheduledPartitions int // the data and meta partitions whose replicas are being deleted
+}
+
+// DataReplicaAddition is the result of adding a replica to a data partition.
+type DataReplicaAddition struct {
+	PartitionID uint64
+	Addr        string   // the data node of the new replica
+	Hosts       []string // the hosts of the partition after the addition
+}
+
+// MetaPartitionSplit is the result of splitting a meta partition at an inode.
+type MetaPartitionSplit struct {
+	PartitionID    uint64
+	End            uint64 // the new end of the partition, the inode of the split
+	NewPartitionID uint64
+	NewStart       uint64
+	NewEnd         uint64
+}
+
+// DataNodeDisk is the space and the load of a disk of a data node.
+type DataNodeDisk struct {
+	Path           string
+	Total          uint64
+	Used           uint64
+	Available      uint64
+	PartitionCount int
+	ReadOnly       bool
+	Decommissioned bool // decommissioned by the master, no data partition is created on it
+}
+
+// NodeTagsResult is the result of setting the tags of a node.
+type NodeTagsResult struct {
+	Addr    string
+	Success bool
+	Msg     string
+}
+
+// ClusterConfigView provides the view of the cluster-wide settings consulted by the placement.
+type ClusterConfigView struct {
+	DefaultZone        string
+	AllocationStrategy string
+	MaxVolumes         uint64
+	DisableAutoAlloc   bool
+	FaultDomain        bool
+	HeartbeatVerbosity string
+}
+
+// VolAllocPriorityView provides the view of a temporarily boosted volume allocation priority.
+type VolAllocPriorityView struct {
+	VolName    string
+	Priority   int
+	ExpireTime string
+}
+
+// DecommissionPreCheck is the result of simulating the decommission of a data node or a disk.
+type DecommissionPreCheck struct {
+	Addr           string
+	DiskPath       string
+	PartitionCount int
+	Passed         bool
+	Violations     []*DecommissionViolation
+}
+
+// DecommissionViolation describes a data partition which would go below its replica count after the decommission.
+type DecommissionViolation struct {
+	PartitionID uint64
+	VolName     string
+	ReplicaNum  uint8
+	Reason      string
+}
+
+// NodeSetMembers lists the nodes of a node set.
+type NodeSetMembers struct {
+	ID        uint64
+	ZoneName  string
+	Capacity  int
+	DataNodes []string
+	MetaNodes []string
+}
+
+// NodeSetFailureSimulation shows the impact of the failure of all the nodes of a node set.
+type NodeSetFailureSimulation struct {
+	NodeSetID          uint64
+	ZoneName           string
+	DataNodes          []string
+	MetaNodes          []string
+	AffectedPartitions []*PartitionReplicaLoss
+	UnavailableCount   int  // the partitions that would lose the raft majority
+	CanHeal            bool // all the lost replicas could be rebuilt on the other nodes
+}
+
+// PartitionReplicaLoss is the replicas a partition would lose in a simulated failure.
+type PartitionReplicaLoss struct {
+	PartitionID   uint64
+	PartitionType string // data or meta
+	VolName       string
+	ReplicaNum    uint8
+	LostReplicas  int
+	Unavailable   bool
+	Reason        string // why the lost replicas could not be rebuilt
+}
+
+// VolDeletionPreview shows what would be released by deleting a volume.
+type VolDeletionPreview struct {
+	VolName          string
+	DataPartitions   int
+	MetaPartitions   int
+	ReclaimableBytes uint64 // the space used by all the data partition replicas
+	AffectedNodes    []string
 }
 
+// VolSkewView compares the data partition placement of a volume with the even distribution over the data nodes.
+type VolSkewView struct {
+	VolName      string
+	ReplicaCount int
+	NodeCount    int
+	FairShare    float64 // replicas per data node if they were evenly distributed
+	MaxDeviation float64
+	SkewRatio    float64 // the replicas on the most loaded data node divided by the fair share
+	Nodes        []*VolSkewNode
+}
+
+// VolSkewNode is the number of data partition replicas of a volume on a data node.
+type VolSkewNode struct {
+	Addr           string
+	PartitionCount int
+	Deviation      float64 // positive if the data node holds more than its fair share
+}
+
+// PartitionIDRange is a range of partition ids [Start, End] reserved for a volume.
+type PartitionIDRange struct {
+	VolName string
+	Start   uint64
+	End     uint64
+}
+
+// BadPartitionSample is the number of bad data partitions at a point of time.
+type BadPartitionSample struct {
+	Time  int64
+	Count int
+}
+
+// SpaceSample is the space of the data nodes at a point of time.
+type SpaceSample struct {
+	Time      int64
+	TotalSize uint64
+	UsedSize  uint64
+}
+
+// CapacityForecast is the space of the data nodes and when they are full if the used space keeps growing as recently.
+type CapacityForecast struct {
+	TotalSize      uint64
+	UsedSize       uint64
+	AvailSize      uint64
+	UsedRatio      float64
+	Samples        int    // the samples the fill rate is derived from
+	FillRatePerDay int64  // the bytes used per day, negative if the used space shrinks
+	SecondsToFull  int64  // -1 if the used space does not grow
+	FullTime       string `json:",omitempty"`
+}
+
+// AuditEvent records an admin action performed on the master.
+type AuditEvent struct {
+	Time       int64
+	Action     string
+	CallerAddr string
+	Target     string
+	Result     string
+}
+
+// ClusterEvent is a change of the cluster, streamed to the clients of /events as it happens.
+type ClusterEvent struct {
+	Time   int64
+	Type   string
+	Target string
+	Msg    string `json:",omitempty"`
+}
+
+// The types of the cluster events.
+const (
+	EventDataNodeActive   = "dataNodeActive"
+	EventDataNodeInactive = "dataNodeInactive"
+	EventMetaNodeActive   = "metaNodeActive"
+	EventMetaNodeInactive = "metaNodeInactive"
+	EventBadDataPartition = "badDataPartition"
+	EventBadMetaPartition = "badMetaPartition"
+	EventVolCreated       = "volCreated"
+	EventVolDeleted       = "volDeleted"
+)
+
 // NodeView provides the view of the data or meta node.
 type NodeView struct {
-	Addr       string
-	Status     bool
-	ID         uint64
-	IsWritable bool
+	Addr            string
+	Status          bool
+	ID              uint64
+	IsWritable      bool
+	Total           uint64  `json:",omitempty"`
+	Used            uint64  `json:",omitempty"`
+	AvailableGB     uint64  `json:",omitempty"`
+	Ratio           float64 `json:",omitempty"` // the memory usage ratio of the meta node
+	Threshold       float32 `json:",omitempty"` // the memory threshold of the meta node
+	ReportTime      string  `json:",omitempty"` // the time of the last heartbeat in RFC3339
+	InactiveSeconds int64   `json:",omitempty"` // the seconds since the last heartbeat of an inactive node
 }
 
 type BadPartitionView struct {
@@ -165,6 +566,7 @@ type DataPartitionInfo struct {
 	PartitionID             uint64
 	LastLoadedTime          int64
 	ReplicaNum              uint8
+	LiveReplicaNum          uint8 // the number of hosts whose data node is active
 	Status                  int8
 	Recover                 bool
 	Replicas                []*DataReplica
@@ -206,6 +608,7 @@ type DataReplica struct {
 	IsLeader        bool
 	NeedsToCompare  bool
 	DiskPath        string
+	ApplyID         uint64
 }
 
 // data partition diagnosis represents the inactive data nodes, corrupt data partitions, and data partitions lack of replicas
@@ -216,6 +619,65 @@ type DataPartitionDiagnosis struct {
 	BadDataPartitionIDs         []BadPartitionView
 }
 
+// the reasons why the creation of a data partition failed
+const (
+	DpCreateFailedNoCapacity         = "no capacity"
+	DpCreateFailedNoEligibleHosts    = "no eligible hosts"
+	DpCreateFailedPlacementViolation = "placement violation"
+	DpCreateFailedUnknown            = "unknown"
+)
+
+// DataPartitionCreateResult lists the data partitions created by a batch.
+type DataPartitionCreateResult struct {
+	RequestCount int
+	PartitionIDs []uint64
+}
+
+// DataPartitionCreateFailure describes why a batch creation of data partitions stopped
+type DataPartitionCreateFailure struct {
+	RequestCount int
+	CreatedCount int
+	PartitionIDs []uint64 // the data partitions created before the failure, they are kept
+	FailedIndex  int      // the index of the data partition failed in the batch
+	Reason       string
+	Detail       string
+}
+
+// ReplicaLagView represents how far a replica falls behind the leader in applying the raft log
+type ReplicaLagView struct {
+	PartitionID uint64
+	VolName     string
+	Addr        string
+	IsLeader    bool
+	ApplyID     uint64
+	Lag         uint64
+}
+
+// OverReplicatedPartitionView represents a data partition whose recorded hosts exceed its replica number
+type OverReplicatedPartitionView struct {
+	PartitionID uint64
+	VolName     string
+	ReplicaNum  uint8
+	Hosts       []string
+	ExtraHosts  []string
+}
+
+// UnderReplicatedPartitionView represents a partition whose live replicas are fewer than its replica number
+type UnderReplicatedPartitionView struct {
+	PartitionID    uint64
+	VolName        string
+	ReplicaNum     int
+	LiveReplicaNum int
+	Hosts          []string
+	LiveHosts      []string
+}
+
+// UnderReplicatedPartitions is the report of the under replicated data and meta partitions of the cluster
+type UnderReplicatedPartitions struct {
+	DataPartitions []*UnderReplicatedPartitionView
+	MetaPartitions []*UnderReplicatedPartitionView
+}
+
 // meta partition diagnosis represents the inactive meta nodes, corrupt meta partitions, and meta partitions lack of replicas
 type MetaPartitionDiagnosis struct {
 	InactiveMetaNodes           []string
@@ -223,3 +685,58 @@ type MetaPartitionDiagnosis struct {
 	LackReplicaMetaPartitionIDs []uint64
 	BadMetaPartitionIDs         []BadPartitionView
 }
+
+// MasterConfigView is the effective config of a master, the secrets are masked.
+type MasterConfigView struct {
+	ClusterName string
+	Peers       []string
+	LeaderAddr  string
+
+	MetaNodeThreshold           float32
+	MetaNodeReservedMem         uint64
+	MetaNodeDeleteBatchCount    uint64
+	MetaNodeDeleteWorkerSleepMs uint64
+	DataNodeDeleteLimitRate     uint64
+	DataNodeAutoRepairLimitRate uint64
+	DataPartitionUsageThreshold float64
+
+	NodeTimeOutSec                      int64
+	DataPartitionTimeOutSec             int64
+	MissingDataPartitionInterval        int64
+	IntervalToAlarmMissingDataPartition int64
+	PeriodToLoadALLDataPartitions       int64
+	IntervalToCheckDataPartition        int
+	NumberOfDataPartitionsToLoad        int
+	SecondsToFreeDataPartitionAfterLoad int64
+	NodeSetCapacity                     int
+	FaultDomain                         bool
+	DomainNodeGrpBatchCnt               int
+	DomainBuildAsPossible               bool
+
+	MinReplicaNum       int
+	MaxReplicaNum       int
+	MinVolCapacity      uint64 // GB
+	MaxVolCapacity      uint64 // GB, 0 means no limit
+	VolNameMinLen       int
+	VolNameMaxLen       int
+	VolNamePattern      string
+	VolDeletionGraceSec int64
+
+	AdminToken                   string // masked, empty if the APIs are not protected by a token
+	SecureRead                   bool
+	StrictParamCheck             bool
+	CORSAllowedOrigins           []string
+	CORSMutatingAPIs             bool
+	BatchDecommissionConcurrency int
+	DecommissionRate             int
+	RebalanceMaxConcurrentMoves  int
+	ReadyMaxAppliedLag           uint64
+	TaskResponseQPS              int
+	MaxTaskResponseBytes         int64
+	JobRetentionSec              int64
+	GzipMinBytes                 int
+	AuditLogCapacity             int
+	DataNodeAllocStrategy        string // the placement strategy until one is set by /cluster/setAllocationStrategy
+
+	Cluster *ClusterConfigView // the cluster-wide settings, such as the automatic allocation
+}
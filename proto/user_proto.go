@@ -219,6 +219,21 @@ func (policy *UserPolicy) RemoveAuthorizedVol(volume string) {
 	delete(policy.AuthorizedVols, volume)
 }
 
+// RenameVol moves the ownership and the authorization of the volume to its new name.
+func (policy *UserPolicy) RenameVol(oldName, newName string) {
+	policy.mu.Lock()
+	defer policy.mu.Unlock()
+	for i, ownVol := range policy.OwnVols {
+		if ownVol == oldName {
+			policy.OwnVols[i] = newName
+		}
+	}
+	if actions, ok := policy.AuthorizedVols[oldName]; ok {
+		delete(policy.AuthorizedVols, oldName)
+		policy.AuthorizedVols[newName] = actions
+	}
+}
+
 func (policy *UserPolicy) SetPerm(volume string, perm Permission) {
 	policy.mu.Lock()
 	defer policy.mu.Unlock()
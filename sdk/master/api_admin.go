@@ -26,6 +26,49 @@ type AdminAPI struct {
 	mc *MasterClient
 }
 
+func (api *AdminAPI) GetRaftStatus() (status *proto.RaftStatus, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.GetRaftStatus)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	status = &proto.RaftStatus{}
+	if err = json.Unmarshal(buf, status); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) TransferRaftLeader(id uint64) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.TransferRaftLeader)
+	request.addParam("id", strconv.FormatUint(id, 10))
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+// StepDownRaftLeader makes the master requested give up the leadership, it fails if the master is not the leader.
+func (api *AdminAPI) StepDownRaftLeader() (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.StepDownRaftLeader)
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+// SnapshotRaftLog compacts the raft log of the master requested, and returns the index it is truncated up to.
+func (api *AdminAPI) SnapshotRaftLog() (view *proto.RaftSnapshotView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.SnapshotRaftLog)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	view = &proto.RaftSnapshotView{}
+	if err = json.Unmarshal(buf, view); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) GetCluster() (cv *proto.ClusterView, err error) {
 	var buf []byte
 	var request = newAPIRequest(http.MethodGet, proto.AdminGetCluster)
@@ -38,6 +81,18 @@ func (api *AdminAPI) GetCluster() (cv *proto.ClusterView, err error) {
 	}
 	return
 }
+func (api *AdminAPI) GetConfig() (cv *proto.MasterConfigView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetConfig)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	cv = &proto.MasterConfigView{}
+	if err = json.Unmarshal(buf, &cv); err != nil {
+		return
+	}
+	return
+}
 func (api *AdminAPI) GetClusterStat() (cs *proto.ClusterStatInfo, err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminClusterStat)
 	request.addHeader("isTimeOut", "false")
@@ -76,6 +131,35 @@ func (api *AdminAPI) Topo() (topo *proto.TopologyView, err error) {
 	return
 }
 
+// TopoPage lists count of the data nodes and count of the meta nodes from start in the topology, with the totals.
+func (api *AdminAPI) TopoPage(start, count int) (topo *proto.TopologyView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.GetTopologyView)
+	request.addParam("start", strconv.Itoa(start))
+	request.addParam("count", strconv.Itoa(count))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	topo = &proto.TopologyView{}
+	if err = json.Unmarshal(buf, topo); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetNodeSetStats() (stats []*proto.NodeSetStat, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetNodeSetStats)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	stats = make([]*proto.NodeSetStat, 0)
+	if err = json.Unmarshal(buf, &stats); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) GetDataPartition(volName string, partitionID uint64) (partition *proto.DataPartitionInfo, err error) {
 	var buf []byte
 	var request = newAPIRequest(http.MethodGet, proto.AdminGetDataPartition)
@@ -104,6 +188,73 @@ func (api *AdminAPI) DiagnoseDataPartition() (diagnosis *proto.DataPartitionDiag
 	return
 }
 
+func (api *AdminAPI) GetUnderReplicatedPartitions() (report *proto.UnderReplicatedPartitions, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetUnderReplicated)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	report = &proto.UnderReplicatedPartitions{}
+	if err = json.Unmarshal(buf, report); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetDecommissioningNodes() (nodes []*proto.DecommissioningNode, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetDecommissioningNodes)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	nodes = make([]*proto.DecommissioningNode, 0)
+	if err = json.Unmarshal(buf, &nodes); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetOverReplicatedDataPartitions() (views []*proto.OverReplicatedPartitionView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetOverReplicatedDps)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	views = make([]*proto.OverReplicatedPartitionView, 0)
+	if err = json.Unmarshal(buf, &views); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetReplicaLag(partitionID uint64) (lags []*proto.ReplicaLagView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetReplicaLag)
+	request.addParam("id", strconv.FormatUint(partitionID, 10))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	lags = make([]*proto.ReplicaLagView, 0)
+	if err = json.Unmarshal(buf, &lags); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetLaggingReplicas(count int) (lags []*proto.ReplicaLagView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetLaggingReplicas)
+	request.addParam("count", strconv.Itoa(count))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	lags = make([]*proto.ReplicaLagView, 0)
+	if err = json.Unmarshal(buf, &lags); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) DiagnoseMetaPartition() (diagnosis *proto.MetaPartitionDiagnosis, err error) {
 	var buf []byte
 	var request = newAPIRequest(http.MethodGet, proto.AdminDiagnoseMetaPartition)
@@ -127,6 +278,25 @@ func (api *AdminAPI) LoadDataPartition(volName string, partitionID uint64) (err
 	return
 }
 
+// VerifyDataPartition compares the file checksums of the replicas of the data partition, waiting at most timeoutSec
+// seconds for them to respond, 0 for the default of the master.
+func (api *AdminAPI) VerifyDataPartition(partitionID uint64, timeoutSec int) (result *proto.DataPartitionVerification, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminVerifyDataPartition)
+	request.addParam("id", strconv.FormatUint(partitionID, 10))
+	if timeoutSec > 0 {
+		request.addParam("timeout", strconv.Itoa(timeoutSec))
+	}
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	result = &proto.DataPartitionVerification{}
+	if err = json.Unmarshal(buf, result); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) CreateDataPartition(volName string, count int) (err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminCreateDataPartition)
 	request.addParam("name", volName)
@@ -137,6 +307,37 @@ func (api *AdminAPI) CreateDataPartition(volName string, count int) (err error)
 	return
 }
 
+func (api *AdminAPI) BatchDecommissionDataPartitions(nodeAddr string, dataPartitionIDs []uint64) (results []*proto.DataPartitionDecommissionResult, err error) {
+	var reqBody, buf []byte
+	if reqBody, err = json.Marshal(&proto.BatchDecommissionDataPartitions{Addr: nodeAddr, PartitionIDs: dataPartitionIDs}); err != nil {
+		return
+	}
+	var request = newAPIRequest(http.MethodPost, proto.AdminBatchDecommissionDps)
+	request.addBody(reqBody)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	results = make([]*proto.DataPartitionDecommissionResult, 0)
+	if err = json.Unmarshal(buf, &results); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetJob(jobID string) (job *proto.JobView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetJob)
+	request.addParam("id", jobID)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	job = &proto.JobView{}
+	if err = json.Unmarshal(buf, job); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) DecommissionDataPartition(dataPartitionID uint64, nodeAddr string) (err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminDecommissionDataPartition)
 	request.addParam("id", strconv.FormatUint(dataPartitionID, 10))
@@ -157,6 +358,53 @@ func (api *AdminAPI) DecommissionMetaPartition(metaPartitionID uint64, nodeAddr
 	return
 }
 
+func (api *AdminAPI) SplitMetaPartition(metaPartitionID, inode uint64) (split *proto.MetaPartitionSplit, err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSplitMetaPartition)
+	request.addParam("id", strconv.FormatUint(metaPartitionID, 10))
+	request.addParam("inode", strconv.FormatUint(inode, 10))
+	var buf []byte
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	split = &proto.MetaPartitionSplit{}
+	if err = json.Unmarshal(buf, split); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) MigrateDataPartition(dataPartitionID uint64, srcAddr, targetAddr string) (hosts []string, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminMigrateDataPartition)
+	request.addParam("id", strconv.FormatUint(dataPartitionID, 10))
+	request.addParam("srcAddr", srcAddr)
+	request.addParam("targetAddr", targetAddr)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	if err = json.Unmarshal(buf, &hosts); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) RebalanceDataPartitions(maxMoves int, dryRun bool) (plan *proto.RebalancePlan, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminRebalanceDataPartitions)
+	if maxMoves > 0 {
+		request.addParam("maxMoves", strconv.Itoa(maxMoves))
+	}
+	request.addParam("dryRun", strconv.FormatBool(dryRun))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	plan = &proto.RebalancePlan{}
+	if err = json.Unmarshal(buf, plan); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) DeleteDataReplica(dataPartitionID uint64, nodeAddr string) (err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminDeleteDataReplica)
 	request.addParam("id", strconv.FormatUint(dataPartitionID, 10))
@@ -207,6 +455,43 @@ func (api *AdminAPI) DeleteVolume(volName, authKey string) (err error) {
 	return
 }
 
+func (api *AdminAPI) UndeleteVolume(volName, authKey string) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminUndeleteVol)
+	request.addParam("name", volName)
+	request.addParam("authKey", authKey)
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) RenameVolume(volName, newName, authKey string) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminRenameVol)
+	request.addParam("name", volName)
+	request.addParam("newName", newName)
+	request.addParam("authKey", authKey)
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) ForceDeleteVolume(volName, authKey string) (count int, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminDeleteVol)
+	request.addParam("name", volName)
+	request.addParam("authKey", authKey)
+	request.addParam("force", "true")
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	deletion := &proto.VolForceDeletion{}
+	if err = json.Unmarshal(buf, deletion); err != nil {
+		return
+	}
+	return deletion.ScheduledPartitions, nil
+}
+
 func (api *AdminAPI) UpdateVolume(volName string, capacity uint64, replicas int, followerRead, authenticate bool, authKey, zoneName string) (err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminUpdateVol)
 	request.addParam("name", volName)
@@ -222,6 +507,33 @@ func (api *AdminAPI) UpdateVolume(volName string, capacity uint64, replicas int,
 	return
 }
 
+func (api *AdminAPI) SetVolumeTags(volName, authKey string, tags map[string]string) (err error) {
+	var value []byte
+	if value, err = json.Marshal(tags); err != nil {
+		return
+	}
+	var request = newAPIRequest(http.MethodGet, proto.AdminUpdateVol)
+	request.addParam("name", volName)
+	request.addParam("authKey", authKey)
+	request.addParam("tags", string(value))
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SetVolumeQos(volName, authKey string, bandwidthLimit, iopsLimit uint64) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminUpdateVol)
+	request.addParam("name", volName)
+	request.addParam("authKey", authKey)
+	request.addParam("bandwidthLimit", strconv.FormatUint(bandwidthLimit, 10))
+	request.addParam("iopsLimit", strconv.FormatUint(iopsLimit, 10))
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) VolShrink(volName string, capacity uint64, authKey string) (err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminVolShrink)
 	request.addParam("name", volName)
@@ -244,6 +556,339 @@ func (api *AdminAPI) VolExpand(volName string, capacity uint64, authKey string)
 	return
 }
 
+func (api *AdminAPI) SetVolAllocationPriority(volName string, priority int, ttlSec int64) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetVolAllocPriority)
+	request.addParam("name", volName)
+	request.addParam("priority", strconv.Itoa(priority))
+	request.addParam("ttl", strconv.FormatInt(ttlSec, 10))
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SetAllocationStrategy(strategy string) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetAllocationStrategy)
+	request.addParam("strategy", strategy)
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetAuditByAddr(addr string, startTime, endTime int64) (events []*proto.AuditEvent, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetAuditByAddr)
+	request.addParam("addr", addr)
+	request.addParam("startTime", strconv.FormatInt(startTime, 10))
+	request.addParam("endTime", strconv.FormatInt(endTime, 10))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	events = make([]*proto.AuditEvent, 0)
+	if err = json.Unmarshal(buf, &events); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetAuditLog(count int, action string) (events []*proto.AuditEvent, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetAuditLog)
+	request.addParam("count", strconv.Itoa(count))
+	if action != "" {
+		request.addParam("action", action)
+	}
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	events = make([]*proto.AuditEvent, 0)
+	if err = json.Unmarshal(buf, &events); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SetLogLevel(level string) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetLogLevel)
+	request.addParam("level", level)
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetLogLevel() (level string, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetLogLevel)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	if err = json.Unmarshal(buf, &level); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) PreCheckDecommission(addr, diskPath string) (result *proto.DecommissionPreCheck, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminPreCheckDecommission)
+	request.addParam("addr", addr)
+	if diskPath != "" {
+		request.addParam("disk", diskPath)
+	}
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	result = &proto.DecommissionPreCheck{}
+	if err = json.Unmarshal(buf, result); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SetVolIOPriority(volName, class string) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetVolIOPriority)
+	request.addParam("name", volName)
+	request.addParam("class", class)
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SetVolReadOnly(volName string, readOnly bool) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetVolReadOnly)
+	request.addParam("name", volName)
+	request.addParam("enable", strconv.FormatBool(readOnly))
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetBadPartitionTrend(hours int) (samples []*proto.BadPartitionSample, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetBadPartitionTrend)
+	request.addParam("hours", strconv.Itoa(hours))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	samples = make([]*proto.BadPartitionSample, 0)
+	if err = json.Unmarshal(buf, &samples); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetCapacityForecast(hours int) (forecast *proto.CapacityForecast, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetCapacityForecast)
+	request.addParam("hours", strconv.Itoa(hours))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	forecast = &proto.CapacityForecast{}
+	if err = json.Unmarshal(buf, forecast); err != nil {
+		return
+	}
+	return
+}
+
+// ReservePartitionIDs reserves the data partition ids for the volume on the leader, the ids not consumed by
+// the data partitions created later are lost when the leader changes.
+func (api *AdminAPI) ReservePartitionIDs(volName string, count int) (idRange *proto.PartitionIDRange, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminReservePartitionIDs)
+	request.addParam("name", volName)
+	request.addParam("count", strconv.Itoa(count))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	idRange = &proto.PartitionIDRange{}
+	if err = json.Unmarshal(buf, idRange); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SetMaxVolumes(maxVolumes uint64) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetMaxVolumes)
+	request.addParam("count", strconv.FormatUint(maxVolumes, 10))
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetVolSkew(volName string) (skew *proto.VolSkewView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetVolSkew)
+	request.addParam("name", volName)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	skew = &proto.VolSkewView{}
+	if err = json.Unmarshal(buf, skew); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) PreviewVolDeletion(volName string) (preview *proto.VolDeletionPreview, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminPreviewVolDeletion)
+	request.addParam("name", volName)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	preview = &proto.VolDeletionPreview{}
+	if err = json.Unmarshal(buf, preview); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SetDefaultZone(zoneName string) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetDefaultZone)
+	request.addParam("zoneName", zoneName)
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetClusterConfig() (cv *proto.ClusterConfigView, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetClusterConfig)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	cv = &proto.ClusterConfigView{}
+	if err = json.Unmarshal(buf, cv); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) BatchSetNodeTags(items []*proto.NodeTags) (results []*proto.NodeTagsResult, err error) {
+	var reqBody, buf []byte
+	if reqBody, err = json.Marshal(items); err != nil {
+		return
+	}
+	var request = newAPIRequest(http.MethodPost, proto.AdminBatchSetNodeTags)
+	request.addBody(reqBody)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	results = make([]*proto.NodeTagsResult, 0)
+	if err = json.Unmarshal(buf, &results); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SimulateNodeSetFailure(nodeSetID uint64) (result *proto.NodeSetFailureSimulation, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminSimulateNodeSetFailure)
+	request.addParam("id", strconv.FormatUint(nodeSetID, 10))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	result = &proto.NodeSetFailureSimulation{}
+	if err = json.Unmarshal(buf, result); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) CreateNodeSet(zoneName string, capacity int) (members *proto.NodeSetMembers, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodPost, proto.AdminCreateNodeSet)
+	request.addParam("zoneName", zoneName)
+	if capacity > 0 {
+		request.addParam("capacity", strconv.Itoa(capacity))
+	}
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	members = &proto.NodeSetMembers{}
+	if err = json.Unmarshal(buf, members); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetNodeSet(nodeSetID uint64) (members *proto.NodeSetMembers, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetNodeSet)
+	request.addParam("id", strconv.FormatUint(nodeSetID, 10))
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	members = &proto.NodeSetMembers{}
+	if err = json.Unmarshal(buf, members); err != nil {
+		return
+	}
+	return
+}
+
+// nodeType is "data" or "meta".
+func (api *AdminAPI) MoveNodeToNodeSet(addr, nodeType string, nodeSetID uint64) (err error) {
+	var request = newAPIRequest(http.MethodPost, proto.AdminMoveNodeToNodeSet)
+	request.addParam("addr", addr)
+	request.addParam("type", nodeType)
+	request.addParam("id", strconv.FormatUint(nodeSetID, 10))
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+func (api *AdminAPI) SetHeartbeatVerbosity(verbosity string) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetHeartbeatVerbosity)
+	request.addParam("verbosity", verbosity)
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+// SetAutoAllocThreshold sets the thresholds of the automatic allocation of the data partitions,
+// minRWDataPartitions of 0 or usedRatio of 0 keeps the value in effect.
+func (api *AdminAPI) SetAutoAllocThreshold(minRWDataPartitions int, usedRatio float64) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetAutoAllocThreshold)
+	if minRWDataPartitions > 0 {
+		request.addParam("count", strconv.Itoa(minRWDataPartitions))
+	}
+	if usedRatio > 0 {
+		request.addParam("ratio", strconv.FormatFloat(usedRatio, 'f', -1, 64))
+	}
+	_, err = api.mc.serveRequest(request)
+	return
+}
+
+func (api *AdminAPI) SetAutoRebalancePolicy(policy *proto.AutoRebalancePolicy) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetAutoRebalancePolicy)
+	request.addParam("enable", strconv.FormatBool(policy.Enable))
+	request.addParam("minScore", strconv.FormatFloat(policy.MinBalanceScore, 'f', -1, 64))
+	request.addParam("windowStart", strconv.Itoa(policy.WindowStartHour))
+	request.addParam("windowEnd", strconv.Itoa(policy.WindowEndHour))
+	request.addParam("maxMoves", strconv.Itoa(policy.MaxConcurrentMoves))
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) SetAutoDecommission(enable bool, timeoutSec int64) (err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminSetAutoDecommission)
+	request.addParam("enable", strconv.FormatBool(enable))
+	request.addParam("timeout", strconv.FormatInt(timeoutSec, 10))
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) CreateVolume(volName, owner string, mpCount int,
 	dpSize uint64, capacity uint64, replicas int, followerRead bool, zoneName string, crossZone bool) (err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminCreateVol)
@@ -286,6 +931,64 @@ func (api *AdminAPI) GetVolumeSimpleInfo(volName string) (vv *proto.SimpleVolVie
 	return
 }
 
+func (api *AdminAPI) ExportVolume(volName string) (spec *proto.VolSpec, err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminExportVol)
+	request.addParam("name", volName)
+	var buf []byte
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	spec = &proto.VolSpec{}
+	if err = json.Unmarshal(buf, spec); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetVolumeHistory(volName string) (history []*proto.VolCapacityChange, err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetVolHistory)
+	request.addParam("name", volName)
+	var buf []byte
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	history = make([]*proto.VolCapacityChange, 0)
+	if err = json.Unmarshal(buf, &history); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) BatchCreateVolumes(reqs []*proto.CreateVolRequest) (results []*proto.VolCreationResult, err error) {
+	var reqBody, buf []byte
+	if reqBody, err = json.Marshal(reqs); err != nil {
+		return
+	}
+	var request = newAPIRequest(http.MethodPost, proto.AdminBatchCreateVol)
+	request.addBody(reqBody)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	results = make([]*proto.VolCreationResult, 0)
+	if err = json.Unmarshal(buf, &results); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) ImportVolume(spec *proto.VolSpec) (err error) {
+	var request = newAPIRequest(http.MethodPost, proto.AdminImportVol)
+	var reqBody []byte
+	if reqBody, err = json.Marshal(spec); err != nil {
+		return
+	}
+	request.addBody(reqBody)
+	if _, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) GetClusterInfo() (ci *proto.ClusterInfo, err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminGetIP)
 	var buf []byte
@@ -323,6 +1026,35 @@ func (api *AdminAPI) ListVols(keywords string) (volsInfo []*proto.VolInfo, err e
 	return
 }
 
+func (api *AdminAPI) ListVolsByOwner(keywords, owner string) (volsInfo []*proto.VolInfo, err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminListVols)
+	request.addParam("keywords", keywords)
+	request.addParam("owner", owner)
+	var buf []byte
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	volsInfo = make([]*proto.VolInfo, 0)
+	if err = json.Unmarshal(buf, &volsInfo); err != nil {
+		return
+	}
+	return
+}
+
+func (api *AdminAPI) GetAllVols(keyword string) (vols []*proto.VolSummary, err error) {
+	var request = newAPIRequest(http.MethodGet, proto.AdminGetAllVols)
+	request.addParam("keyword", keyword)
+	var buf []byte
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	vols = make([]*proto.VolSummary, 0)
+	if err = json.Unmarshal(buf, &vols); err != nil {
+		return
+	}
+	return
+}
+
 func (api *AdminAPI) IsFreezeCluster(isFreeze bool) (err error) {
 	var request = newAPIRequest(http.MethodGet, proto.AdminClusterFreeze)
 	request.addParam("enable", strconv.FormatBool(isFreeze))
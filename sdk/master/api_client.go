@@ -125,6 +125,37 @@ func (api *ClientAPI) GetMetaPartitions(volName string) (views []*proto.MetaPart
 	return
 }
 
+func (api *ClientAPI) GetDataPartitionsPage(volName string, start, count int) (page *proto.DataPartitionsPage, err error) {
+	var request = newAPIRequest(http.MethodGet, proto.ClientDataPartitions)
+	request.addParam("name", volName)
+	request.addParam("start", strconv.Itoa(start))
+	request.addParam("count", strconv.Itoa(count))
+	var data []byte
+	if data, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	page = &proto.DataPartitionsPage{}
+	if err = json.Unmarshal(data, page); err != nil {
+		return
+	}
+	return
+}
+
+func (api *ClientAPI) GetDataPartitionsGroupedByStatus(volName string) (view *proto.DataPartitionsGroupedView, err error) {
+	var request = newAPIRequest(http.MethodGet, proto.ClientDataPartitions)
+	request.addParam("name", volName)
+	request.addParam("groupByStatus", "true")
+	var data []byte
+	if data, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	view = &proto.DataPartitionsGroupedView{}
+	if err = json.Unmarshal(data, view); err != nil {
+		return
+	}
+	return
+}
+
 func (api *ClientAPI) GetDataPartitions(volName string) (view *proto.DataPartitionsView, err error) {
 	var request = newAPIRequest(http.MethodGet, proto.ClientDataPartitions)
 	request.addParam("name", volName)
@@ -31,7 +31,15 @@ func (api *NodeAPI) AddDataNode(serverAddr, zoneName string) (id uint64, err err
 	request.addParam("addr", serverAddr)
 	request.addParam("zoneName", zoneName)
 	var data []byte
-	if data, err = api.mc.serveRequest(request); err != nil {
+	if data, err = api.mc.serveRequest(request); err == proto.ErrDuplicateNode {
+		// a restarted data node registers itself again, go on with the id it was given
+		var node *proto.DataNodeInfo
+		if node, err = api.GetDataNode(serverAddr); err != nil {
+			return
+		}
+		return node.ID, nil
+	}
+	if err != nil {
 		return
 	}
 	id, err = strconv.ParseUint(string(data), 10, 64)
@@ -43,7 +51,15 @@ func (api *NodeAPI) AddMetaNode(serverAddr, zoneName string) (id uint64, err err
 	request.addParam("addr", serverAddr)
 	request.addParam("zoneName", zoneName)
 	var data []byte
-	if data, err = api.mc.serveRequest(request); err != nil {
+	if data, err = api.mc.serveRequest(request); err == proto.ErrDuplicateNode {
+		// a restarted meta node registers itself again, go on with the id it was given
+		var node *proto.MetaNodeInfo
+		if node, err = api.GetMetaNode(serverAddr); err != nil {
+			return
+		}
+		return node.ID, nil
+	}
+	if err != nil {
 		return
 	}
 	id, err = strconv.ParseUint(string(data), 10, 64)
@@ -64,6 +80,49 @@ func (api *NodeAPI) GetDataNode(serverHost string) (node *proto.DataNodeInfo, er
 	return
 }
 
+func (api *NodeAPI) GetDataNodePartitions(serverHost string) (partitions []*proto.NodeDataPartition, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.GetDataNodePartitions)
+	request.addParam("addr", serverHost)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	partitions = make([]*proto.NodeDataPartition, 0)
+	if err = json.Unmarshal(buf, &partitions); err != nil {
+		return
+	}
+	return
+}
+
+func (api *NodeAPI) GetDataNodeDisks(serverHost string) (disks []*proto.DataNodeDisk, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.GetDataNodeDisks)
+	request.addParam("addr", serverHost)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	disks = make([]*proto.DataNodeDisk, 0)
+	if err = json.Unmarshal(buf, &disks); err != nil {
+		return
+	}
+	return
+}
+
+func (api *NodeAPI) RecommissionDisk(serverHost, diskPath string) (disk *proto.DataNodeDisk, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.RecommissionDisk)
+	request.addParam("addr", serverHost)
+	request.addParam("disk", diskPath)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	disk = &proto.DataNodeDisk{}
+	if err = json.Unmarshal(buf, disk); err != nil {
+		return
+	}
+	return
+}
+
 func (api *NodeAPI) GetMetaNode(serverHost string) (node *proto.MetaNodeInfo, err error) {
 	var buf []byte
 	var request = newAPIRequest(http.MethodGet, proto.GetMetaNode)
@@ -78,6 +137,20 @@ func (api *NodeAPI) GetMetaNode(serverHost string) (node *proto.MetaNodeInfo, er
 	return
 }
 
+func (api *NodeAPI) GetMetaNodePartitions(serverHost string) (partitions []*proto.NodeMetaPartition, err error) {
+	var buf []byte
+	var request = newAPIRequest(http.MethodGet, proto.GetMetaNodePartitions)
+	request.addParam("addr", serverHost)
+	if buf, err = api.mc.serveRequest(request); err != nil {
+		return
+	}
+	partitions = make([]*proto.NodeMetaPartition, 0)
+	if err = json.Unmarshal(buf, &partitions); err != nil {
+		return
+	}
+	return
+}
+
 func (api *NodeAPI) ResponseMetaNodeTask(task *proto.AdminTask) (err error) {
 	var encoded []byte
 	if encoded, err = json.Marshal(task); err != nil {
@@ -44,6 +44,7 @@ type MasterClient struct {
 	useSSL     bool
 	leaderAddr string
 	timeout    time.Duration
+	adminToken string
 
 	adminAPI  *AdminAPI
 	clientAPI *ClientAPI
@@ -96,6 +97,13 @@ func (c *MasterClient) SetTimeout(timeout uint16) {
 	c.Unlock()
 }
 
+// Set the admin token sent with every request, which the master requires for the mutating APIs if configured.
+func (c *MasterClient) SetAdminToken(token string) {
+	c.Lock()
+	c.adminToken = token
+	c.Unlock()
+}
+
 func (c *MasterClient) serveRequest(r *request) (repsData []byte, err error) {
 	leaderAddr, nodes := c.prepareRequest()
 	host := leaderAddr
@@ -140,7 +148,24 @@ func (c *MasterClient) serveRequest(r *request) (repsData []byte, err error) {
 			}
 			repsData, err = c.serveRequest(r)
 			return
-		case http.StatusOK:
+		case http.StatusUnauthorized:
+			log.LogWarnf("serveRequest: server response status 401: host(%v) uri(%v) body(%s)",
+				host, r.path, strings.Replace(string(repsData), "\n", "", -1))
+			err = proto.ErrNoPermission
+			return
+		case http.StatusServiceUnavailable:
+			log.LogWarnf("serveRequest: server response status 503: host(%v) uri(%v) body(%s)",
+				host, r.path, strings.Replace(string(repsData), "\n", "", -1))
+			// other masters may serve the request unless the cluster is in maintenance, which is told by the code of the body
+			var body = &struct {
+				Code int32 `json:"code"`
+			}{}
+			if json.Unmarshal(repsData, body) == nil && body.Code == proto.ErrCodeClusterInMaintenance {
+				err = proto.ErrClusterInMaintenance
+				return
+			}
+			continue
+		case http.StatusOK, http.StatusCreated:
 			if leaderAddr != host {
 				log.LogDebugf("server Request resp new master[%v] old [%v]", host, leaderAddr)
 				c.setLeader(host)
@@ -202,6 +227,13 @@ func (c *MasterClient) httpRequest(method, url string, param, header map[string]
 	} else {
 		client.Timeout = c.timeout
 	}
+	c.RLock()
+	adminToken := c.adminToken
+	c.RUnlock()
+	if adminToken != "" {
+		// the Authorization header is dropped when a follower redirects the request to the leader on another host
+		client = &http.Client{Timeout: client.Timeout, CheckRedirect: keepAuthorization}
+	}
 	var req *http.Request
 	fullUrl := c.mergeRequestUrl(url, param)
 	log.LogDebugf("httpRequest: merge request url: method(%v) url(%v) bodyLength[%v].", method, fullUrl, len(reqData))
@@ -210,6 +242,9 @@ func (c *MasterClient) httpRequest(method, url string, param, header map[string]
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Connection", "close")
+	if adminToken != "" {
+		req.Header.Set(proto.HeadAuthorized, "Bearer "+adminToken)
+	}
 	for k, v := range header {
 		req.Header.Set(k, v)
 	}
@@ -217,6 +252,14 @@ func (c *MasterClient) httpRequest(method, url string, param, header map[string]
 	return
 }
 
+func keepAuthorization(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	req.Header.Set(proto.HeadAuthorized, via[0].Header.Get(proto.HeadAuthorized))
+	return nil
+}
+
 func (c *MasterClient) updateMaster(address string) {
 	contains := false
 	for _, master := range c.masters {
@@ -203,6 +203,28 @@ func (c *Config) CheckAndGetBool(key string) (bool, bool) {
 	return false, false
 }
 
+// Check and get an int64 for the config key, the value can be a number or a string of it.
+// The error is returned if the value is present but not an integer.
+func (c *Config) CheckAndGetInt64(key string) (value int64, present bool, err error) {
+	x, present := c.data[key]
+	if !present {
+		return 0, false, nil
+	}
+	switch result := x.(type) {
+	case int64:
+		return result, true, nil
+	case float64:
+		if result == float64(int64(result)) {
+			return int64(result), true, nil
+		}
+	case string:
+		if value, err = strconv.ParseInt(result, 10, 64); err == nil {
+			return value, true, nil
+		}
+	}
+	return 0, true, NewIllegalConfigError(key)
+}
+
 func NewIllegalConfigError(configKey string) error {
 	return fmt.Errorf("illegal config %s", configKey)
 }
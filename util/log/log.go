@@ -30,6 +30,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
@@ -239,7 +240,7 @@ type Log struct {
 	readLogger     *LogObject
 	updateLogger   *LogObject
 	criticalLogger *LogObject
-	level          Level
+	level          uint32 // the Level, it is changed at runtime so it is accessed atomically
 	msgC           chan string
 	rotate         *LogRotate
 	lastRolledTime time.Time
@@ -344,10 +345,18 @@ func (l *Log) initLog(logDir, module string, level Level) error {
 			return err
 		}
 	}
-	l.level = level
+	l.storeLevel(level)
 	return nil
 }
 
+func (l *Log) loadLevel() Level {
+	return Level(atomic.LoadUint32(&l.level))
+}
+
+func (l *Log) storeLevel(level Level) {
+	atomic.StoreUint32(&l.level, uint32(level))
+}
+
 // SetPrefix sets the log prefix.
 func (l *Log) SetPrefix(s, level string) string {
 	_, file, line, ok := runtime.Caller(2)
@@ -395,9 +404,18 @@ func SetLogLevel(w http.ResponseWriter, r *http.Request) {
 		buildFailureResp(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	levelStr := r.FormValue("level")
 	var level Level
-	switch strings.ToLower(levelStr) {
+	if level, err = ParseLevel(r.FormValue("level")); err != nil {
+		buildFailureResp(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	gLog.storeLevel(level)
+	buildSuccessResp(w, "set log level success")
+}
+
+// ParseLevel returns the level of the name, such as debug, the names are the ones taken by SetLogLevel.
+func ParseLevel(name string) (level Level, err error) {
+	switch strings.ToLower(name) {
 	case "debug":
 		level = DebugLevel
 	case "info", "read", "write":
@@ -412,11 +430,43 @@ func SetLogLevel(w http.ResponseWriter, r *http.Request) {
 		level = FatalLevel
 	default:
 		err = fmt.Errorf("level only can be set :debug,info,warn,error,critical,read,write,fatal")
-		buildFailureResp(w, http.StatusBadRequest, err.Error())
+	}
+	return
+}
+
+// GetLevel returns the level of the log, 0 if the log is not initialized.
+func GetLevel() Level {
+	if gLog == nil {
+		return 0
+	}
+	return gLog.loadLevel()
+}
+
+// SetLevel changes the level of the log at runtime.
+func SetLevel(level Level) {
+	if gLog == nil {
 		return
 	}
-	gLog.level = Level(level)
-	buildSuccessResp(w, "set log level success")
+	gLog.storeLevel(level)
+}
+
+// String returns the name of the level as it is set in the config, such as debug.
+func (level Level) String() string {
+	switch level {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	case FatalLevel:
+		return "fatal"
+	case CriticalLevel:
+		return "critical"
+	}
+	return strconv.Itoa(int(level))
 }
 
 func buildSuccessResp(w http.ResponseWriter, data interface{}) {
@@ -455,7 +505,7 @@ func LogWarn(v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if WarnLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); WarnLevel&level != level {
 		return
 	}
 	s := fmt.Sprintln(v...)
@@ -469,7 +519,7 @@ func LogWarnf(format string, v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if WarnLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); WarnLevel&level != level {
 		return
 	}
 	s := fmt.Sprintf(format, v...)
@@ -483,7 +533,7 @@ func LogInfo(v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if InfoLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); InfoLevel&level != level {
 		return
 	}
 	s := fmt.Sprintln(v...)
@@ -496,7 +546,7 @@ func LogInfof(format string, v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if InfoLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); InfoLevel&level != level {
 		return
 	}
 	s := fmt.Sprintf(format, v...)
@@ -509,7 +559,7 @@ func LogError(v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if ErrorLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); ErrorLevel&level != level {
 		return
 	}
 	s := fmt.Sprintln(v...)
@@ -523,7 +573,7 @@ func LogErrorf(format string, v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if ErrorLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); ErrorLevel&level != level {
 		return
 	}
 	s := fmt.Sprintf(format, v...)
@@ -537,7 +587,7 @@ func LogDebug(v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if DebugLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); DebugLevel&level != level {
 		return
 	}
 	s := fmt.Sprintln(v...)
@@ -550,7 +600,7 @@ func LogDebugf(format string, v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if DebugLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); DebugLevel&level != level {
 		return
 	}
 	s := fmt.Sprintf(format, v...)
@@ -607,7 +657,7 @@ func LogRead(v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if ReadLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); ReadLevel&level != level {
 		return
 	}
 	s := fmt.Sprintln(v...)
@@ -620,7 +670,7 @@ func LogReadf(format string, v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if ReadLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); ReadLevel&level != level {
 		return
 	}
 	s := fmt.Sprintf(format, v...)
@@ -633,7 +683,7 @@ func LogWrite(v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if UpdateLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); UpdateLevel&level != level {
 		return
 	}
 	s := fmt.Sprintln(v...)
@@ -646,7 +696,7 @@ func LogWritef(format string, v ...interface{}) {
 	if gLog == nil {
 		return
 	}
-	if UpdateLevel&gLog.level != gLog.level {
+	if level := gLog.loadLevel(); UpdateLevel&level != level {
 		return
 	}
 	s := fmt.Sprintf(format, v...)